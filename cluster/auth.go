@@ -0,0 +1,138 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+var coreAuthRolesObjects = RegisterStmt(`
+SELECT core_auth_roles.name, core_auth_roles.entitlement
+  FROM core_auth_roles
+  ORDER BY core_auth_roles.name, core_auth_roles.entitlement
+`)
+
+var coreAuthBindingsObjects = RegisterStmt(`
+SELECT core_auth_bindings.role, core_auth_bindings.identity_type, core_auth_bindings.identity
+  FROM core_auth_bindings
+  ORDER BY core_auth_bindings.id
+`)
+
+var coreAuthRolesCreate = RegisterStmt(`
+INSERT INTO core_auth_roles (name, entitlement)
+  VALUES (?, ?)
+`)
+
+var coreAuthBindingsCreate = RegisterStmt(`
+INSERT INTO core_auth_bindings (role, identity_type, identity)
+  VALUES (?, ?, ?)
+`)
+
+var coreAuthRolesDelete = RegisterStmt(`
+DELETE FROM core_auth_roles
+`)
+
+var coreAuthBindingsDelete = RegisterStmt(`
+DELETE FROM core_auth_bindings
+`)
+
+// GetCoreAuthPolicy returns the authorization policy.
+func GetCoreAuthPolicy(ctx context.Context, tx *sql.Tx) (*types.AuthPolicy, error) {
+	rolesStmt, err := Stmt(tx, coreAuthRolesObjects)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get \"coreAuthRolesObjects\" prepared statement: %w", err)
+	}
+
+	bindingsStmt, err := Stmt(tx, coreAuthBindingsObjects)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get \"coreAuthBindingsObjects\" prepared statement: %w", err)
+	}
+
+	policy := &types.AuthPolicy{Roles: map[string][]types.Entitlement{}, Bindings: []types.AuthBinding{}}
+	rows, err := rolesStmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"core_auth_roles\" table: %w", err)
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var name string
+		var entitlement types.Entitlement
+		err := rows.Scan(&name, &entitlement)
+		if err != nil {
+			return nil, err
+		}
+
+		policy.Roles[name] = append(policy.Roles[name], entitlement)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = bindingsStmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"core_auth_bindings\" table: %w", err)
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		binding := types.AuthBinding{}
+		err := rows.Scan(&binding.Role, &binding.IdentityType, &binding.Identity)
+		if err != nil {
+			return nil, err
+		}
+
+		policy.Bindings = append(policy.Bindings, binding)
+	}
+
+	return policy, rows.Err()
+}
+
+// ReplaceCoreAuthPolicy replaces the authorization policy with the given one.
+func ReplaceCoreAuthPolicy(ctx context.Context, tx *sql.Tx, policy types.AuthPolicy) error {
+	for _, code := range []int{coreAuthRolesDelete, coreAuthBindingsDelete} {
+		stmt, err := Stmt(tx, code)
+		if err != nil {
+			return fmt.Errorf("Failed to get prepared statement to clear the authorization policy: %w", err)
+		}
+
+		_, err = stmt.ExecContext(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to clear the authorization policy: %w", err)
+		}
+	}
+
+	rolesStmt, err := Stmt(tx, coreAuthRolesCreate)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"coreAuthRolesCreate\" prepared statement: %w", err)
+	}
+
+	for name, entitlements := range policy.Roles {
+		for _, entitlement := range entitlements {
+			_, err = rolesStmt.ExecContext(ctx, name, entitlement)
+			if err != nil {
+				return fmt.Errorf("Failed to create \"core_auth_roles\" entry %q: %w", name, err)
+			}
+		}
+	}
+
+	bindingsStmt, err := Stmt(tx, coreAuthBindingsCreate)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"coreAuthBindingsCreate\" prepared statement: %w", err)
+	}
+
+	for _, binding := range policy.Bindings {
+		_, err = bindingsStmt.ExecContext(ctx, binding.Role, binding.IdentityType, binding.Identity)
+		if err != nil {
+			return fmt.Errorf("Failed to create \"core_auth_bindings\" entry for role %q: %w", binding.Role, err)
+		}
+	}
+
+	return nil
+}
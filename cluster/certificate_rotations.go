@@ -0,0 +1,169 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// Code generation directives.
+//
+//go:generate -command mapper lxd-generate db mapper -t certificate_rotations.mapper.go
+//go:generate mapper reset
+//
+//go:generate mapper stmt -e core_certificate_rotation objects table=core_certificate_rotations
+//go:generate mapper stmt -e core_certificate_rotation objects-by-Fingerprint table=core_certificate_rotations
+//go:generate mapper stmt -e core_certificate_rotation id table=core_certificate_rotations
+//go:generate mapper stmt -e core_certificate_rotation create table=core_certificate_rotations
+//go:generate mapper stmt -e core_certificate_rotation delete-by-Fingerprint table=core_certificate_rotations
+//go:generate mapper stmt -e core_certificate_rotation update table=core_certificate_rotations
+//
+//go:generate mapper method -e core_certificate_rotation GetMany table=core_certificate_rotations
+//go:generate mapper method -e core_certificate_rotation ID table=core_certificate_rotations
+//go:generate mapper method -e core_certificate_rotation Exists table=core_certificate_rotations
+//go:generate mapper method -e core_certificate_rotation Create table=core_certificate_rotations
+//go:generate mapper method -e core_certificate_rotation DeleteOne-by-Fingerprint table=core_certificate_rotations
+//go:generate mapper method -e core_certificate_rotation Update table=core_certificate_rotations
+//
+//go:generate mapper stmt -e core_certificate_rotation_member objects table=core_certificate_rotation_members
+//go:generate mapper stmt -e core_certificate_rotation_member objects-by-RotationID table=core_certificate_rotation_members
+//go:generate mapper stmt -e core_certificate_rotation_member create-or-replace table=core_certificate_rotation_members
+//go:generate mapper stmt -e core_certificate_rotation_member delete-by-RotationID table=core_certificate_rotation_members
+//
+//go:generate mapper method -e core_certificate_rotation_member GetMany table=core_certificate_rotation_members
+//go:generate mapper method -e core_certificate_rotation_member CreateOrReplace table=core_certificate_rotation_members
+//go:generate mapper method -e core_certificate_rotation_member DeleteMany-by-RotationID table=core_certificate_rotation_members
+
+// CoreCertificateRotation is the database representation of a rotation of the cluster certificate.
+// Only the latest rotation is kept.
+//
+// Key holds the private key of the new cluster certificate until every cluster member serves it. It is cleared once
+// the rotation enters its grace period. While stored, it is left out of database dumps and backups, but remains
+// readable with SQL queries from cluster members.
+type CoreCertificateRotation struct {
+	ID                  int
+	Fingerprint         string `db:"primary=yes"`
+	PreviousFingerprint string
+	Certificate         string
+	Key                 string
+	Phase               types.CertificateRotationPhase
+	GracePeriod         time.Duration
+	StartedAt           time.Time
+	SwitchedAt          sql.NullTime
+	CompletedAt         sql.NullTime
+}
+
+// CoreCertificateRotationFilter is the filter struct for filtering results from generated methods.
+type CoreCertificateRotationFilter struct {
+	ID          *int
+	Fingerprint *string
+}
+
+// CoreCertificateRotationMember is the database representation of the latest phase of a rotation of the cluster
+// certificate that a cluster member has acknowledged.
+type CoreCertificateRotationMember struct {
+	ID         int
+	RotationID int    `db:"primary=yes"`
+	Member     string `db:"primary=yes"`
+	Phase      types.CertificateRotationPhase
+}
+
+// CoreCertificateRotationMemberFilter is the filter struct for filtering results from generated methods.
+type CoreCertificateRotationMemberFilter struct {
+	RotationID *int
+	Member     *string
+}
+
+// ToAPI converts the CoreCertificateRotation to an API compatible struct, with the phase acknowledged by each of the
+// named cluster members.
+func (r CoreCertificateRotation) ToAPI(members []string, acks map[string]types.CertificateRotationPhase) types.CertificateRotation {
+	rotation := types.CertificateRotation{
+		Fingerprint:         r.Fingerprint,
+		PreviousFingerprint: r.PreviousFingerprint,
+		Phase:               r.Phase,
+		GracePeriod:         r.GracePeriod,
+		StartedAt:           r.StartedAt,
+		SwitchedAt:          r.SwitchedAt.Time,
+		CompletedAt:         r.CompletedAt.Time,
+		Members:             make([]types.CertificateRotationMember, 0, len(members)),
+	}
+
+	for _, name := range members {
+		rotation.Members = append(rotation.Members, types.CertificateRotationMember{Name: name, Phase: acks[name]})
+	}
+
+	return rotation
+}
+
+// GetCoreCertificateRotation returns the latest rotation of the cluster certificate.
+func GetCoreCertificateRotation(ctx context.Context, tx *sql.Tx) (*CoreCertificateRotation, error) {
+	rotations, err := GetCoreCertificateRotations(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rotations) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "No cluster certificate rotation found")
+	}
+
+	latest := rotations[0]
+	for _, rotation := range rotations[1:] {
+		if rotation.ID > latest.ID {
+			latest = rotation
+		}
+	}
+
+	return &latest, nil
+}
+
+// StartCoreCertificateRotation starts a new rotation of the cluster certificate, replacing the previous rotation.
+// A 409 Conflict error is returned if the previous rotation has neither completed nor been aborted.
+func StartCoreCertificateRotation(ctx context.Context, tx *sql.Tx, rotation CoreCertificateRotation) error {
+	rotations, err := GetCoreCertificateRotations(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, previous := range rotations {
+		if !previous.Phase.Done() {
+			return api.StatusErrorf(http.StatusConflict, "A cluster certificate rotation is already in progress")
+		}
+	}
+
+	for _, previous := range rotations {
+		err = DeleteCoreCertificateRotationMembers(ctx, tx, previous.ID)
+		if err != nil {
+			return err
+		}
+
+		err = DeleteCoreCertificateRotation(ctx, tx, previous.Fingerprint)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = CreateCoreCertificateRotation(ctx, tx, rotation)
+
+	return err
+}
+
+// GetCoreCertificateRotationAcks returns the latest phase of the rotation that each cluster member has acknowledged,
+// keyed by cluster member name.
+func GetCoreCertificateRotationAcks(ctx context.Context, tx *sql.Tx, rotationID int) (map[string]types.CertificateRotationPhase, error) {
+	members, err := GetCoreCertificateRotationMembers(ctx, tx, CoreCertificateRotationMemberFilter{RotationID: &rotationID})
+	if err != nil {
+		return nil, err
+	}
+
+	acks := make(map[string]types.CertificateRotationPhase, len(members))
+	for _, member := range members {
+		acks[member.Member] = member.Phase
+	}
+
+	return acks, nil
+}
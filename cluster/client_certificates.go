@@ -0,0 +1,187 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// Code generation directives.
+//
+//go:generate -command mapper lxd-generate db mapper -t client_certificates.mapper.go
+//go:generate mapper reset
+//
+//go:generate mapper stmt -e core_client_certificate objects table=core_client_certificates
+//go:generate mapper stmt -e core_client_certificate objects-by-Fingerprint table=core_client_certificates
+//go:generate mapper stmt -e core_client_certificate id table=core_client_certificates
+//go:generate mapper stmt -e core_client_certificate create table=core_client_certificates
+//go:generate mapper stmt -e core_client_certificate delete-by-Name table=core_client_certificates
+//
+//go:generate mapper method -e core_client_certificate GetMany table=core_client_certificates
+//go:generate mapper method -e core_client_certificate ID table=core_client_certificates
+//go:generate mapper method -e core_client_certificate Exists table=core_client_certificates
+//go:generate mapper method -e core_client_certificate Create table=core_client_certificates
+//go:generate mapper method -e core_client_certificate DeleteOne-by-Name table=core_client_certificates
+//
+//go:generate mapper stmt -e core_client_token objects table=core_client_tokens
+//go:generate mapper stmt -e core_client_token objects-by-Secret table=core_client_tokens
+//go:generate mapper stmt -e core_client_token id table=core_client_tokens
+//go:generate mapper stmt -e core_client_token create table=core_client_tokens
+//go:generate mapper stmt -e core_client_token delete-by-Name table=core_client_tokens
+//
+//go:generate mapper method -e core_client_token GetMany table=core_client_tokens
+//go:generate mapper method -e core_client_token ID table=core_client_tokens
+//go:generate mapper method -e core_client_token Exists table=core_client_tokens
+//go:generate mapper method -e core_client_token Create table=core_client_tokens
+//go:generate mapper method -e core_client_token DeleteOne-by-Name table=core_client_tokens
+
+// CoreClientCertificate is the database representation of a trusted client certificate.
+type CoreClientCertificate struct {
+	ID          int
+	Name        string `db:"primary=yes"`
+	Fingerprint string
+	Certificate string
+}
+
+// CoreClientCertificateFilter is the filter struct for filtering results from generated methods.
+type CoreClientCertificateFilter struct {
+	ID          *int
+	Name        *string
+	Fingerprint *string
+}
+
+// ToAPI converts the CoreClientCertificate to an API compatible struct.
+func (c CoreClientCertificate) ToAPI() (*types.ClientCertificate, error) {
+	cert, err := types.ParseX509Certificate(c.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse client certificate %q: %w", c.Name, err)
+	}
+
+	return &types.ClientCertificate{Name: c.Name, Fingerprint: c.Fingerprint, Certificate: *cert}, nil
+}
+
+// CoreClientToken is the database representation of a token a client can redeem to have its certificate trusted.
+type CoreClientToken struct {
+	ID         int
+	Name       string `db:"primary=yes"`
+	Secret     string
+	ExpiryDate sql.NullTime
+}
+
+// CoreClientTokenFilter is the filter struct for filtering results from generated methods.
+type CoreClientTokenFilter struct {
+	ID     *int
+	Name   *string
+	Secret *string
+}
+
+// Expired compares the token's expiry date with the current time.
+func (t CoreClientToken) Expired() bool {
+	return t.ExpiryDate.Valid && t.ExpiryDate.Time.Before(time.Now())
+}
+
+// GetCoreClientCertificateByFingerprint returns the trusted client certificate with the given fingerprint.
+func GetCoreClientCertificateByFingerprint(ctx context.Context, tx *sql.Tx, fingerprint string) (*CoreClientCertificate, error) {
+	certs, err := GetCoreClientCertificates(ctx, tx, CoreClientCertificateFilter{Fingerprint: &fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(certs) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Client certificate not found")
+	}
+
+	return &certs[0], nil
+}
+
+// checkCoreClientNameFree returns a 409 Conflict error if a client certificate or token already uses the name.
+func checkCoreClientNameFree(ctx context.Context, tx *sql.Tx, name string) error {
+	for _, exists := range []func(ctx context.Context, tx *sql.Tx, name string) (bool, error){CoreClientCertificateExists, CoreClientTokenExists} {
+		found, err := exists(ctx, tx, name)
+		if err != nil {
+			return fmt.Errorf("Failed to check for existing client certificates and tokens: %w", err)
+		}
+
+		if found {
+			return api.StatusErrorf(http.StatusConflict, "A client certificate or token with name %q already exists", name)
+		}
+	}
+
+	return nil
+}
+
+// TrustCoreClientCertificate trusts the given client certificate. A 409 Conflict error is returned if a client
+// certificate or token already uses its name, or if the certificate is already trusted.
+func TrustCoreClientCertificate(ctx context.Context, tx *sql.Tx, cert CoreClientCertificate) error {
+	err := checkCoreClientNameFree(ctx, tx, cert.Name)
+	if err != nil {
+		return err
+	}
+
+	_, err = GetCoreClientCertificateByFingerprint(ctx, tx, cert.Fingerprint)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "A client certificate with fingerprint %q already exists", cert.Fingerprint)
+	}
+
+	if !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	_, err = CreateCoreClientCertificate(ctx, tx, cert)
+
+	return err
+}
+
+// GetCoreClientTokenBySecret returns the client token with the given secret.
+func GetCoreClientTokenBySecret(ctx context.Context, tx *sql.Tx, secret string) (*CoreClientToken, error) {
+	tokens, err := GetCoreClientTokens(ctx, tx, CoreClientTokenFilter{Secret: &secret})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Client token not found")
+	}
+
+	return &tokens[0], nil
+}
+
+// IssueCoreClientToken records a token that a client can redeem to have its certificate trusted under the token's
+// name. A 409 Conflict error is returned if a client certificate or token already uses the name.
+func IssueCoreClientToken(ctx context.Context, tx *sql.Tx, token CoreClientToken) error {
+	err := checkCoreClientNameFree(ctx, tx, token.Name)
+	if err != nil {
+		return err
+	}
+
+	_, err = CreateCoreClientToken(ctx, tx, token)
+
+	return err
+}
+
+// DeleteExpiredCoreClientTokens cleans up expired client tokens.
+func DeleteExpiredCoreClientTokens(ctx context.Context, tx *sql.Tx) error {
+	tokens, err := GetCoreClientTokens(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		if !token.Expired() {
+			continue
+		}
+
+		err = DeleteCoreClientToken(ctx, tx, token.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
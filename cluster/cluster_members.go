@@ -3,6 +3,8 @@ package cluster
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -47,6 +49,41 @@ type CoreClusterMember struct {
 	APIExtensions  extensions.Extensions
 	Heartbeat      time.Time
 	Role           Role
+	Labels         MemberLabels
+	FailureDomain  string
+	Maintenance    bool
+}
+
+// MemberLabels are the key/value labels of a cluster member, stored as a JSON object.
+type MemberLabels map[string]string
+
+// Value implements the driver.Valuer interface to serialize the labels for database storage.
+func (l MemberLabels) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return "{}", nil
+	}
+
+	return json.Marshal(l)
+}
+
+// Scan implements the sql.Scanner interface to deserialize the labels from database storage.
+func (l *MemberLabels) Scan(value any) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("type assertion to []byte or string failed, incompatible type (%T) for value: %v", value, value)
+	}
+
+	return json.Unmarshal(bytes, l)
 }
 
 // CoreClusterMemberFilter is used for filtering queries using generated methods.
@@ -56,7 +93,7 @@ type CoreClusterMemberFilter struct {
 }
 
 // ToAPI returns the api struct for a ClusterMember database entity.
-// The cluster member's status will be reported as unreachable by default.
+// The cluster member's status will be reported as unreachable by default, or as in maintenance if it has been evacuated.
 func (c CoreClusterMember) ToAPI() (*types.ClusterMember, error) {
 	address, err := types.ParseAddrPort(c.Address)
 	if err != nil {
@@ -68,6 +105,11 @@ func (c CoreClusterMember) ToAPI() (*types.ClusterMember, error) {
 		return nil, fmt.Errorf("Failed to parse certificate of database cluster member with address %q: %w", c.Address, err)
 	}
 
+	status := types.MemberUnreachable
+	if c.Maintenance {
+		status = types.MemberMaintenance
+	}
+
 	return &types.ClusterMember{
 		ClusterMemberLocal: types.ClusterMemberLocal{
 			Name:        c.Name,
@@ -75,10 +117,12 @@ func (c CoreClusterMember) ToAPI() (*types.ClusterMember, error) {
 			Certificate: *certificate,
 		},
 		Role:                  string(c.Role),
+		Labels:                c.Labels,
+		FailureDomain:         c.FailureDomain,
 		SchemaInternalVersion: c.SchemaInternal,
 		SchemaExternalVersion: c.SchemaExternal,
 		LastHeartbeat:         c.Heartbeat,
-		Status:                types.MemberUnreachable,
+		Status:                status,
 		Extensions:            c.APIExtensions,
 	}, nil
 }
@@ -92,33 +136,56 @@ func GetUpgradingClusterMembers(ctx context.Context, tx *sql.Tx, schemaInternal
 		return nil, nil, err
 	}
 
-	// Check for the `api_extensions` column, which may not exist if we haven't actually run the update yet.
+	// Check for columns which may not exist if we haven't actually run the updates adding them yet.
 	stmt := fmt.Sprintf(`
-SELECT count(name)
+SELECT name
 FROM pragma_table_info('%s')
-WHERE name IN ('api_extensions');
+WHERE name IN ('api_extensions', 'labels', 'failure_domain', 'maintenance');
 `, tableName)
 
-	var count int
-	err = tx.QueryRow(stmt).Scan(&count)
+	rows, err := tx.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := map[string]bool{}
+	for rows.Next() {
+		var column string
+		err = rows.Scan(&column)
+		if err != nil {
+			_ = rows.Close()
+			return nil, nil, err
+		}
+
+		columns[column] = true
+	}
+
+	err = rows.Err()
 	if err != nil {
+		_ = rows.Close()
 		return nil, nil, err
 	}
 
+	err = rows.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Substitute the default value of any missing column.
+	fields := []string{"api_extensions", "labels", "failure_domain", "maintenance"}
+	defaults := map[string]string{"api_extensions": "'[]'", "labels": "'{}'", "failure_domain": "''", "maintenance": "0"}
+	for i, field := range fields {
+		if !columns[field] {
+			fields[i] = defaults[field] + " as " + field
+		}
+	}
+
 	// Fetch all cluster members with a smaller schema version than we expect.
-	stmt = `SELECT id, name, address, certificate, schema_internal, schema_external, %s, heartbeat, role
+	stmt = fmt.Sprintf(`SELECT id, name, address, certificate, schema_internal, schema_external, %s, heartbeat, role, %s, %s, %s
   FROM %s
   ORDER BY name
-	`
-
-	// If API extensions are supported, ensure the list for each cluster member also matches what we expect,
-	// and only return cluster members for whom it does not.
-	apiField := "'[]' as api_extensions"
-	if count == 1 {
-		apiField = "api_extensions"
-	}
+	`, fields[0], fields[1], fields[2], fields[3], tableName)
 
-	stmt = fmt.Sprintf(stmt, apiField, tableName)
 	allMembers, err = getCoreClusterMembersRaw(ctx, tx, stmt)
 	if err != nil {
 		return nil, nil, err
@@ -129,7 +196,7 @@ WHERE name IN ('api_extensions');
 		awaitingMembers[member.Name] = member.SchemaInternal < schemaInternal || member.SchemaExternal < schemaExternal
 
 		// If we have API extension support, also compare against the database API extensions.
-		if count == 1 {
+		if columns["api_extensions"] {
 			awaitingMembers[member.Name] = member.APIExtensions.IsSameVersion(apiExtensions) != nil || awaitingMembers[member.Name]
 		}
 	}
@@ -17,20 +17,20 @@ import (
 var _ = api.ServerEnvironment{}
 
 var coreClusterMemberObjects = RegisterStmt(`
-SELECT core_cluster_members.id, core_cluster_members.name, core_cluster_members.address, core_cluster_members.certificate, core_cluster_members.schema_internal, core_cluster_members.schema_external, core_cluster_members.api_extensions, core_cluster_members.heartbeat, core_cluster_members.role
+SELECT core_cluster_members.id, core_cluster_members.name, core_cluster_members.address, core_cluster_members.certificate, core_cluster_members.schema_internal, core_cluster_members.schema_external, core_cluster_members.api_extensions, core_cluster_members.heartbeat, core_cluster_members.role, core_cluster_members.labels, core_cluster_members.failure_domain, core_cluster_members.maintenance
   FROM core_cluster_members
   ORDER BY core_cluster_members.name
 `)
 
 var coreClusterMemberObjectsByAddress = RegisterStmt(`
-SELECT core_cluster_members.id, core_cluster_members.name, core_cluster_members.address, core_cluster_members.certificate, core_cluster_members.schema_internal, core_cluster_members.schema_external, core_cluster_members.api_extensions, core_cluster_members.heartbeat, core_cluster_members.role
+SELECT core_cluster_members.id, core_cluster_members.name, core_cluster_members.address, core_cluster_members.certificate, core_cluster_members.schema_internal, core_cluster_members.schema_external, core_cluster_members.api_extensions, core_cluster_members.heartbeat, core_cluster_members.role, core_cluster_members.labels, core_cluster_members.failure_domain, core_cluster_members.maintenance
   FROM core_cluster_members
   WHERE ( core_cluster_members.address = ? )
   ORDER BY core_cluster_members.name
 `)
 
 var coreClusterMemberObjectsByName = RegisterStmt(`
-SELECT core_cluster_members.id, core_cluster_members.name, core_cluster_members.address, core_cluster_members.certificate, core_cluster_members.schema_internal, core_cluster_members.schema_external, core_cluster_members.api_extensions, core_cluster_members.heartbeat, core_cluster_members.role
+SELECT core_cluster_members.id, core_cluster_members.name, core_cluster_members.address, core_cluster_members.certificate, core_cluster_members.schema_internal, core_cluster_members.schema_external, core_cluster_members.api_extensions, core_cluster_members.heartbeat, core_cluster_members.role, core_cluster_members.labels, core_cluster_members.failure_domain, core_cluster_members.maintenance
   FROM core_cluster_members
   WHERE ( core_cluster_members.name = ? )
   ORDER BY core_cluster_members.name
@@ -42,8 +42,8 @@ SELECT core_cluster_members.id FROM core_cluster_members
 `)
 
 var coreClusterMemberCreate = RegisterStmt(`
-INSERT INTO core_cluster_members (name, address, certificate, schema_internal, schema_external, api_extensions, heartbeat, role)
-  VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO core_cluster_members (name, address, certificate, schema_internal, schema_external, api_extensions, heartbeat, role, labels, failure_domain, maintenance)
+  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `)
 
 var coreClusterMemberDeleteByAddress = RegisterStmt(`
@@ -52,14 +52,14 @@ DELETE FROM core_cluster_members WHERE address = ?
 
 var coreClusterMemberUpdate = RegisterStmt(`
 UPDATE core_cluster_members
-  SET name = ?, address = ?, certificate = ?, schema_internal = ?, schema_external = ?, api_extensions = ?, heartbeat = ?, role = ?
+  SET name = ?, address = ?, certificate = ?, schema_internal = ?, schema_external = ?, api_extensions = ?, heartbeat = ?, role = ?, labels = ?, failure_domain = ?, maintenance = ?
  WHERE id = ?
 `)
 
 // coreClusterMemberColumns returns a string of column names to be used with a SELECT statement for the entity.
 // Use this function when building statements to retrieve database entries matching the CoreClusterMember entity.
 func coreClusterMemberColumns() string {
-	return "core_cluster_members.id, core_cluster_members.name, core_cluster_members.address, core_cluster_members.certificate, core_cluster_members.schema_internal, core_cluster_members.schema_external, core_cluster_members.api_extensions, core_cluster_members.heartbeat, core_cluster_members.role"
+	return "core_cluster_members.id, core_cluster_members.name, core_cluster_members.address, core_cluster_members.certificate, core_cluster_members.schema_internal, core_cluster_members.schema_external, core_cluster_members.api_extensions, core_cluster_members.heartbeat, core_cluster_members.role, core_cluster_members.labels, core_cluster_members.failure_domain, core_cluster_members.maintenance"
 }
 
 // getCoreClusterMembers can be used to run handwritten sql.Stmts to return a slice of objects.
@@ -68,7 +68,7 @@ func getCoreClusterMembers(ctx context.Context, stmt *sql.Stmt, args ...any) ([]
 
 	dest := func(scan func(dest ...any) error) error {
 		c := CoreClusterMember{}
-		err := scan(&c.ID, &c.Name, &c.Address, &c.Certificate, &c.SchemaInternal, &c.SchemaExternal, &c.APIExtensions, &c.Heartbeat, &c.Role)
+		err := scan(&c.ID, &c.Name, &c.Address, &c.Certificate, &c.SchemaInternal, &c.SchemaExternal, &c.APIExtensions, &c.Heartbeat, &c.Role, &c.Labels, &c.FailureDomain, &c.Maintenance)
 		if err != nil {
 			return err
 		}
@@ -92,7 +92,7 @@ func getCoreClusterMembersRaw(ctx context.Context, tx *sql.Tx, sql string, args
 
 	dest := func(scan func(dest ...any) error) error {
 		c := CoreClusterMember{}
-		err := scan(&c.ID, &c.Name, &c.Address, &c.Certificate, &c.SchemaInternal, &c.SchemaExternal, &c.APIExtensions, &c.Heartbeat, &c.Role)
+		err := scan(&c.ID, &c.Name, &c.Address, &c.Certificate, &c.SchemaInternal, &c.SchemaExternal, &c.APIExtensions, &c.Heartbeat, &c.Role, &c.Labels, &c.FailureDomain, &c.Maintenance)
 		if err != nil {
 			return err
 		}
@@ -272,7 +272,7 @@ func CreateCoreClusterMember(ctx context.Context, tx *sql.Tx, object CoreCluster
 		return -1, api.StatusErrorf(http.StatusConflict, "This \"core_cluster_members\" entry already exists")
 	}
 
-	args := make([]any, 8)
+	args := make([]any, 11)
 
 	// Populate the statement arguments.
 	args[0] = object.Name
@@ -283,6 +283,9 @@ func CreateCoreClusterMember(ctx context.Context, tx *sql.Tx, object CoreCluster
 	args[5] = object.APIExtensions
 	args[6] = object.Heartbeat
 	args[7] = object.Role
+	args[8] = object.Labels
+	args[9] = object.FailureDomain
+	args[10] = object.Maintenance
 
 	// Prepared statement to use.
 	stmt, err := Stmt(tx, coreClusterMemberCreate)
@@ -344,7 +347,7 @@ func UpdateCoreClusterMember(ctx context.Context, tx *sql.Tx, name string, objec
 		return fmt.Errorf("Failed to get \"coreClusterMemberUpdate\" prepared statement: %w", err)
 	}
 
-	result, err := stmt.Exec(object.Name, object.Address, object.Certificate, object.SchemaInternal, object.SchemaExternal, object.APIExtensions, object.Heartbeat, object.Role, id)
+	result, err := stmt.Exec(object.Name, object.Address, object.Certificate, object.SchemaInternal, object.SchemaExternal, object.APIExtensions, object.Heartbeat, object.Role, object.Labels, object.FailureDomain, object.Maintenance, id)
 	if err != nil {
 		return fmt.Errorf("Update \"core_cluster_members\" entry failed: %w", err)
 	}
@@ -0,0 +1,121 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+const (
+	// HeartbeatIntervalKey is the cluster-wide config key for the interval between heartbeats.
+	HeartbeatIntervalKey = "core.heartbeat_interval"
+
+	// RoleProbeIntervalKey is the cluster-wide config key for the interval between dqlite role probes.
+	RoleProbeIntervalKey = "core.role_probe_interval"
+)
+
+// Code generation directives.
+//
+//go:generate -command mapper lxd-generate db mapper -t config.mapper.go
+//go:generate mapper reset
+//
+//go:generate mapper stmt -e core_config_key objects table=core_config
+//go:generate mapper stmt -e core_config_key objects-by-Member table=core_config
+//go:generate mapper stmt -e core_config_key create-or-replace table=core_config
+//go:generate mapper stmt -e core_config_key delete-by-Key-and-Member table=core_config
+//go:generate mapper stmt -e core_config_key delete-by-Member table=core_config
+//
+//go:generate mapper method -e core_config_key GetMany table=core_config
+//go:generate mapper method -e core_config_key CreateOrReplace table=core_config
+//go:generate mapper method -e core_config_key DeleteOne-by-Key-and-Member table=core_config
+//go:generate mapper method -e core_config_key DeleteMany-by-Member table=core_config
+
+// CoreConfigKey is the database representation of a config key. Cluster-wide keys have an empty member name.
+type CoreConfigKey struct {
+	ID     int
+	Key    string `db:"primary=yes"`
+	Value  string
+	Member string `db:"primary=yes"`
+}
+
+// CoreConfigKeyFilter is the filter struct for filtering results from generated methods.
+type CoreConfigKeyFilter struct {
+	Key    *string
+	Member *string
+}
+
+// GetCoreConfig returns all cluster-wide config keys and their values.
+func GetCoreConfig(ctx context.Context, tx *sql.Tx) (map[string]string, error) {
+	return GetCoreMemberConfig(ctx, tx, "")
+}
+
+// GetCoreMemberConfig returns all config keys set for the named cluster member and their values.
+func GetCoreMemberConfig(ctx context.Context, tx *sql.Tx, member string) (map[string]string, error) {
+	keys, err := GetCoreConfigKeys(ctx, tx, CoreConfigKeyFilter{Member: &member})
+	if err != nil {
+		return nil, err
+	}
+
+	config := make(map[string]string, len(keys))
+	for _, key := range keys {
+		config[key.Key] = key.Value
+	}
+
+	return config, nil
+}
+
+// GetCoreMembersConfig returns the config keys set for each cluster member and their values, keyed by cluster member name.
+func GetCoreMembersConfig(ctx context.Context, tx *sql.Tx) (map[string]map[string]string, error) {
+	keys, err := GetCoreConfigKeys(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	config := map[string]map[string]string{}
+	for _, key := range keys {
+		if key.Member == "" {
+			continue
+		}
+
+		if config[key.Member] == nil {
+			config[key.Member] = map[string]string{}
+		}
+
+		config[key.Member][key.Key] = key.Value
+	}
+
+	return config, nil
+}
+
+// UpdateCoreConfig sets the given cluster-wide config keys. Keys with an empty value are unset.
+func UpdateCoreConfig(ctx context.Context, tx *sql.Tx, config map[string]string) error {
+	return UpdateCoreMemberConfig(ctx, tx, "", config)
+}
+
+// UpdateCoreMemberConfig sets the given config keys for the named cluster member. Keys with an empty value are unset.
+func UpdateCoreMemberConfig(ctx context.Context, tx *sql.Tx, member string, config map[string]string) error {
+	for key, value := range config {
+		if value == "" {
+			err := DeleteCoreConfigKey(ctx, tx, key, member)
+			if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+
+			continue
+		}
+
+		_, err := CreateOrReplaceCoreConfigKey(ctx, tx, CoreConfigKey{Key: key, Value: value, Member: member})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteCoreMemberConfig unsets all config keys of the named cluster member.
+func DeleteCoreMemberConfig(ctx context.Context, tx *sql.Tx, member string) error {
+	return DeleteCoreConfigKeys(ctx, tx, member)
+}
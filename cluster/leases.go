@@ -0,0 +1,39 @@
+package cluster
+
+import (
+	"time"
+)
+
+// Code generation directives.
+//
+//go:generate -command mapper lxd-generate db mapper -t leases.mapper.go
+//go:generate mapper reset
+//
+//go:generate mapper stmt -e core_lease objects table=core_leases
+//go:generate mapper stmt -e core_lease objects-by-Name table=core_leases
+//go:generate mapper stmt -e core_lease id table=core_leases
+//go:generate mapper stmt -e core_lease create table=core_leases
+//go:generate mapper stmt -e core_lease update table=core_leases
+//
+//go:generate mapper method -e core_lease GetMany table=core_leases
+//go:generate mapper method -e core_lease GetOne table=core_leases
+//go:generate mapper method -e core_lease ID table=core_leases
+//go:generate mapper method -e core_lease Exists table=core_leases
+//go:generate mapper method -e core_lease Create table=core_leases
+//go:generate mapper method -e core_lease Update table=core_leases
+
+// CoreLease is the database representation of a cluster-wide lease.
+// The token is incremented each time the lease changes hands, so it can be used as a fencing token.
+type CoreLease struct {
+	ID         int
+	Name       string `db:"primary=yes"`
+	Holder     string
+	Token      int64
+	ExpiryDate time.Time
+}
+
+// CoreLeaseFilter is the filter struct for filtering results from generated methods.
+type CoreLeaseFilter struct {
+	ID   *int
+	Name *string
+}
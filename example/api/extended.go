@@ -19,7 +19,8 @@ import (
 // This is an example extended endpoint reachable at /1.0/extended.
 var extendedCmd = rest.Endpoint{
 	Path: "extended",
-	Post: rest.EndpointAction{Handler: cmdPost, AllowUntrusted: true},
+	// The summary and the request and response types describe the endpoint in the OpenAPI document served to trusted clients at /openapi.json.
+	Post: rest.EndpointAction{Handler: cmdPost, AllowUntrusted: true, Summary: "Send a message to every cluster member", Request: extendedTypes.ExtendedType{}, Response: ""},
 }
 
 // This is the POST handler for the /1.0/extended endpoint.
@@ -2,8 +2,15 @@
 package api
 
 import (
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/logger"
+
 	"github.com/canonical/microcluster/v2/example/api/types"
 	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/state"
 )
 
 // Servers represents the list of listeners that the daemon will start
@@ -11,8 +18,9 @@ import (
 // If the Server is marked as CoreAPI, its endpoints will be added to the core listener of Microcluster.
 var Servers = map[string]rest.Server{
 	"extended": {
-		CoreAPI:   true,
-		ServeUnix: true,
+		CoreAPI:     true,
+		ServeUnix:   true,
+		Middlewares: []rest.Middleware{logRequests},
 		Resources: []rest.Resources{
 			{
 				PathPrefix: types.ExtendedPathPrefix,
@@ -23,3 +31,14 @@ var Servers = map[string]rest.Server{
 		},
 	},
 }
+
+// logRequests is a middleware that logs each request to the endpoints of the server, along with how long it took.
+func logRequests(next rest.Handler) rest.Handler {
+	return func(s state.State, r *http.Request) response.Response {
+		start := time.Now()
+		resp := next(s, r)
+		logger.Debug("Handled request", logger.Ctx{"method": r.Method, "url": r.URL.String(), "duration": time.Since(start)})
+
+		return resp
+	}
+}
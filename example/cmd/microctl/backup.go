@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcluster/v2/microcluster"
+)
+
+type cmdBackup struct {
+	common *CmdControl
+}
+
+func (c *cmdBackup) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup <file>",
+		Short: "Save a snapshot of the database to a tarball",
+		RunE:  c.run,
+	}
+
+	return cmd
+}
+
+func (c *cmdBackup) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	m, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagStateDir})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(args[0], os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed to create backup file: %w", err)
+	}
+
+	err = m.Backup(cmd.Context(), file)
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(args[0])
+
+		return err
+	}
+
+	return file.Close()
+}
+
+type cmdRestore struct {
+	common *CmdControl
+}
+
+func (c *cmdRestore) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <file>",
+		Short: "Replace the contents of the database with a snapshot from a backup tarball",
+		RunE:  c.run,
+	}
+
+	return cmd
+}
+
+func (c *cmdRestore) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	m, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagStateDir})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("Failed to open backup file: %w", err)
+	}
+
+	defer file.Close()
+
+	return m.Restore(cmd.Context(), file)
+}
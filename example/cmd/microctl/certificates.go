@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	cli "github.com/canonical/lxd/shared/cmd"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcluster/v2/microcluster"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type cmdCertificates struct {
+	common *CmdControl
+}
+
+func (c *cmdCertificates) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certificates",
+		Short: "Manage the cluster certificate",
+		RunE:  c.run,
+	}
+
+	var cmdRotate = cmdCertificatesRotate{common: c.common}
+	cmd.AddCommand(cmdRotate.command())
+
+	var cmdRotation = cmdCertificatesRotation{common: c.common}
+	cmd.AddCommand(cmdRotation.command())
+
+	var cmdAbort = cmdCertificatesAbort{common: c.common}
+	cmd.AddCommand(cmdAbort.command())
+
+	return cmd
+}
+
+func (c *cmdCertificates) run(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
+
+type cmdCertificatesRotate struct {
+	common *CmdControl
+
+	flagGracePeriod string
+}
+
+func (c *cmdCertificatesRotate) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate <cert> <key>",
+		Short: "Rotate the cluster certificate to the keypair in the given PEM files",
+		RunE:  c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagGracePeriod, "grace-period", "g", "1h", "Set how long the previous certificate is trusted once every member serves the new one")
+
+	return cmd
+}
+
+func (c *cmdCertificatesRotate) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Help()
+	}
+
+	gracePeriod, err := time.ParseDuration(c.flagGracePeriod)
+	if err != nil {
+		return fmt.Errorf("Invalid value for grace-period: %w", err)
+	}
+
+	cert, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	key, err := os.ReadFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	m, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagStateDir})
+	if err != nil {
+		return err
+	}
+
+	client, err := m.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	rotation, err := client.RotateClusterCertificate(cmd.Context(), types.CertificateRotationPost{Cert: string(cert), Key: string(key), GracePeriod: gracePeriod})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Started rotation to cluster certificate %q, now in phase %q\n", rotation.Fingerprint, rotation.Phase)
+
+	return nil
+}
+
+type cmdCertificatesRotation struct {
+	common *CmdControl
+
+	flagFormat string
+}
+
+func (c *cmdCertificatesRotation) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotation",
+		Short: "Show the progress of the latest cluster certificate rotation",
+		RunE:  c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", cli.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+func (c *cmdCertificatesRotation) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	m, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagStateDir})
+	if err != nil {
+		return err
+	}
+
+	client, err := m.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	rotation, err := client.GetCertificateRotation(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if c.flagFormat == cli.TableFormatTable {
+		fmt.Printf("Rotation from %q to %q is in phase %q\n", rotation.PreviousFingerprint, rotation.Fingerprint, rotation.Phase)
+	}
+
+	data := make([][]string, len(rotation.Members))
+	for i, member := range rotation.Members {
+		data[i] = []string{member.Name, string(member.Phase)}
+	}
+
+	header := []string{"NAME", "ACKNOWLEDGED PHASE"}
+
+	return cli.RenderTable(c.flagFormat, header, data, rotation)
+}
+
+type cmdCertificatesAbort struct {
+	common *CmdControl
+}
+
+func (c *cmdCertificatesAbort) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "abort",
+		Short: "Abort the latest cluster certificate rotation, before the previous certificate is retired",
+		RunE:  c.run,
+	}
+
+	return cmd
+}
+
+func (c *cmdCertificatesAbort) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	m, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagStateDir})
+	if err != nil {
+		return err
+	}
+
+	client, err := m.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	rotation, err := client.AbortCertificateRotation(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Aborting rotation to cluster certificate %q, now in phase %q\n", rotation.Fingerprint, rotation.Phase)
+
+	return nil
+}
@@ -62,6 +62,18 @@ func (c *cmdClusterMembers) command() *cobra.Command {
 	var cmdRestore = cmdClusterEdit{common: c.common}
 	cmd.AddCommand(cmdRestore.command())
 
+	var cmdPushRecovery = cmdClusterPushRecovery{common: c.common}
+	cmd.AddCommand(cmdPushRecovery.command())
+
+	var cmdSetRole = cmdClusterMemberSetRole{common: c.common}
+	cmd.AddCommand(cmdSetRole.command())
+
+	var cmdEvacuate = cmdClusterMemberEvacuate{common: c.common}
+	cmd.AddCommand(cmdEvacuate.command())
+
+	var cmdRestoreMember = cmdClusterMemberRestore{common: c.common}
+	cmd.AddCommand(cmdRestoreMember.command())
+
 	return cmd
 }
 
@@ -130,10 +142,22 @@ func (c *cmdClusterMembersList) listClusterMembers(ctx context.Context, client *
 
 	data := make([][]string, len(clusterMembers))
 	for i, clusterMember := range clusterMembers {
-		data[i] = []string{clusterMember.Name, clusterMember.Address.String(), clusterMember.Role, shared.CertFingerprint(clusterMember.Certificate.Certificate), string(clusterMember.Status)}
+		labels := make([]string, 0, len(clusterMember.Labels))
+		for key, value := range clusterMember.Labels {
+			labels = append(labels, key+"="+value)
+		}
+
+		sort.Strings(labels)
+
+		role := clusterMember.Role
+		if clusterMember.Leader {
+			role += " (leader)"
+		}
+
+		data[i] = []string{clusterMember.Name, clusterMember.Address.String(), role, clusterMember.FailureDomain, strings.Join(labels, "\n"), shared.CertFingerprint(clusterMember.Certificate.Certificate), string(clusterMember.Status)}
 	}
 
-	header := []string{"NAME", "ADDRESS", "ROLE", "FINGERPRINT", "STATUS"}
+	header := []string{"NAME", "ADDRESS", "ROLE", "FAILURE DOMAIN", "LABELS", "FINGERPRINT", "STATUS"}
 	sort.Sort(cli.SortColumnsNaturally(data))
 
 	return cli.RenderTable(c.flagFormat, header, data, clusterMembers)
@@ -197,8 +221,106 @@ func (c *cmdClusterMemberRemove) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+type cmdClusterMemberSetRole struct {
+	common *CmdControl
+}
+
+func (c *cmdClusterMemberSetRole) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-role <name> <voter|stand-by|spare>",
+		Short: "Assign a dqlite role to the cluster member with the given name.",
+		RunE:  c.run,
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterMemberSetRole) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Help()
+	}
+
+	m, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagStateDir})
+	if err != nil {
+		return err
+	}
+
+	client, err := m.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	return client.SetClusterMemberRole(cmd.Context(), args[0], args[1])
+}
+
+type cmdClusterMemberEvacuate struct {
+	common *CmdControl
+}
+
+func (c *cmdClusterMemberEvacuate) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "evacuate <name>",
+		Short: "Put the cluster member with the given name into maintenance.",
+		RunE:  c.run,
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterMemberEvacuate) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	m, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagStateDir})
+	if err != nil {
+		return err
+	}
+
+	client, err := m.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	return client.EvacuateClusterMember(cmd.Context(), args[0])
+}
+
+type cmdClusterMemberRestore struct {
+	common *CmdControl
+}
+
+func (c *cmdClusterMemberRestore) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <name>",
+		Short: "Take the cluster member with the given name out of maintenance.",
+		RunE:  c.run,
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterMemberRestore) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	m, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagStateDir})
+	if err != nil {
+		return err
+	}
+
+	client, err := m.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	return client.RestoreClusterMember(cmd.Context(), args[0])
+}
+
 type cmdClusterEdit struct {
 	common *CmdControl
+
+	flagDryRun bool
 }
 
 func (c *cmdClusterEdit) command() *cobra.Command {
@@ -208,6 +330,8 @@ func (c *cmdClusterEdit) command() *cobra.Command {
 		RunE:  c.run,
 	}
 
+	cmd.Flags().BoolVar(&c.flagDryRun, "dry-run", false, "Show the changes that recovery would make without applying them")
+
 	return cmd
 }
 
@@ -235,14 +359,16 @@ func (c *cmdClusterEdit) run(cmd *cobra.Command, args []string) error {
 		}
 	} else {
 		reader := bufio.NewReader(os.Stdin)
-		fmt.Print(recoveryConfirmation)
+		if !c.flagDryRun {
+			fmt.Print(recoveryConfirmation)
 
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSuffix(input, "\n")
+			input, _ := reader.ReadString('\n')
+			input = strings.TrimSuffix(input, "\n")
 
-		if strings.ToLower(input) != "yes" {
-			fmt.Println("Cluster edit aborted; no changes made")
-			return nil
+			if strings.ToLower(input) != "yes" {
+				fmt.Println("Cluster edit aborted; no changes made")
+				return nil
+			}
 		}
 
 		content, err = shared.TextEditor("", append([]byte(recoveryYamlComment), membersYaml...))
@@ -257,14 +383,80 @@ func (c *cmdClusterEdit) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if c.flagDryRun {
+		plan, err := m.PlanRecovery(newMembers)
+		if err != nil {
+			return fmt.Errorf("cluster edit: %w", err)
+		}
+
+		planYaml, err := yaml.Marshal(plan)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(string(planYaml))
+
+		return nil
+	}
+
 	tarballPath, err := m.RecoverFromQuorumLoss(newMembers)
 	if err != nil {
 		return fmt.Errorf("cluster edit: %w", err)
 	}
 
 	fmt.Printf("Cluster changes applied; new database state saved to %s\n\n", tarballPath)
-	fmt.Printf("*Before* starting any cluster member, copy %s to %s on all remaining cluster members.\n\n", tarballPath, tarballPath)
+	fmt.Printf("*Before* starting any cluster member, copy %s to %s on all remaining cluster members.\n", tarballPath, tarballPath)
+	fmt.Printf("Alternatively, start the remaining cluster members and run \"microctl cluster push-recovery %s\", then restart them.\n\n", tarballPath)
 	fmt.Printf("microd will load this file during startup.\n")
 
 	return nil
 }
+
+type cmdClusterPushRecovery struct {
+	common *CmdControl
+}
+
+func (c *cmdClusterPushRecovery) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "push-recovery <tarball>",
+		Short: "Send the recovery tarball created by \"cluster edit\" to all other cluster members",
+		RunE:  c.run,
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterPushRecovery) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	m, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagStateDir})
+	if err != nil {
+		return err
+	}
+
+	results, err := m.PushRecoveryTarball(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+			fmt.Printf("%s (%s): %s\n", result.Name, result.Address, result.Error)
+			continue
+		}
+
+		fmt.Printf("%s (%s): recovery tarball staged\n", result.Name, result.Address)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Failed to push recovery tarball to %d of %d cluster members", failed, len(results))
+	}
+
+	fmt.Printf("\nRestart the other cluster members to load the recovery tarball.\n")
+
+	return nil
+}
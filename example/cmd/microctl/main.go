@@ -53,12 +53,24 @@ func main() {
 	var cmdSQL = cmdSQL{common: &commonCmd}
 	app.AddCommand(cmdSQL.command())
 
+	var cmdBackup = cmdBackup{common: &commonCmd}
+	app.AddCommand(cmdBackup.command())
+
+	var cmdRestore = cmdRestore{common: &commonCmd}
+	app.AddCommand(cmdRestore.command())
+
 	var cmdSecrets = cmdSecrets{common: &commonCmd}
 	app.AddCommand(cmdSecrets.command())
 
+	var cmdCertificates = cmdCertificates{common: &commonCmd}
+	app.AddCommand(cmdCertificates.command())
+
 	var cmdWaitready = cmdWaitready{common: &commonCmd}
 	app.AddCommand(cmdWaitready.command())
 
+	var cmdOpenAPI = cmdOpenAPI{common: &commonCmd}
+	app.AddCommand(cmdOpenAPI.command())
+
 	var cmdExtended = cmdExtended{common: &commonCmd}
 	app.AddCommand(cmdExtended.command())
 
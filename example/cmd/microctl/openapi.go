@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcluster/v2/microcluster"
+)
+
+type cmdOpenAPI struct {
+	common *CmdControl
+
+	flagListener string
+}
+
+func (c *cmdOpenAPI) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "openapi",
+		Short: "Print the OpenAPI document describing the API of a listener",
+		RunE:  c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagListener, "listener", "l", "core", "Listener to describe: core, unix, or the name of an additional listener"+"``")
+
+	return cmd
+}
+
+func (c *cmdOpenAPI) run(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return cmd.Help()
+	}
+
+	m, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagStateDir})
+	if err != nil {
+		return err
+	}
+
+	doc, err := m.OpenAPI(cmd.Context(), c.flagListener)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(doc)
+}
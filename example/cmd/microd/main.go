@@ -3,7 +3,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"os"
+	"time"
 
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/spf13/cobra"
@@ -42,8 +44,9 @@ func (c *cmdGlobal) run(cmd *cobra.Command, args []string) error {
 type cmdDaemon struct {
 	global *cmdGlobal
 
-	flagStateDir    string
-	flagSocketGroup string
+	flagStateDir        string
+	flagSocketGroup     string
+	flagAutoRemoveAfter time.Duration
 }
 
 func (c *cmdDaemon) command() *cobra.Command {
@@ -74,6 +77,15 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 
 		SocketGroup: c.flagSocketGroup,
 
+		AutoRemove: state.AutoRemovePolicy{After: c.flagAutoRemoveAfter, MinMembers: 3},
+
+		// ConfigKeys can be set through the cluster config API, and read by any cluster member with s.Config().
+		ConfigKeys: map[string]state.ConfigKey{
+			"example.greeting":    {Type: state.ConfigTypeString, Default: "hello"},
+			"example.max_widgets": {Type: state.ConfigTypeInt, Default: "10"},
+			"example.debug":       {Type: state.ConfigTypeBool, Scope: state.ConfigScopeMember},
+		},
+
 		ExtensionsSchema: database.SchemaExtensions,
 		APIExtensions:    api.Extensions(),
 		ExtensionServers: api.Servers,
@@ -166,6 +178,20 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 			return nil
 		},
 
+		// PreEvacuate is run before the daemon is put into maintenance.
+		PreEvacuate: func(ctx context.Context, s state.State) error {
+			logger.Infof("This is a hook that is run on peer %q before it is evacuated", s.Name())
+
+			return nil
+		},
+
+		// PostRestore is run after the daemon is taken out of maintenance.
+		PostRestore: func(ctx context.Context, s state.State) error {
+			logger.Infof("This is a hook that is run on peer %q after it is restored", s.Name())
+
+			return nil
+		},
+
 		// OnHeartbeat is run after a successful heartbeat round.
 		OnHeartbeat: func(ctx context.Context, s state.State) error {
 			logger.Info("This is a hook that is run on the dqlite leader after a successful heartbeat")
@@ -180,6 +206,48 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 			return nil
 		},
 
+		// OnMemberOffline is run after a cluster member stops answering heartbeats.
+		OnMemberOffline: func(ctx context.Context, s state.State, member types.ClusterMemberLocal) error {
+			logger.Infof("This is a hook that is run on peer %q when cluster member %q goes offline", s.Name(), member.Name)
+
+			return nil
+		},
+
+		// OnMemberOnline is run after a cluster member answers heartbeats again.
+		OnMemberOnline: func(ctx context.Context, s state.State, member types.ClusterMemberLocal) error {
+			logger.Infof("This is a hook that is run on peer %q when cluster member %q comes back online", s.Name(), member.Name)
+
+			return nil
+		},
+
+		// OnLeaderChange is run after a cluster member learns that the dqlite leader has changed.
+		OnLeaderChange: func(ctx context.Context, s state.State, oldLeader string, newLeader string) error {
+			logger.Infof("This is a hook that is run on peer %q when the dqlite leader changes from %q to %q", s.Name(), oldLeader, newLeader)
+
+			return nil
+		},
+
+		// HeartbeatData is run when a cluster member answers a heartbeat, and contributes data to share with the cluster.
+		HeartbeatData: func(ctx context.Context, s state.State) (json.RawMessage, error) {
+			return json.Marshal(map[string]string{"version": s.Version()})
+		},
+
+		// OnHeartbeatData is run after a cluster member receives the heartbeat data of the whole cluster.
+		OnHeartbeatData: func(ctx context.Context, s state.State, data map[string]json.RawMessage) error {
+			logger.Debugf("This is a hook that is run on peer %q after receiving heartbeat data from %d cluster members", s.Name(), len(data))
+
+			return nil
+		},
+
+		// OnConfigChange is run on each cluster member after cluster config keys have changed.
+		OnConfigChange: func(ctx context.Context, s state.State, changes []types.ConfigChange) error {
+			for _, change := range changes {
+				logger.Infof("This is a hook that is run on peer %q when config key %q changes to %q", s.Name(), change.Key, change.Value)
+			}
+
+			return nil
+		},
+
 		// OnDaemonConfigUpdate is run after the local daemon config of a cluster member got modified.
 		OnDaemonConfigUpdate: func(ctx context.Context, s state.State, config types.DaemonConfig) error {
 			logger.Infof("Running OnDaemonConfigUpdate triggered by %q", config.Name)
@@ -204,6 +272,7 @@ func main() {
 
 	app.PersistentFlags().StringVar(&daemonCmd.flagStateDir, "state-dir", "", "Path to store state information"+"``")
 	app.PersistentFlags().StringVar(&daemonCmd.flagSocketGroup, "socket-group", "", "Group to set socket's group ownership to")
+	app.PersistentFlags().DurationVar(&daemonCmd.flagAutoRemoveAfter, "auto-remove-after", 0, "Remove cluster members that miss heartbeats for this long (disabled if 0)")
 
 	app.SetVersionTemplate("{{.Version}}\n")
 
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// policyCacheExpiry is how long a policy loaded from the database is used before it is loaded again,
+// so that changes made through other cluster members are picked up.
+const policyCacheExpiry = 10 * time.Second
+
+// DefaultPolicy returns the authorization policy seeded in the database, which binds the admin role to every unix
+// socket peer.
+func DefaultPolicy() types.AuthPolicy {
+	return types.AuthPolicy{
+		Roles:    map[string][]types.Entitlement{},
+		Bindings: []types.AuthBinding{{Role: types.AuthRoleAdmin, IdentityType: types.IdentityTypeUnix, Identity: "*"}},
+	}
+}
+
+// PolicyCache holds the authorization policy last loaded from the database, so that it isn't loaded for each request
+// and remains available while the database is not.
+type PolicyCache struct {
+	mu       sync.Mutex
+	policy   *types.AuthPolicy
+	loadedAt time.Time
+}
+
+// Get returns the cached policy, loading it with the given function first if it has expired.
+func (c *PolicyCache) Get(load func() (*types.AuthPolicy, error)) (types.AuthPolicy, error) {
+	c.mu.Lock()
+	if c.policy != nil && time.Since(c.loadedAt) < policyCacheExpiry {
+		policy := *c.policy
+		c.mu.Unlock()
+
+		return policy, nil
+	}
+
+	c.mu.Unlock()
+
+	policy, err := load()
+	if err != nil {
+		return types.AuthPolicy{}, err
+	}
+
+	c.Set(*policy)
+
+	return *policy, nil
+}
+
+// Last returns the policy last loaded from the database, regardless of its age, or the default policy if it was never
+// loaded. It is used while the database is unavailable.
+func (c *PolicyCache) Last() types.AuthPolicy {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.policy == nil {
+		return DefaultPolicy()
+	}
+
+	return *c.policy
+}
+
+// Set replaces the cached policy, such as after it was updated through this cluster member.
+func (c *PolicyCache) Set(policy types.AuthPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.policy = &policy
+	c.loadedAt = time.Now()
+}
@@ -0,0 +1,181 @@
+// Package auth evaluates the authorization policy of the cluster.
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// builtinRoles are the roles that are always defined, and cannot be redefined by the policy.
+var builtinRoles = map[string][]types.Entitlement{
+	types.AuthRoleAdmin:  {types.EntitlementAll},
+	types.AuthRoleViewer: {"*:read"},
+}
+
+// secretEntitlements are the entitlements that expose secrets, such as join token secrets or the database contents.
+// They are not matched by entitlements with a "*" object, so that the viewer role and similar roles don't grant them.
+var secretEntitlements = map[types.Entitlement]bool{
+	types.EntitlementTokensRead:   true,
+	types.EntitlementDatabaseRead: true,
+}
+
+// Validate checks that the policy only grants defined roles, and that its entitlements and identities are well-formed.
+func Validate(policy types.AuthPolicy) error {
+	for role, entitlements := range policy.Roles {
+		_, ok := builtinRoles[role]
+		if ok {
+			return api.StatusErrorf(http.StatusBadRequest, "Role %q is built-in and cannot be redefined", role)
+		}
+
+		if role == "" {
+			return api.StatusErrorf(http.StatusBadRequest, "Roles must have a name")
+		}
+
+		if len(entitlements) == 0 {
+			return api.StatusErrorf(http.StatusBadRequest, "Role %q must grant at least one entitlement", role)
+		}
+
+		seen := make(map[types.Entitlement]bool, len(entitlements))
+		for _, entitlement := range entitlements {
+			err := validateEntitlement(entitlement)
+			if err != nil {
+				return api.StatusErrorf(http.StatusBadRequest, "Invalid entitlement %q of role %q: %v", entitlement, role, err)
+			}
+
+			if seen[entitlement] {
+				return api.StatusErrorf(http.StatusBadRequest, "Role %q grants entitlement %q more than once", role, entitlement)
+			}
+
+			seen[entitlement] = true
+		}
+	}
+
+	seen := make(map[types.AuthBinding]bool, len(policy.Bindings))
+	for _, binding := range policy.Bindings {
+		if seen[binding] {
+			return api.StatusErrorf(http.StatusBadRequest, "Role %q is bound to %s identity %q more than once", binding.Role, binding.IdentityType, binding.Identity)
+		}
+
+		seen[binding] = true
+
+		_, builtin := builtinRoles[binding.Role]
+		_, custom := policy.Roles[binding.Role]
+		if !builtin && !custom {
+			return api.StatusErrorf(http.StatusBadRequest, "Binding refers to unknown role %q", binding.Role)
+		}
+
+		err := validateIdentity(binding.IdentityType, binding.Identity)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid %s identity %q bound to role %q: %v", binding.IdentityType, binding.Identity, binding.Role, err)
+		}
+	}
+
+	return nil
+}
+
+// validateEntitlement checks that the entitlement is "*" or of the form "<object>:<action>".
+func validateEntitlement(entitlement types.Entitlement) error {
+	if entitlement == types.EntitlementAll {
+		return nil
+	}
+
+	object, action, ok := strings.Cut(string(entitlement), ":")
+	if !ok || object == "" || action == "" || strings.Contains(action, ":") {
+		return fmt.Errorf("Entitlements must be of the form \"<object>:<action>\"")
+	}
+
+	return nil
+}
+
+// validateIdentity checks that the identity pattern of a binding is valid for the identity type.
+func validateIdentity(identityType types.IdentityType, identity string) error {
+	if identity == "" {
+		return fmt.Errorf("Identity must not be empty")
+	}
+
+	switch identityType {
+	case types.IdentityTypeCertificate:
+		return nil
+	case types.IdentityTypeUnix:
+		if identity == "*" {
+			return nil
+		}
+
+		kind, id, ok := strings.Cut(identity, ":")
+		if !ok || (kind != "uid" && kind != "gid") {
+			return fmt.Errorf("Unix identities must be \"*\", \"uid:<uid>\" or \"gid:<gid>\"")
+		}
+
+		_, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			return fmt.Errorf("Invalid %s %q: %w", kind, id, err)
+		}
+
+		return nil
+	case types.IdentityTypeClusterMember:
+		return fmt.Errorf("Cluster members are always granted every entitlement")
+	default:
+		return fmt.Errorf("Unknown identity type")
+	}
+}
+
+// Allowed returns whether the policy grants the entitlement to the identity.
+func Allowed(policy types.AuthPolicy, identity types.Identity, entitlement types.Entitlement) bool {
+	for _, binding := range policy.Bindings {
+		if !matchIdentity(binding, identity) {
+			continue
+		}
+
+		entitlements, ok := builtinRoles[binding.Role]
+		if !ok {
+			entitlements = policy.Roles[binding.Role]
+		}
+
+		for _, granted := range entitlements {
+			if matchEntitlement(granted, entitlement) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchIdentity returns whether the binding applies to the identity.
+func matchIdentity(binding types.AuthBinding, identity types.Identity) bool {
+	if binding.IdentityType != identity.Type {
+		return false
+	}
+
+	if binding.Identity == "*" {
+		return true
+	}
+
+	switch identity.Type {
+	case types.IdentityTypeUnix:
+		return binding.Identity == fmt.Sprintf("uid:%d", identity.UID) || binding.Identity == fmt.Sprintf("gid:%d", identity.GID)
+	default:
+		return binding.Identity == identity.Name
+	}
+}
+
+// matchEntitlement returns whether the entitlement granted by a role, which may contain wildcards, covers the required one.
+func matchEntitlement(granted types.Entitlement, required types.Entitlement) bool {
+	if granted == types.EntitlementAll || granted == required {
+		return true
+	}
+
+	grantedObject, grantedAction, _ := strings.Cut(string(granted), ":")
+	requiredObject, requiredAction, _ := strings.Cut(string(required), ":")
+	if grantedObject == "*" && secretEntitlements[required] {
+		return false
+	}
+
+	return (grantedObject == "*" || grantedObject == requiredObject) && (grantedAction == "*" || grantedAction == requiredAction)
+}
@@ -0,0 +1,267 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/db/update"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type policySuite struct {
+	suite.Suite
+}
+
+func TestPolicySuite(t *testing.T) {
+	suite.Run(t, new(policySuite))
+}
+
+// newTestDB returns a sqlite DB set up with the default microcluster schema.
+func newTestDB() (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each connection to an in-memory database has its own data.
+	db.SetMaxOpenConns(1)
+
+	_, err = update.NewSchema().Schema().Ensure(db)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cluster.PrepareStmts(db, cluster.GetCallerProject(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (s *policySuite) Test_validate() {
+	cases := []struct {
+		name   string
+		policy types.AuthPolicy
+		valid  bool
+	}{
+		{
+			name:   "Empty policy",
+			policy: types.AuthPolicy{},
+			valid:  true,
+		},
+		{
+			name: "Custom role bound to unix and certificate identities",
+			policy: types.AuthPolicy{
+				Roles: map[string][]types.Entitlement{"operator": {"config:*", types.EntitlementClusterRead}},
+				Bindings: []types.AuthBinding{
+					{Role: "operator", IdentityType: types.IdentityTypeUnix, Identity: "gid:100"},
+					{Role: types.AuthRoleViewer, IdentityType: types.IdentityTypeUnix, Identity: "uid:1000"},
+					{Role: types.AuthRoleAdmin, IdentityType: types.IdentityTypeCertificate, Identity: "abcd"},
+				},
+			},
+			valid: true,
+		},
+		{
+			name:   "Redefined built-in role",
+			policy: types.AuthPolicy{Roles: map[string][]types.Entitlement{types.AuthRoleAdmin: {types.EntitlementAll}}},
+		},
+		{
+			name:   "Role without entitlements",
+			policy: types.AuthPolicy{Roles: map[string][]types.Entitlement{"operator": {}}},
+		},
+		{
+			name:   "Malformed entitlement",
+			policy: types.AuthPolicy{Roles: map[string][]types.Entitlement{"operator": {"config"}}},
+		},
+		{
+			name:   "Duplicate entitlement",
+			policy: types.AuthPolicy{Roles: map[string][]types.Entitlement{"operator": {"config:read", "config:read"}}},
+		},
+		{
+			name:   "Unknown role",
+			policy: types.AuthPolicy{Bindings: []types.AuthBinding{{Role: "operator", IdentityType: types.IdentityTypeUnix, Identity: "*"}}},
+		},
+		{
+			name:   "Malformed unix identity",
+			policy: types.AuthPolicy{Bindings: []types.AuthBinding{{Role: types.AuthRoleAdmin, IdentityType: types.IdentityTypeUnix, Identity: "user:root"}}},
+		},
+		{
+			name:   "Cluster member identity",
+			policy: types.AuthPolicy{Bindings: []types.AuthBinding{{Role: types.AuthRoleAdmin, IdentityType: types.IdentityTypeClusterMember, Identity: "*"}}},
+		},
+		{
+			name: "Duplicate binding",
+			policy: types.AuthPolicy{Bindings: []types.AuthBinding{
+				{Role: types.AuthRoleAdmin, IdentityType: types.IdentityTypeUnix, Identity: "*"},
+				{Role: types.AuthRoleAdmin, IdentityType: types.IdentityTypeUnix, Identity: "*"},
+			}},
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%s (case %d)", c.name, i)
+
+		err := Validate(c.policy)
+		if c.valid {
+			s.NoError(err)
+		} else {
+			s.Error(err)
+		}
+	}
+}
+
+func (s *policySuite) Test_allowed() {
+	policy := types.AuthPolicy{
+		Roles: map[string][]types.Entitlement{"operator": {"config:*", types.EntitlementClusterRead}},
+		Bindings: []types.AuthBinding{
+			{Role: "operator", IdentityType: types.IdentityTypeUnix, Identity: "gid:100"},
+			{Role: types.AuthRoleViewer, IdentityType: types.IdentityTypeUnix, Identity: "uid:1000"},
+			{Role: types.AuthRoleAdmin, IdentityType: types.IdentityTypeCertificate, Identity: "abcd"},
+		},
+	}
+
+	operator := types.Identity{Type: types.IdentityTypeUnix, Name: "uid:1001", UID: 1001, GID: 100}
+	s.True(Allowed(policy, operator, types.EntitlementConfigWrite))
+	s.True(Allowed(policy, operator, types.EntitlementClusterRead))
+	s.False(Allowed(policy, operator, types.EntitlementClusterWrite))
+
+	viewer := types.Identity{Type: types.IdentityTypeUnix, Name: "uid:1000", UID: 1000, GID: 1000}
+	s.True(Allowed(policy, viewer, types.EntitlementClusterRead))
+	s.True(Allowed(policy, viewer, types.EntitlementAPIRead))
+	s.False(Allowed(policy, viewer, types.EntitlementAPIWrite))
+
+	admin := types.Identity{Type: types.IdentityTypeCertificate, Name: "abcd"}
+	s.True(Allowed(policy, admin, types.EntitlementAuthWrite))
+
+	// Bindings only apply to identities of the same type.
+	s.False(Allowed(policy, types.Identity{Type: types.IdentityTypeCertificate, Name: "uid:1000"}, types.EntitlementAPIRead))
+	s.False(Allowed(policy, types.Identity{Type: types.IdentityTypeUnix, Name: "uid:1002", UID: 1002, GID: 1002}, types.EntitlementAPIRead))
+}
+
+func (s *policySuite) Test_allowedSecrets() {
+	policy := types.AuthPolicy{
+		Roles: map[string][]types.Entitlement{
+			"reader":  {"*:*"},
+			"tokens":  {"tokens:*"},
+			"backups": {types.EntitlementDatabaseRead},
+		},
+		Bindings: []types.AuthBinding{
+			{Role: types.AuthRoleViewer, IdentityType: types.IdentityTypeCertificate, Identity: "viewer"},
+			{Role: "reader", IdentityType: types.IdentityTypeCertificate, Identity: "reader"},
+			{Role: "tokens", IdentityType: types.IdentityTypeCertificate, Identity: "tokens"},
+			{Role: "backups", IdentityType: types.IdentityTypeCertificate, Identity: "backups"},
+			{Role: types.AuthRoleAdmin, IdentityType: types.IdentityTypeCertificate, Identity: "admin"},
+		},
+	}
+
+	identity := func(name string) types.Identity {
+		return types.Identity{Type: types.IdentityTypeCertificate, Name: name}
+	}
+
+	// Join token secrets and the database contents are not covered by a "*" object, such as the viewer role's.
+	for _, entitlement := range []types.Entitlement{types.EntitlementTokensRead, types.EntitlementDatabaseRead} {
+		s.False(Allowed(policy, identity("viewer"), entitlement))
+		s.False(Allowed(policy, identity("reader"), entitlement))
+		s.True(Allowed(policy, identity("admin"), entitlement))
+	}
+
+	s.True(Allowed(policy, identity("viewer"), types.EntitlementCertificatesRead))
+	s.True(Allowed(policy, identity("reader"), types.EntitlementConfigWrite))
+
+	// Roles naming the object are granted them.
+	s.True(Allowed(policy, identity("tokens"), types.EntitlementTokensRead))
+	s.False(Allowed(policy, identity("tokens"), types.EntitlementDatabaseRead))
+	s.True(Allowed(policy, identity("backups"), types.EntitlementDatabaseRead))
+	s.False(Allowed(policy, identity("backups"), types.EntitlementTokensRead))
+}
+
+func (s *policySuite) Test_storedPolicy() {
+	db, err := newTestDB()
+	s.Require().NoError(err)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	s.Require().NoError(err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	// Unix socket peers are granted every entitlement by default.
+	policy, err := cluster.GetCoreAuthPolicy(ctx, tx)
+	s.Require().NoError(err)
+	s.Equal(DefaultPolicy(), *policy)
+
+	s.True(Allowed(*policy, types.Identity{Type: types.IdentityTypeUnix, Name: "uid:1000", UID: 1000, GID: 1000}, types.EntitlementDatabaseWrite))
+
+	newPolicy := types.AuthPolicy{
+		Roles: map[string][]types.Entitlement{"operator": {types.EntitlementClusterRead, "config:*"}},
+		Bindings: []types.AuthBinding{
+			{Role: "operator", IdentityType: types.IdentityTypeUnix, Identity: "gid:100"},
+			{Role: types.AuthRoleViewer, IdentityType: types.IdentityTypeUnix, Identity: "*"},
+		},
+	}
+
+	s.Require().NoError(Validate(newPolicy))
+	s.Require().NoError(cluster.ReplaceCoreAuthPolicy(ctx, tx, newPolicy))
+
+	policy, err = cluster.GetCoreAuthPolicy(ctx, tx)
+	s.Require().NoError(err)
+	s.Equal(newPolicy.Bindings, policy.Bindings)
+	s.ElementsMatch(newPolicy.Roles["operator"], policy.Roles["operator"])
+	s.Len(policy.Roles, 1)
+}
+
+func (s *policySuite) Test_policyCache() {
+	cache := &PolicyCache{}
+	stored := types.AuthPolicy{Bindings: []types.AuthBinding{{Role: types.AuthRoleViewer, IdentityType: types.IdentityTypeUnix, Identity: "*"}}}
+
+	var loads int
+	load := func() (*types.AuthPolicy, error) {
+		loads++
+
+		return &stored, nil
+	}
+
+	failedLoad := func() (*types.AuthPolicy, error) {
+		return nil, fmt.Errorf("Database is offline")
+	}
+
+	// The default policy is used until the policy is loaded.
+	s.Equal(DefaultPolicy(), cache.Last())
+
+	_, err := cache.Get(failedLoad)
+	s.Error(err)
+	s.Equal(DefaultPolicy(), cache.Last())
+
+	// The policy is only loaded again once it has expired.
+	policy, err := cache.Get(load)
+	s.Require().NoError(err)
+	s.Equal(stored, policy)
+
+	_, err = cache.Get(load)
+	s.Require().NoError(err)
+	s.Equal(1, loads)
+
+	cache.loadedAt = time.Now().Add(-policyCacheExpiry)
+	_, err = cache.Get(load)
+	s.Require().NoError(err)
+	s.Equal(2, loads)
+
+	// The last loaded policy is kept, regardless of its age.
+	cache.loadedAt = time.Now().Add(-policyCacheExpiry)
+	s.Equal(stored, cache.Last())
+
+	// Policies set locally are used straight away.
+	cache.Set(DefaultPolicy())
+	policy, err = cache.Get(load)
+	s.Require().NoError(err)
+	s.Equal(DefaultPolicy(), policy)
+	s.Equal(2, loads)
+}
@@ -0,0 +1,428 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/db"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// KeyType is the type of the value of a config key.
+type KeyType string
+
+const (
+	// KeyTypeString accepts any value.
+	KeyTypeString KeyType = "string"
+
+	// KeyTypeInt accepts integers.
+	KeyTypeInt KeyType = "int"
+
+	// KeyTypeBool accepts booleans, as parsed by strconv.ParseBool.
+	KeyTypeBool KeyType = "bool"
+
+	// KeyTypeDuration accepts durations, as parsed by time.ParseDuration.
+	KeyTypeDuration KeyType = "duration"
+)
+
+// KeyScope determines whether a config key has a single value for the whole cluster or one per cluster member.
+type KeyScope string
+
+const (
+	// KeyScopeCluster keys have a single value shared by all cluster members.
+	KeyScopeCluster KeyScope = "cluster"
+
+	// KeyScopeMember keys have a separate value for each cluster member.
+	KeyScopeMember KeyScope = "member"
+)
+
+// coreKeyPrefix is reserved for the config keys of microcluster itself.
+const coreKeyPrefix = "core."
+
+// Key declares a config key that can be set through the cluster config API.
+type Key struct {
+	// Type is the type of the value. Defaults to KeyTypeString.
+	Type KeyType
+
+	// Default is the value of the key when it is not set.
+	Default string
+
+	// Scope is whether the key is set for the whole cluster or for each cluster member. Defaults to KeyScopeCluster.
+	Scope KeyScope
+
+	// Validate is an optional additional check on the value, run after it has been checked against the type.
+	Validate func(value string) error
+}
+
+// validate checks that the given non-empty value is valid for the key.
+func (k Key) validate(value string) error {
+	var err error
+	switch k.Type {
+	case KeyTypeString:
+	case KeyTypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case KeyTypeBool:
+		_, err = strconv.ParseBool(value)
+	case KeyTypeDuration:
+		_, err = time.ParseDuration(value)
+	default:
+		err = fmt.Errorf("Unknown type %q", k.Type)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if k.Validate != nil {
+		return k.Validate(value)
+	}
+
+	return nil
+}
+
+// coreKeys are the config keys of microcluster itself.
+var coreKeys = map[string]Key{
+	cluster.HeartbeatIntervalKey: {Type: KeyTypeDuration, Scope: KeyScopeCluster, Validate: validateInterval},
+	cluster.RoleProbeIntervalKey: {Type: KeyTypeDuration, Scope: KeyScopeCluster, Validate: validateInterval},
+}
+
+// validateInterval checks that a heartbeat or role probe interval is not too short.
+func validateInterval(value string) error {
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	if interval < time.Second {
+		return fmt.Errorf("Interval %q must be at least %q", interval, time.Second)
+	}
+
+	return nil
+}
+
+// Store gives access to the cluster config keys stored in the database, as seen by the local cluster member.
+// Use ForMember to access the keys scoped to another cluster member.
+type Store struct {
+	db   db.DB
+	name func() string
+	keys map[string]Key
+}
+
+// NewStore returns a store for the given config keys, along with those of microcluster itself.
+func NewStore(database db.DB, name func() string, keys map[string]Key) (*Store, error) {
+	allKeys := make(map[string]Key, len(coreKeys)+len(keys))
+	for key, keyInfo := range coreKeys {
+		allKeys[key] = keyInfo
+	}
+
+	for key, keyInfo := range keys {
+		if strings.HasPrefix(key, coreKeyPrefix) {
+			return nil, fmt.Errorf("Config key %q uses the reserved prefix %q", key, coreKeyPrefix)
+		}
+
+		if keyInfo.Type == "" {
+			keyInfo.Type = KeyTypeString
+		}
+
+		if keyInfo.Scope == "" {
+			keyInfo.Scope = KeyScopeCluster
+		}
+
+		if keyInfo.Scope != KeyScopeCluster && keyInfo.Scope != KeyScopeMember {
+			return nil, fmt.Errorf("Config key %q has unknown scope %q", key, keyInfo.Scope)
+		}
+
+		if keyInfo.Default != "" {
+			err := keyInfo.validate(keyInfo.Default)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid default value %q for config key %q: %w", keyInfo.Default, key, err)
+			}
+		}
+
+		allKeys[key] = keyInfo
+	}
+
+	return &Store{db: database, name: name, keys: allKeys}, nil
+}
+
+// Keys returns the declared config keys.
+func (s *Store) Keys() map[string]Key {
+	keys := make(map[string]Key, len(s.keys))
+	for key, keyInfo := range s.keys {
+		keys[key] = keyInfo
+	}
+
+	return keys
+}
+
+// ForMember returns a view of the config keys as seen by the named cluster member.
+// Keys scoped to cluster members are read and set for that cluster member rather than the local one.
+func (s *Store) ForMember(name string) *Store {
+	return &Store{db: s.db, name: func() string { return name }, keys: s.keys}
+}
+
+// Members returns the keys scoped to cluster members that are set for each cluster member, keyed by cluster member name.
+func (s *Store) Members(ctx context.Context) (map[string]map[string]string, error) {
+	var config map[string]map[string]string
+	err := s.db.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		config, err = s.members(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// members returns the keys scoped to cluster members that are set for each cluster member.
+func (s *Store) members(ctx context.Context, tx *sql.Tx) (map[string]map[string]string, error) {
+	allConfig, err := cluster.GetCoreMembersConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	config := make(map[string]map[string]string, len(allConfig))
+	for member, memberConfig := range allConfig {
+		for key, value := range memberConfig {
+			if s.keys[key].Scope != KeyScopeMember {
+				continue
+			}
+
+			if config[member] == nil {
+				config[member] = map[string]string{}
+			}
+
+			config[member][key] = value
+		}
+	}
+
+	return config, nil
+}
+
+// Validate checks that all the given keys are declared and their values are valid. Empty values are always valid.
+func (s *Store) Validate(config map[string]string) error {
+	for key, value := range config {
+		keyInfo, ok := s.keys[key]
+		if !ok {
+			return api.StatusErrorf(http.StatusBadRequest, "Unknown config key %q", key)
+		}
+
+		if value == "" {
+			continue
+		}
+
+		err := keyInfo.validate(value)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid value %q for config key %q: %v", value, key, err)
+		}
+	}
+
+	return nil
+}
+
+// GetAll returns the keys that are set, along with their values. Keys scoped to cluster members are those of the local cluster member.
+func (s *Store) GetAll(ctx context.Context) (map[string]string, error) {
+	var config map[string]string
+	err := s.db.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		config, err = s.getAll(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// getAll returns the keys that are set for the local cluster member, along with their values.
+func (s *Store) getAll(ctx context.Context, tx *sql.Tx) (map[string]string, error) {
+	clusterConfig, err := cluster.GetCoreConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	memberConfig, err := cluster.GetCoreMemberConfig(ctx, tx, s.name())
+	if err != nil {
+		return nil, err
+	}
+
+	config := map[string]string{}
+	for key, value := range clusterConfig {
+		if s.keys[key].Scope != KeyScopeMember {
+			config[key] = value
+		}
+	}
+
+	for key, value := range memberConfig {
+		if s.keys[key].Scope == KeyScopeMember {
+			config[key] = value
+		}
+	}
+
+	return config, nil
+}
+
+// Get returns the value of the given key, or its default if it is not set.
+func (s *Store) Get(ctx context.Context, key string) (string, error) {
+	keyInfo, ok := s.keys[key]
+	if !ok {
+		return "", api.StatusErrorf(http.StatusNotFound, "Unknown config key %q", key)
+	}
+
+	config, err := s.GetAll(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := config[key]
+	if !ok {
+		return keyInfo.Default, nil
+	}
+
+	return value, nil
+}
+
+// GetInt returns the value of the given key as an integer.
+func (s *Store) GetInt(ctx context.Context, key string) (int64, error) {
+	value, err := s.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// GetBool returns the value of the given key as a boolean.
+func (s *Store) GetBool(ctx context.Context, key string) (bool, error) {
+	value, err := s.Get(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	if value == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+// GetDuration returns the value of the given key as a duration.
+func (s *Store) GetDuration(ctx context.Context, key string) (time.Duration, error) {
+	value, err := s.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+// Update sets the given keys, unsetting those with an empty value. If replace is true, all other keys are unset too.
+// The check function is given the currently set keys before any change is made, so that concurrent updates can be detected.
+// Returns the keys whose value changed, with their new value or default.
+func (s *Store) Update(ctx context.Context, config map[string]string, replace bool, check func(current map[string]string) error) ([]types.ConfigChange, error) {
+	err := s.Validate(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []types.ConfigChange
+	err = s.db.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		changes, err = s.update(ctx, tx, config, replace, check)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// update sets the given validated keys in the transaction, and returns the keys whose value changed.
+func (s *Store) update(ctx context.Context, tx *sql.Tx, config map[string]string, replace bool, check func(current map[string]string) error) ([]types.ConfigChange, error) {
+	current, err := s.getAll(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if check != nil {
+		err = check(current)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	updates := map[string]string{}
+	for key, value := range config {
+		updates[key] = value
+	}
+
+	if replace {
+		for key := range current {
+			_, ok := updates[key]
+			if !ok {
+				updates[key] = ""
+			}
+		}
+	}
+
+	clusterUpdates := map[string]string{}
+	memberUpdates := map[string]string{}
+	changes := []types.ConfigChange{}
+	for key, value := range updates {
+		if current[key] == value {
+			continue
+		}
+
+		keyInfo := s.keys[key]
+		change := types.ConfigChange{Key: key, Value: value}
+		if value == "" {
+			change.Value = keyInfo.Default
+		}
+
+		if keyInfo.Scope == KeyScopeMember {
+			change.Member = s.name()
+			memberUpdates[key] = value
+		} else {
+			clusterUpdates[key] = value
+		}
+
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+
+	err = cluster.UpdateCoreConfig(ctx, tx, clusterUpdates)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cluster.UpdateCoreMemberConfig(ctx, tx, s.name(), memberUpdates)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
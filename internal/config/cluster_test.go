@@ -0,0 +1,193 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/db/update"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type clusterConfigSuite struct {
+	suite.Suite
+
+	db    *sql.DB
+	store *Store
+}
+
+func TestClusterConfigSuite(t *testing.T) {
+	suite.Run(t, new(clusterConfigSuite))
+}
+
+// newTestDB returns a sqlite DB set up with the default microcluster schema.
+func newTestDB() (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each connection to an in-memory database has its own data.
+	db.SetMaxOpenConns(1)
+
+	_, err = update.NewSchema().Schema().Ensure(db)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cluster.PrepareStmts(db, cluster.GetCallerProject(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (s *clusterConfigSuite) SetupTest() {
+	var err error
+	s.db, err = newTestDB()
+	s.Require().NoError(err)
+
+	s.store, err = NewStore(nil, func() string { return "n1" }, map[string]Key{
+		"app.name":    {},
+		"app.workers": {Type: KeyTypeInt, Default: "4"},
+		"app.debug":   {Type: KeyTypeBool, Scope: KeyScopeMember},
+	})
+	s.Require().NoError(err)
+}
+
+func (s *clusterConfigSuite) TearDownTest() {
+	s.NoError(s.db.Close())
+}
+
+func (s *clusterConfigSuite) transaction(f func(ctx context.Context, tx *sql.Tx) error) {
+	tx, err := s.db.Begin()
+	s.Require().NoError(err)
+
+	s.Require().NoError(f(context.Background(), tx))
+	s.Require().NoError(tx.Commit())
+}
+
+func (s *clusterConfigSuite) Test_newStore() {
+	_, err := NewStore(nil, nil, map[string]Key{"core.foo": {}})
+	s.Error(err)
+
+	_, err = NewStore(nil, nil, map[string]Key{"app.foo": {Scope: "everywhere"}})
+	s.Error(err)
+
+	_, err = NewStore(nil, nil, map[string]Key{"app.foo": {Type: KeyTypeInt, Default: "many"}})
+	s.Error(err)
+
+	s.Equal(KeyTypeString, s.store.Keys()["app.name"].Type)
+	s.Equal(KeyScopeCluster, s.store.Keys()["app.name"].Scope)
+	s.Contains(s.store.Keys(), cluster.HeartbeatIntervalKey)
+}
+
+func (s *clusterConfigSuite) Test_validate() {
+	s.NoError(s.store.Validate(map[string]string{"app.workers": "8", "app.debug": "true", "app.name": ""}))
+	s.NoError(s.store.Validate(map[string]string{cluster.HeartbeatIntervalKey: "5s"}))
+
+	for _, config := range []map[string]string{
+		{"app.unknown": "foo"},
+		{"app.workers": "many"},
+		{"app.debug": "maybe"},
+		{cluster.HeartbeatIntervalKey: "10ms"},
+	} {
+		err := s.store.Validate(config)
+		s.True(api.StatusErrorCheck(err, http.StatusBadRequest), "Config %v should be rejected", config)
+	}
+}
+
+func (s *clusterConfigSuite) Test_update() {
+	other := &Store{name: func() string { return "n2" }, keys: s.store.keys}
+
+	s.transaction(func(ctx context.Context, tx *sql.Tx) error {
+		changes, err := s.store.update(ctx, tx, map[string]string{"app.name": "foo", "app.workers": "8", "app.debug": "true"}, false, nil)
+		s.Require().NoError(err)
+		s.Equal([]types.ConfigChange{
+			{Key: "app.debug", Value: "true", Member: "n1"},
+			{Key: "app.name", Value: "foo"},
+			{Key: "app.workers", Value: "8"},
+		}, changes)
+
+		// Keys scoped to cluster members are only visible to the member that set them.
+		config, err := s.store.getAll(ctx, tx)
+		s.Require().NoError(err)
+		s.Equal(map[string]string{"app.name": "foo", "app.workers": "8", "app.debug": "true"}, config)
+
+		config, err = other.getAll(ctx, tx)
+		s.Require().NoError(err)
+		s.Equal(map[string]string{"app.name": "foo", "app.workers": "8"}, config)
+
+		// Unchanged keys are not reported, and unset keys report their default.
+		changes, err = s.store.update(ctx, tx, map[string]string{"app.name": "foo", "app.workers": ""}, false, nil)
+		s.Require().NoError(err)
+		s.Equal([]types.ConfigChange{{Key: "app.workers", Value: "4"}}, changes)
+
+		// Replacing the config unsets all other keys.
+		changes, err = s.store.update(ctx, tx, map[string]string{"app.name": "bar"}, true, nil)
+		s.Require().NoError(err)
+		s.Equal([]types.ConfigChange{
+			{Key: "app.debug", Value: "", Member: "n1"},
+			{Key: "app.name", Value: "bar"},
+		}, changes)
+
+		config, err = s.store.getAll(ctx, tx)
+		s.Require().NoError(err)
+		s.Equal(map[string]string{"app.name": "bar"}, config)
+
+		// A failed check prevents any change.
+		checkErr := errors.New("Config changed")
+		_, err = s.store.update(ctx, tx, map[string]string{"app.name": "baz"}, false, func(current map[string]string) error {
+			s.Equal(map[string]string{"app.name": "bar"}, current)
+
+			return checkErr
+		})
+		s.ErrorIs(err, checkErr)
+
+		config, err = s.store.getAll(ctx, tx)
+		s.Require().NoError(err)
+		s.Equal(map[string]string{"app.name": "bar"}, config)
+
+		return nil
+	})
+}
+
+func (s *clusterConfigSuite) Test_forMember() {
+	other := s.store.ForMember("n2")
+
+	s.transaction(func(ctx context.Context, tx *sql.Tx) error {
+		_, err := s.store.update(ctx, tx, map[string]string{"app.name": "foo", "app.debug": "true"}, false, nil)
+		s.Require().NoError(err)
+
+		// Another cluster member's keys can be set from the local cluster member.
+		changes, err := other.update(ctx, tx, map[string]string{"app.debug": "false"}, false, nil)
+		s.Require().NoError(err)
+		s.Equal([]types.ConfigChange{{Key: "app.debug", Value: "false", Member: "n2"}}, changes)
+
+		config, err := other.getAll(ctx, tx)
+		s.Require().NoError(err)
+		s.Equal(map[string]string{"app.name": "foo", "app.debug": "false"}, config)
+
+		members, err := s.store.members(ctx, tx)
+		s.Require().NoError(err)
+		s.Equal(map[string]map[string]string{"n1": {"app.debug": "true"}, "n2": {"app.debug": "false"}}, members)
+
+		// Removing a cluster member removes its keys.
+		err = cluster.DeleteCoreMemberConfig(ctx, tx, "n2")
+		s.Require().NoError(err)
+
+		members, err = s.store.members(ctx, tx)
+		s.Require().NoError(err)
+		s.Equal(map[string]map[string]string{"n1": {"app.debug": "true"}}, members)
+
+		return nil
+	})
+}
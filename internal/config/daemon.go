@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -108,6 +109,22 @@ func (d *DaemonConfig) GetServers() map[string]types.ServerConfig {
 	return serverConfigCopy
 }
 
+// GetFailureDomain returns the daemon's failure domain.
+func (d *DaemonConfig) GetFailureDomain() string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	return d.config.FailureDomain
+}
+
+// GetRoleProbeInterval returns the daemon's local copy of the cluster-wide dqlite role probe interval.
+func (d *DaemonConfig) GetRoleProbeInterval() time.Duration {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	return d.config.RoleProbeInterval
+}
+
 // SetName sets the daemon's name.
 func (d *DaemonConfig) SetName(name string) {
 	d.lock.Lock()
@@ -131,3 +148,19 @@ func (d *DaemonConfig) SetServers(servers map[string]types.ServerConfig) {
 
 	d.config.Servers = servers
 }
+
+// SetFailureDomain sets the daemon's failure domain.
+func (d *DaemonConfig) SetFailureDomain(failureDomain string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.config.FailureDomain = failureDomain
+}
+
+// SetRoleProbeInterval sets the daemon's local copy of the cluster-wide dqlite role probe interval.
+func (d *DaemonConfig) SetRoleProbeInterval(interval time.Duration) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.config.RoleProbeInterval = interval
+}
@@ -2,11 +2,14 @@ package daemon
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -23,10 +26,15 @@ import (
 
 	"github.com/canonical/microcluster/v2/client"
 	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/auth"
 	internalConfig "github.com/canonical/microcluster/v2/internal/config"
 	"github.com/canonical/microcluster/v2/internal/db"
 	"github.com/canonical/microcluster/v2/internal/endpoints"
+	"github.com/canonical/microcluster/v2/internal/events"
 	"github.com/canonical/microcluster/v2/internal/extensions"
+	"github.com/canonical/microcluster/v2/internal/health"
+	"github.com/canonical/microcluster/v2/internal/leases"
+	"github.com/canonical/microcluster/v2/internal/operations"
 	"github.com/canonical/microcluster/v2/internal/recover"
 	internalREST "github.com/canonical/microcluster/v2/internal/rest"
 	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
@@ -34,9 +42,11 @@ import (
 	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
 	internalState "github.com/canonical/microcluster/v2/internal/state"
 	"github.com/canonical/microcluster/v2/internal/sys"
+	"github.com/canonical/microcluster/v2/internal/tasks"
 	"github.com/canonical/microcluster/v2/internal/trust"
 	"github.com/canonical/microcluster/v2/internal/utils"
 	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/openapi"
 	"github.com/canonical/microcluster/v2/rest/types"
 	"github.com/canonical/microcluster/v2/state"
 )
@@ -55,7 +65,7 @@ type Args struct {
 	// Address/port to offer the core API and extension servers over before initializing the daemon
 	PreInitListenAddress string
 
-	// How often heartbeats are attempted
+	// How often heartbeats are attempted, unless configured cluster-wide through the heartbeat API
 	HeartbeatInterval time.Duration
 
 	// List of schema updates in the order that they should be applied.
@@ -67,8 +77,24 @@ type Args struct {
 	// Functions that trigger at various lifecycle events
 	Hooks *state.Hooks
 
+	// Policy for the automatic removal of cluster members that stop responding to heartbeats. Disabled by default.
+	AutoRemove state.AutoRemovePolicy
+
+	// Config keys that can be set through the cluster config API, in addition to those of microcluster itself.
+	ConfigKeys map[string]state.ConfigKey
+
+	// Thresholds of the failure detector used to derive the status of cluster members from heartbeats.
+	FailureDetector state.FailureDetectorConfig
+
 	// Each rest.Server will be initialized and managed by microcluster.
 	ExtensionServers map[string]rest.Server
+
+	// Tasks are run periodically once the database is ready, according to their schedule and run mode.
+	Tasks []state.Task
+
+	// CertificateIssuer issues the server, cluster and dedicated additional listener certificates.
+	// Certificates are self-signed if it is nil.
+	CertificateIssuer state.CertificateIssuer
 }
 
 // Daemon holds information for the microcluster daemon.
@@ -81,8 +107,9 @@ type Daemon struct {
 	os         *sys.OS
 	serverCert *shared.CertInfo
 
-	clusterMu   sync.RWMutex
-	clusterCert *shared.CertInfo
+	clusterMu             sync.RWMutex
+	clusterCert           *shared.CertInfo
+	alternateClusterCerts []*x509.Certificate // Next and previous cluster certificates while the cluster certificate is rotated.
 
 	endpoints *endpoints.Endpoints
 	db        *db.DqliteDB
@@ -90,7 +117,19 @@ type Daemon struct {
 	fsWatcher  *sys.Watcher
 	trustStore *trust.Store
 
-	hooks state.Hooks // Hooks to be called upon various daemon actions.
+	hooks      state.Hooks                // Hooks to be called upon various daemon actions.
+	autoRemove state.AutoRemovePolicy     // Policy for the automatic removal of unreachable cluster members.
+	events     *events.Server             // Events dispatches cluster events to local event listeners.
+	operations *operations.Manager        // Operations keeps track of long-running actions on this cluster member.
+	tasks      *tasks.Scheduler           // Tasks runs the periodic tasks supplied in the daemon arguments.
+	leases     *leases.Manager            // Leases grants cluster-wide leases to this cluster member.
+	health     *health.Detector           // Health estimates how likely cluster members are to have failed from heartbeats.
+	authPolicy *auth.PolicyCache          // AuthPolicy caches the authorization policy checked for each request.
+	configKeys map[string]state.ConfigKey // Config keys that can be set through the cluster config API.
+
+	certificateIssuer state.CertificateIssuer // Issues the keypairs of this cluster member, or nil for self-signed keypairs.
+
+	clusterConfig *internalConfig.Store // Cluster config keys stored in the database.
 
 	ReadyChan      chan struct{}      // Closed when the daemon is fully ready.
 	shutdownCtx    context.Context    // Cancelled when shutdown starts.
@@ -104,15 +143,21 @@ type Daemon struct {
 
 	extensionServersMu sync.RWMutex
 	extensionServers   map[string]rest.Server
+
+	listenerResourcesMu sync.RWMutex
+	listenerResources   map[string][]rest.Resources // Resources served by each listener, keyed by listener name.
 }
 
 // NewDaemon initializes the Daemon context and channels.
 func NewDaemon(project string) *Daemon {
 	d := &Daemon{
-		shutdownDoneCh:   make(chan error),
-		ReadyChan:        make(chan struct{}),
-		extensionServers: make(map[string]rest.Server),
-		project:          project,
+		shutdownDoneCh:    make(chan error),
+		ReadyChan:         make(chan struct{}),
+		extensionServers:  make(map[string]rest.Server),
+		listenerResources: make(map[string][]rest.Resources),
+		project:           project,
+		events:            events.NewServer(),
+		authPolicy:        &auth.PolicyCache{},
 	}
 
 	d.stop = sync.OnceValue(func() error {
@@ -120,6 +165,13 @@ func NewDaemon(project string) *Daemon {
 			d.shutdownCancel()
 		}
 
+		if d.tasks != nil {
+			err := d.tasks.Stop(30 * time.Second)
+			if err != nil {
+				logger.Error("Failed to stop scheduled tasks", logger.Ctx{"error": err})
+			}
+		}
+
 		var dqliteErr error
 		if d.db != nil {
 			dqliteErr = d.db.Stop()
@@ -128,6 +180,8 @@ func NewDaemon(project string) *Daemon {
 			}
 		}
 
+		d.events.Close()
+
 		if d.endpoints != nil {
 			err := d.endpoints.Down()
 			if err != nil {
@@ -145,6 +199,13 @@ func NewDaemon(project string) *Daemon {
 // and blocks until the daemon is cancelled.
 func (d *Daemon) Run(ctx context.Context, stateDir string, args Args) error {
 	d.shutdownCtx, d.shutdownCancel = context.WithCancel(ctx)
+	d.operations = operations.NewManager(d.shutdownCtx, d.Name, func(op types.Operation) {
+		err := d.State().SendEvent(types.EventOperationUpdated, op)
+		if err != nil {
+			logger.Warn("Failed to send operation event", logger.Ctx{"id": op.ID, "error": err})
+		}
+	})
+
 	if stateDir == "" {
 		stateDir = os.Getenv(sys.StateDir)
 	}
@@ -173,6 +234,32 @@ func (d *Daemon) Run(ctx context.Context, stateDir string, args Args) error {
 
 	d.version = args.Version
 
+	heartbeatInterval := args.HeartbeatInterval
+	if heartbeatInterval == 0 {
+		heartbeatInterval = db.DefaultHeartbeatInterval
+	}
+
+	// Give cluster members a few missed heartbeats to recover before they can be removed.
+	if args.AutoRemove.After != 0 && args.AutoRemove.After < 3*heartbeatInterval {
+		return fmt.Errorf("Automatic removal delay %q must be at least three heartbeat intervals (%q)", args.AutoRemove.After, 3*heartbeatInterval)
+	}
+
+	d.autoRemove = args.AutoRemove
+	d.configKeys = args.ConfigKeys
+	d.certificateIssuer = args.CertificateIssuer
+
+	err = args.FailureDetector.Validate()
+	if err != nil {
+		return fmt.Errorf("Invalid failure detector configuration: %w", err)
+	}
+
+	d.health = health.NewDetector(args.FailureDetector, func() time.Duration { return d.db.GetHeartbeatInterval() })
+
+	d.tasks, err = tasks.NewScheduler(args.Tasks, d.State)
+	if err != nil {
+		return fmt.Errorf("Invalid tasks: %w", err)
+	}
+
 	// Setup the deamon's internal config.
 	d.config = internalConfig.NewDaemonConfig(filepath.Join(d.os.StateDir, "daemon.yaml"))
 
@@ -257,6 +344,11 @@ func (d *Daemon) init(listenAddress string, socketGroup string, heartbeatInterva
 		return err
 	}
 
+	err = d.issueKeyPair(d.os.StateDir, types.ServerCertificateName, name)
+	if err != nil {
+		return err
+	}
+
 	d.serverCert, err = util.LoadServerCert(d.os.StateDir)
 	if err != nil {
 		return err
@@ -267,7 +359,68 @@ func (d *Daemon) init(listenAddress string, socketGroup string, heartbeatInterva
 		return fmt.Errorf("Failed to initialize trust store: %w", err)
 	}
 
-	d.db = db.NewDB(d.shutdownCtx, d.ServerCert, d.ClusterCert, d.Name, d.os, heartbeatInterval)
+	d.db = db.NewDB(d.shutdownCtx, d.ServerCert, d.ClusterCert, d.AlternateClusterCerts, d.Name, d.config.GetFailureDomain, d.config.GetRoleProbeInterval, d.os, heartbeatInterval)
+	d.leases = leases.NewManager(d.db, d.Name, d.db.GetHeartbeatInterval)
+
+	d.clusterConfig, err = internalConfig.NewStore(d.db, d.Name, d.configKeys)
+	if err != nil {
+		return fmt.Errorf("Invalid config keys: %w", err)
+	}
+
+	// Notify event listeners when the database starts or stops waiting for an upgrade.
+	var lastUpgradeStatus types.DatabaseStatus
+	d.db.OnStatusChange(func(oldStatus types.DatabaseStatus, newStatus types.DatabaseStatus) {
+		// Scheduled tasks only start running once the database is ready.
+		if newStatus == types.DatabaseReady {
+			d.tasks.Start(d.shutdownCtx)
+		}
+
+		if newStatus != types.DatabaseWaiting && newStatus != types.DatabaseReady {
+			return
+		}
+
+		if newStatus == lastUpgradeStatus || (lastUpgradeStatus == "" && newStatus == types.DatabaseReady) {
+			lastUpgradeStatus = newStatus
+			return
+		}
+
+		lastUpgradeStatus = newStatus
+		err := d.State().SendEvent(types.EventUpgradeStateChanged, types.EventUpgradeState{Status: newStatus})
+		if err != nil {
+			logger.Warn("Failed to send upgrade state event", logger.Ctx{"error": err})
+		}
+	})
+
+	// Run the OnLeaderChange hook in the background, so that it doesn't hold up the heartbeat that found the new leader.
+	// A single worker runs the hook, so that each run sees the changes in the order they happened.
+	leaderChanges := newLeaderChangeQueue()
+	go leaderChanges.run(d.shutdownCtx, func(change leaderChange) {
+		logger.Info("Dqlite leader changed", logger.Ctx{"oldLeader": change.oldLeader, "newLeader": change.newLeader})
+
+		err := d.hooks.OnLeaderChange(d.shutdownCtx, d.State(), change.oldLeader, change.newLeader)
+		if err != nil {
+			logger.Error("Failed to run leader change hook", logger.Ctx{"oldLeader": change.oldLeader, "newLeader": change.newLeader, "error": err})
+		}
+	})
+
+	d.db.OnLeaderChange(func(oldAddress string, newAddress string) {
+		if d.trustStore == nil {
+			return
+		}
+
+		oldLeader := ""
+		newLeader := ""
+		for name, addr := range d.trustStore.Remotes().Addresses() {
+			switch addr.String() {
+			case oldAddress:
+				oldLeader = name
+			case newAddress:
+				newLeader = name
+			}
+		}
+
+		leaderChanges.push(leaderChange{oldLeader: oldLeader, newLeader: newLeader})
+	})
 
 	listenAddr := api.NewURL()
 	if listenAddress != "" {
@@ -298,7 +451,7 @@ func (d *Daemon) init(listenAddress string, socketGroup string, heartbeatInterva
 	d.extensionServersMu.RLock()
 	for _, server := range d.extensionServers {
 		if server.ServeUnix {
-			serverEndpoints = append(serverEndpoints, server.Resources...)
+			serverEndpoints = append(serverEndpoints, server.ResourcesWithMiddlewares()...)
 		}
 	}
 
@@ -343,8 +496,12 @@ func (d *Daemon) applyHooks(hooks *state.Hooks) {
 	noOpHook := func(ctx context.Context, s state.State) error { return nil }
 	noOpRemoveHook := func(ctx context.Context, s state.State, force bool) error { return nil }
 	noOpInitHook := func(ctx context.Context, s state.State, initConfig map[string]string) error { return nil }
+	noOpConfigChangeHook := func(ctx context.Context, s state.State, changes []types.ConfigChange) error { return nil }
+	noOpLeaderHook := func(ctx context.Context, s state.State, oldLeader string, newLeader string) error { return nil }
 	noOpConfigHook := func(ctx context.Context, s state.State, config types.DaemonConfig) error { return nil }
-	noOpNewMemberHook := func(ctx context.Context, s state.State, newMember types.ClusterMemberLocal) error { return nil }
+	noOpNewMemberHook := func(ctx context.Context, s state.State, member types.ClusterMemberLocal) error { return nil }
+	noOpHeartbeatDataHook := func(ctx context.Context, s state.State) (json.RawMessage, error) { return nil, nil }
+	noOpOnHeartbeatDataHook := func(ctx context.Context, s state.State, data map[string]json.RawMessage) error { return nil }
 
 	if hooks == nil {
 		d.hooks = state.Hooks{}
@@ -388,6 +545,38 @@ func (d *Daemon) applyHooks(hooks *state.Hooks) {
 		d.hooks.PostRemove = noOpRemoveHook
 	}
 
+	if d.hooks.PreEvacuate == nil {
+		d.hooks.PreEvacuate = noOpHook
+	}
+
+	if d.hooks.PostRestore == nil {
+		d.hooks.PostRestore = noOpHook
+	}
+
+	if d.hooks.OnMemberOffline == nil {
+		d.hooks.OnMemberOffline = noOpNewMemberHook
+	}
+
+	if d.hooks.OnMemberOnline == nil {
+		d.hooks.OnMemberOnline = noOpNewMemberHook
+	}
+
+	if d.hooks.OnLeaderChange == nil {
+		d.hooks.OnLeaderChange = noOpLeaderHook
+	}
+
+	if d.hooks.HeartbeatData == nil {
+		d.hooks.HeartbeatData = noOpHeartbeatDataHook
+	}
+
+	if d.hooks.OnHeartbeatData == nil {
+		d.hooks.OnHeartbeatData = noOpOnHeartbeatDataHook
+	}
+
+	if d.hooks.OnConfigChange == nil {
+		d.hooks.OnConfigChange = noOpConfigChangeHook
+	}
+
 	if d.hooks.OnDaemonConfigUpdate == nil {
 		d.hooks.OnDaemonConfigUpdate = noOpConfigHook
 	}
@@ -442,7 +631,12 @@ func (d *Daemon) initStore() error {
 	return nil
 }
 
-func (d *Daemon) initServer(resources ...rest.Resources) *http.Server {
+// initServer returns a web server for the named listener, serving the given resources.
+func (d *Daemon) initServer(name string, serverResources ...rest.Resources) *http.Server {
+	d.listenerResourcesMu.Lock()
+	d.listenerResources[name] = serverResources
+	d.listenerResourcesMu.Unlock()
+
 	/* Setup the web server */
 	mux := mux.NewRouter()
 	mux.StrictSlash(false)
@@ -450,7 +644,7 @@ func (d *Daemon) initServer(resources ...rest.Resources) *http.Server {
 	mux.UseEncodedPath()
 
 	state := d.State()
-	for _, endpoints := range resources {
+	for _, endpoints := range serverResources {
 		for _, e := range endpoints.Endpoints {
 			internalREST.HandleEndpoint(state, mux, string(endpoints.PathPrefix), e)
 
@@ -464,6 +658,9 @@ func (d *Daemon) initServer(resources ...rest.Resources) *http.Server {
 		}
 	}
 
+	// Describe the resources served by this listener at a well-known path.
+	internalREST.HandleEndpoint(state, mux, "", resources.OpenAPIDocumentEndpoint(name))
+
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		err := response.SyncResponse(true, []string{"/1.0"}).Render(w)
@@ -487,12 +684,31 @@ func (d *Daemon) initServer(resources ...rest.Resources) *http.Server {
 	}
 }
 
+// openAPIInfo returns the description of the API used in OpenAPI documents.
+func (d *Daemon) openAPIInfo() openapi.Info {
+	return openapi.Info{Title: d.project, Version: d.version}
+}
+
+// OpenAPI returns the OpenAPI document describing the resources served by the named listener.
+func (d *Daemon) OpenAPI(name string) (*openapi.Document, error) {
+	d.listenerResourcesMu.RLock()
+	resources, ok := d.listenerResources[name]
+	d.listenerResourcesMu.RUnlock()
+	if !ok {
+		return nil, api.StatusErrorf(http.StatusNotFound, "No listener found with name %q", name)
+	}
+
+	return rest.OpenAPI(d.openAPIInfo(), resources...), nil
+}
+
 // StartAPI starts up the admin and consumer APIs, and generates a cluster cert
 // if we are bootstrapping the first node.
 func (d *Daemon) StartAPI(ctx context.Context, bootstrap bool, initConfig map[string]string, newConfig *trust.Location, joinAddresses ...string) error {
+	memberPut := types.MemberPutFromInitConfig(initConfig)
 	if newConfig != nil {
 		d.config.SetAddress(newConfig.Address)
 		d.config.SetName(newConfig.Name)
+		d.config.SetFailureDomain(memberPut.FailureDomain)
 
 		// Write the latest config to disk.
 		err := d.config.Write()
@@ -539,6 +755,11 @@ func (d *Daemon) StartAPI(ctx context.Context, bootstrap bool, initConfig map[st
 		if err != nil {
 			return fmt.Errorf("Failed to initialize local remote entry: %w", err)
 		}
+
+		err = d.issueKeyPair(d.os.StateDir, types.ClusterCertificateName, d.Name())
+		if err != nil {
+			return err
+		}
 	}
 
 	err = d.ReloadCert(types.ClusterCertificateName)
@@ -575,11 +796,13 @@ func (d *Daemon) StartAPI(ctx context.Context, bootstrap bool, initConfig map[st
 	// If bootstrapping the first node, just open the database and create an entry for ourselves.
 	if bootstrap {
 		clusterMember := cluster.CoreClusterMember{
-			Name:        localNode.Name,
-			Address:     localNode.Address.String(),
-			Certificate: localNode.Certificate.String(),
-			Heartbeat:   time.Time{},
-			Role:        cluster.Pending,
+			Name:          localNode.Name,
+			Address:       localNode.Address.String(),
+			Certificate:   localNode.Certificate.String(),
+			Heartbeat:     time.Time{},
+			Role:          cluster.Pending,
+			Labels:        memberPut.Labels,
+			FailureDomain: memberPut.FailureDomain,
 		}
 
 		clusterMember.SchemaInternal, clusterMember.SchemaExternal, _ = d.db.Schema().Version()
@@ -628,7 +851,7 @@ func (d *Daemon) StartAPI(ctx context.Context, bootstrap bool, initConfig map[st
 		return err
 	}
 
-	cluster, err := d.trustStore.Remotes().Cluster(false, d.ServerCert(), publicKey)
+	cluster, err := d.trustStore.Remotes().Cluster(false, d.ServerCert(), publicKey, d.AlternateClusterCerts()...)
 	if err != nil {
 		return err
 	}
@@ -804,7 +1027,7 @@ func (d *Daemon) UpdateServers() error {
 
 // startUnixServer starts up the core unix listener with the given resources.
 func (d *Daemon) startUnixServer(serverEndpoints []rest.Resources, socketGroup string) error {
-	ctlServer := d.initServer(serverEndpoints...)
+	ctlServer := d.initServer(endpoints.EndpointsUnix, serverEndpoints...)
 	ctl := endpoints.NewSocket(d.shutdownCtx, ctlServer, d.os.ControlSocket(), socketGroup)
 	d.endpoints = endpoints.NewEndpoints(d.shutdownCtx, map[string]endpoints.Endpoint{
 		endpoints.EndpointsUnix: ctl,
@@ -832,12 +1055,12 @@ func (d *Daemon) addCoreServers(preInit bool, defaultURL api.URL, defaultCert *s
 			continue
 		}
 
-		serverEndpoints = append(serverEndpoints, s.Resources...)
+		serverEndpoints = append(serverEndpoints, s.ResourcesWithMiddlewares()...)
 	}
 
 	d.extensionServersMu.RUnlock()
 
-	server := d.initServer(serverEndpoints...)
+	server := d.initServer(endpoints.EndpointsCore, serverEndpoints...)
 	network := endpoints.NewNetwork(d.shutdownCtx, endpoints.EndpointNetwork, server, defaultURL, defaultCert)
 
 	return d.endpoints.Add(map[string]endpoints.Endpoint{
@@ -897,13 +1120,18 @@ func (d *Daemon) addExtensionServers(preInit bool, fallbackCert *shared.CertInfo
 		} else {
 			// Generate a dedicated certificate or load the custom one if it exists.
 			// When updating the additional listeners the dedicated certificate from before will be reused.
+			err = d.issueKeyPair(d.os.CertificatesDir, types.CertificateName(serverName), serverName)
+			if err != nil {
+				return fmt.Errorf("Failed to issue dedicated certificate for additional server %q: %w", serverName, err)
+			}
+
 			cert, err = shared.KeyPairAndCA(d.os.CertificatesDir, serverName, shared.CertServer, shared.CertOptions{AddHosts: true, SubjectName: serverName})
 			if err != nil {
 				return fmt.Errorf("Failed to setup dedicated certificate for additional server %q: %w", serverName, err)
 			}
 		}
 
-		server := d.initServer(extensionServer.Resources...)
+		server := d.initServer(serverName, extensionServer.ResourcesWithMiddlewares()...)
 		network := endpoints.NewNetwork(d.shutdownCtx, endpoints.EndpointNetwork, server, *url, cert)
 		networks[serverName] = network
 	}
@@ -960,6 +1188,25 @@ func (d *Daemon) ClusterCert() *shared.CertInfo {
 	return shared.NewCertInfo(d.clusterCert.KeyPair(), d.clusterCert.CA(), d.clusterCert.CRL())
 }
 
+// AlternateClusterCerts returns the next and previous cluster certificates, which are trusted alongside the cluster
+// certificate while it is rotated.
+func (d *Daemon) AlternateClusterCerts() []*x509.Certificate {
+	d.clusterMu.RLock()
+	defer d.clusterMu.RUnlock()
+
+	return slices.Clone(d.alternateClusterCerts)
+}
+
+// issueKeyPair has the certificate issuer issue the named keypair into the given directory, unless it already exists.
+// Without a certificate issuer, a self-signed keypair is generated instead once it is loaded.
+func (d *Daemon) issueKeyPair(dir string, name types.CertificateName, subjectName string) error {
+	if d.certificateIssuer == nil || shared.PathExists(filepath.Join(dir, fmt.Sprintf("%s.crt", name))) {
+		return nil
+	}
+
+	return internalState.WriteIssuedKeyPair(d.shutdownCtx, d.certificateIssuer, dir, name, subjectName)
+}
+
 // ReloadCert reloads a specific certificate from the filesytem.
 func (d *Daemon) ReloadCert(name types.CertificateName) error {
 	d.clusterMu.Lock()
@@ -977,17 +1224,25 @@ func (d *Daemon) ReloadCert(name types.CertificateName) error {
 		return fmt.Errorf("Failed to load TLS certificate %q: %w", name, err)
 	}
 
-	// In case the cluster certificate gets reloaded also populate its value.
+	// In case the cluster certificate gets reloaded also populate its value, along with any certificates of a rotation.
 	if name == types.ClusterCertificateName {
+		alternateCerts, err := d.os.AlternateClusterCerts()
+		if err != nil {
+			return err
+		}
+
 		d.clusterCert = cert
+		d.alternateClusterCerts = alternateCerts
 	}
 
 	if name == types.ServerCertificateName {
+		d.serverCert = cert
+
+		// Once initialized, the core API is served with the cluster certificate,
+		// so a renewed server certificate only identifies this cluster member to the others.
 		if d.db.Status() != types.DatabaseNotReady {
-			return fmt.Errorf("Cannot replace server certificate after initialization")
+			return nil
 		}
-
-		d.serverCert = cert
 	}
 
 	if name == types.ClusterCertificateName || name == types.ServerCertificateName {
@@ -1074,24 +1329,36 @@ func (d *Daemon) FileSystem() *sys.OS {
 // State creates a State instance with the daemon's stateful components.
 func (d *Daemon) State() state.State {
 	state := &internalState.InternalState{
-		Hooks:                    &d.hooks,
-		Context:                  d.shutdownCtx,
-		ReadyCh:                  d.ReadyChan,
-		StartAPI:                 d.StartAPI,
-		Extensions:               d.Extensions,
-		Endpoints:                d.endpoints,
-		UpdateServers:            d.UpdateServers,
-		LocalConfig:              d.LocalConfig,
-		ReloadCert:               d.ReloadCert,
-		InternalFileSystem:       d.FileSystem,
-		InternalAddress:          d.Address,
-		InternalName:             d.Name,
-		InternalVersion:          d.Version,
-		InternalServerCert:       d.ServerCert,
-		InternalClusterCert:      d.ClusterCert,
-		InternalDatabase:         d.db,
-		InternalRemotes:          d.trustStore.Remotes,
-		InternalExtensionServers: d.ExtensionServers,
+		Hooks:                         &d.hooks,
+		AutoRemove:                    d.autoRemove,
+		CertificateIssuer:             d.certificateIssuer,
+		Events:                        d.events,
+		Context:                       d.shutdownCtx,
+		ReadyCh:                       d.ReadyChan,
+		StartAPI:                      d.StartAPI,
+		Extensions:                    d.Extensions,
+		Endpoints:                     d.endpoints,
+		UpdateServers:                 d.UpdateServers,
+		LocalConfig:                   d.LocalConfig,
+		ReloadCert:                    d.ReloadCert,
+		InternalFileSystem:            d.FileSystem,
+		InternalAddress:               d.Address,
+		InternalName:                  d.Name,
+		InternalVersion:               d.Version,
+		InternalServerCert:            d.ServerCert,
+		InternalClusterCert:           d.ClusterCert,
+		InternalAlternateClusterCerts: d.AlternateClusterCerts,
+		InternalDatabase:              d.db,
+		InternalRemotes:               d.trustStore.Remotes,
+		InternalExtensionServers:      d.ExtensionServers,
+		InternalOperations:            d.operations,
+		InternalLeases:                d.leases,
+		FailureDetector:               d.health,
+		AuthPolicy:                    d.authPolicy,
+		InternalConfig:                d.clusterConfig,
+		TaskStatus:                    d.tasks.Status,
+		RunTask:                       d.tasks.RunTask,
+		OpenAPI:                       d.OpenAPI,
 		Stop: func() (exit func(), stopErr error) {
 			stopErr = d.stop()
 			exit = func() {
@@ -1106,7 +1373,8 @@ func (d *Daemon) State() state.State {
 				return err
 			}
 
-			return d.endpoints.Down()
+			// Keep the control socket up, so that local clients can still collect the result of an operation.
+			return d.endpoints.Down(endpoints.EndpointNetwork)
 		},
 	}
 
@@ -236,3 +236,31 @@ func (t *daemonsSuite) Test_UpdateServers() {
 		require.NoError(t.T(), err)
 	}
 }
+
+func (t *daemonsSuite) Test_leaderChangeQueue() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queue := newLeaderChangeQueue()
+	queue.push(leaderChange{oldLeader: "", newLeader: "n1"})
+
+	handled := make(chan leaderChange)
+	running := 0
+	go queue.run(ctx, func(change leaderChange) {
+		running++
+		t.Equal(1, running, "Leader changes are handled concurrently")
+		handled <- change
+		running--
+	})
+
+	t.Equal(leaderChange{oldLeader: "", newLeader: "n1"}, <-handled)
+
+	// Changes pushed while one is being handled are handled afterwards, in order.
+	queue.push(leaderChange{oldLeader: "n1", newLeader: "n2"})
+	queue.push(leaderChange{oldLeader: "n2", newLeader: "n3"})
+	t.Equal(leaderChange{oldLeader: "n1", newLeader: "n2"}, <-handled)
+	t.Equal(leaderChange{oldLeader: "n2", newLeader: "n3"}, <-handled)
+
+	queue.push(leaderChange{oldLeader: "n3", newLeader: "n1"})
+	t.Equal(leaderChange{oldLeader: "n3", newLeader: "n1"}, <-handled)
+}
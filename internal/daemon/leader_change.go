@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+)
+
+// leaderChange is a change of dqlite leader, as learned through heartbeats.
+type leaderChange struct {
+	oldLeader string
+	newLeader string
+}
+
+// leaderChangeQueue hands changes of dqlite leader to a single worker, so that they are handled one at a time and in
+// the order they were learned, without holding up the heartbeat that found them.
+type leaderChangeQueue struct {
+	mu      sync.Mutex
+	pending []leaderChange
+	wake    chan struct{}
+}
+
+// newLeaderChangeQueue returns an empty leaderChangeQueue.
+func newLeaderChangeQueue() *leaderChangeQueue {
+	return &leaderChangeQueue{wake: make(chan struct{}, 1)}
+}
+
+// push adds a change to the end of the queue.
+func (q *leaderChangeQueue) push(change leaderChange) {
+	q.mu.Lock()
+	q.pending = append(q.pending, change)
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pop removes the change at the front of the queue, and returns false if the queue is empty.
+func (q *leaderChangeQueue) pop() (leaderChange, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) == 0 {
+		return leaderChange{}, false
+	}
+
+	change := q.pending[0]
+	q.pending = q.pending[1:]
+
+	return change, true
+}
+
+// run calls handle for each change pushed to the queue, one at a time and in order, until the context is cancelled.
+func (q *leaderChangeQueue) run(ctx context.Context, handle func(change leaderChange)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		}
+
+		for {
+			change, ok := q.pop()
+			if !ok {
+				break
+			}
+
+			handle(change)
+		}
+	}
+}
@@ -31,17 +31,13 @@ func (db *DqliteDB) Open(ext extensions.Extensions, bootstrap bool, project stri
 	ctx, cancel := context.WithTimeout(db.ctx, 30*time.Second)
 	defer cancel()
 
-	db.statusLock.Lock()
-	db.status = types.DatabaseStarting
-	db.statusLock.Unlock()
+	db.setStatus(types.DatabaseStarting)
 
 	reverter := revert.New()
 	defer reverter.Fail()
 
 	reverter.Add(func() {
-		db.statusLock.Lock()
-		db.status = types.DatabaseOffline
-		db.statusLock.Unlock()
+		db.setStatus(types.DatabaseOffline)
 	})
 
 	err := db.dqlite.Ready(ctx)
@@ -76,12 +72,12 @@ func (db *DqliteDB) Open(ext extensions.Extensions, bootstrap bool, project stri
 		return err
 	}
 
-	db.statusLock.Lock()
-	db.status = types.DatabaseReady
-	db.statusLock.Unlock()
+	db.setStatus(types.DatabaseReady)
 
 	reverter.Success()
 
+	db.heartbeatOnce.Do(func() { go db.heartbeatLoop() })
+
 	return nil
 }
 
@@ -233,9 +229,7 @@ func (db *DqliteDB) waitUpgrade(bootstrap bool, ext extensions.Extensions) error
 
 	// If we are not bootstrapping, wait for an upgrade notification, or wait a minute before checking again.
 	if otherNodesBehind && !bootstrap {
-		db.statusLock.Lock()
-		db.status = types.DatabaseWaiting
-		db.statusLock.Unlock()
+		db.setStatus(types.DatabaseWaiting)
 
 		logger.Warn("Waiting for other cluster members to upgrade their versions", logger.Ctx{"address": db.listenAddr.String()})
 		select {
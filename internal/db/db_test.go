@@ -17,6 +17,7 @@ import (
 	"github.com/canonical/microcluster/v2/internal/db/update"
 	"github.com/canonical/microcluster/v2/internal/extensions"
 	"github.com/canonical/microcluster/v2/internal/sys"
+	"github.com/canonical/microcluster/v2/rest/types"
 )
 
 type dbSuite struct {
@@ -648,6 +649,130 @@ func (s *dbSuite) Test_waitUpgradeSchemaAndAPI() {
 	}
 }
 
+// Ensures GetUpgradingClusterMembers returns cluster members whether or not the most recent columns have been added yet.
+func (s *dbSuite) Test_getUpgradingClusterMembers() {
+	db, err := NewTestDB([]schema.Update{})
+	s.Require().NoError(err)
+
+	apiExtensions, err := extensions.NewExtensionRegistry(true)
+	s.Require().NoError(err)
+
+	ctx := context.Background()
+	tx, err := db.db.BeginTx(ctx, nil)
+	s.Require().NoError(err)
+
+	for i := 0; i < 2; i++ {
+		_, err = cluster.CreateCoreClusterMember(ctx, tx, cluster.CoreClusterMember{
+			Name:           fmt.Sprintf("cluster-member-%d", i),
+			Address:        fmt.Sprintf("10.0.0.%d:8443", i),
+			Certificate:    fmt.Sprintf("test-cert-%d", i),
+			SchemaInternal: uint64(i),
+			APIExtensions:  apiExtensions,
+			Role:           "voter",
+			Labels:         cluster.MemberLabels{"zone": "a"},
+			FailureDomain:  "rack-1",
+			Maintenance:    i == 0,
+		})
+		s.Require().NoError(err)
+	}
+
+	members, awaiting, err := cluster.GetUpgradingClusterMembers(ctx, tx, 1, 0, apiExtensions)
+	s.Require().NoError(err)
+	s.Require().Len(members, 2)
+	s.Equal(cluster.MemberLabels{"zone": "a"}, members[0].Labels)
+	s.Equal("rack-1", members[0].FailureDomain)
+	s.True(members[0].Maintenance)
+	s.False(members[1].Maintenance)
+	s.Equal(map[string]bool{"cluster-member-0": true, "cluster-member-1": false}, awaiting)
+
+	// Columns added by later schema updates are substituted with their default value.
+	for _, column := range []string{"labels", "failure_domain", "maintenance"} {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE core_cluster_members DROP COLUMN %s", column))
+		s.Require().NoError(err)
+	}
+
+	members, _, err = cluster.GetUpgradingClusterMembers(ctx, tx, 1, 0, apiExtensions)
+	s.Require().NoError(err)
+	s.Require().Len(members, 2)
+	s.Equal(cluster.MemberLabels{}, members[0].Labels)
+	s.Equal("", members[0].FailureDomain)
+	s.False(members[0].Maintenance)
+
+	s.NoError(tx.Rollback())
+}
+
+// Ensures the leader change function is only called when the recorded leader actually changes.
+func (s *dbSuite) Test_setLeaderAddress() {
+	db := &DqliteDB{}
+
+	changes := [][2]string{}
+	db.OnLeaderChange(func(oldAddress string, newAddress string) {
+		changes = append(changes, [2]string{oldAddress, newAddress})
+	})
+
+	address, electedAt := db.LeaderAddress()
+	s.Equal("", address)
+	s.True(electedAt.IsZero())
+
+	s.Equal("", db.SetLeaderAddress("10.0.0.1:8443"))
+	address, firstElectedAt := db.LeaderAddress()
+	s.Equal("10.0.0.1:8443", address)
+	s.False(firstElectedAt.IsZero())
+
+	s.Equal("10.0.0.1:8443", db.SetLeaderAddress("10.0.0.1:8443"))
+	_, electedAt = db.LeaderAddress()
+	s.Equal(firstElectedAt, electedAt)
+
+	s.Equal("10.0.0.1:8443", db.SetLeaderAddress("10.0.0.2:8443"))
+	address, electedAt = db.LeaderAddress()
+	s.Equal("10.0.0.2:8443", address)
+	s.False(electedAt.Before(firstElectedAt))
+
+	s.Equal([][2]string{{"", "10.0.0.1:8443"}, {"10.0.0.1:8443", "10.0.0.2:8443"}}, changes)
+}
+
+// Ensures the cluster-wide heartbeat configuration is stored and parsed, falling back to the local defaults.
+func (s *dbSuite) Test_clusterHeartbeatConfig() {
+	db, err := NewTestDB([]schema.Update{})
+	s.Require().NoError(err)
+
+	db.defaultHeartbeatInterval = DefaultHeartbeatInterval
+
+	ctx := context.Background()
+	tx, err := db.db.BeginTx(ctx, nil)
+	s.Require().NoError(err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	coreConfig, err := cluster.GetCoreConfig(ctx, tx)
+	s.Require().NoError(err)
+
+	heartbeatConfig, err := db.ClusterHeartbeatConfig(coreConfig)
+	s.Require().NoError(err)
+	s.Equal(types.HeartbeatConfig{Interval: DefaultHeartbeatInterval, RoleProbeInterval: DefaultHeartbeatInterval}, heartbeatConfig)
+
+	err = cluster.UpdateCoreConfig(ctx, tx, map[string]string{cluster.HeartbeatIntervalKey: "5s", cluster.RoleProbeIntervalKey: "30s"})
+	s.Require().NoError(err)
+
+	coreConfig, err = cluster.GetCoreConfig(ctx, tx)
+	s.Require().NoError(err)
+
+	heartbeatConfig, err = db.ClusterHeartbeatConfig(coreConfig)
+	s.Require().NoError(err)
+	s.Equal(types.HeartbeatConfig{Interval: 5 * time.Second, RoleProbeInterval: 30 * time.Second}, heartbeatConfig)
+
+	// Unsetting a key reverts it to the default.
+	err = cluster.UpdateCoreConfig(ctx, tx, map[string]string{cluster.RoleProbeIntervalKey: ""})
+	s.Require().NoError(err)
+
+	coreConfig, err = cluster.GetCoreConfig(ctx, tx)
+	s.Require().NoError(err)
+	s.Equal(map[string]string{cluster.HeartbeatIntervalKey: "5s"}, coreConfig)
+
+	_, err = db.ClusterHeartbeatConfig(map[string]string{cluster.HeartbeatIntervalKey: "often"})
+	s.Error(err)
+}
+
 // NewTedb returns a sqlite DB set up with the default microcluster schema.
 func NewTestDB(extensionsExternal []schema.Update) (*DqliteDB, error) {
 	var err error
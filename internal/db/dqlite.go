@@ -4,9 +4,12 @@ import (
 	"bufio"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"net"
 	"net/http"
@@ -36,10 +39,12 @@ import (
 
 // DqliteDB holds all information internal to the dqlite database.
 type DqliteDB struct {
-	memberName  func() string           // Local cluster member name
-	clusterCert func() *shared.CertInfo // Cluster certificate for dqlite authentication.
-	serverCert  func() *shared.CertInfo // Server certificate for dqlite authentication.
-	listenAddr  api.URL                 // Listen address for this dqlite node.
+	memberName            func() string              // Local cluster member name
+	failureDomain         func() string              // Local cluster member failure domain
+	clusterCert           func() *shared.CertInfo    // Cluster certificate for dqlite authentication.
+	alternateClusterCerts func() []*x509.Certificate // Certificates trusted alongside the cluster certificate while it is rotated.
+	serverCert            func() *shared.CertInfo    // Server certificate for dqlite authentication.
+	listenAddr            api.URL                    // Listen address for this dqlite node.
 
 	dbName string // This is db.bin.
 	os     *sys.OS
@@ -52,28 +57,70 @@ type DqliteDB struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
-	heartbeatLock     sync.Mutex
-	heartbeatInterval time.Duration
-	maxConns          int64
+	heartbeatLock sync.Mutex
+	heartbeatOnce sync.Once
+	maxConns      int64
+
+	intervalLock             sync.RWMutex
+	defaultHeartbeatInterval time.Duration
+	heartbeatInterval        time.Duration
+	roleProbeInterval        func() time.Duration
+	runningRoleProbeInterval time.Duration
+
+	leaderLock      sync.Mutex
+	leaderAddress   string
+	leaderElectedAt time.Time
+	leaderChange    func(oldAddress string, newAddress string)
+
+	heartbeatDataLock sync.Mutex
+	heartbeatData     map[string]json.RawMessage
+
+	memberCertificatesLock sync.Mutex
+	memberCertificates     map[string][]types.CertificateExpiry
 
 	schema *update.SchemaUpdate
 
-	statusLock sync.RWMutex
-	status     types.DatabaseStatus
+	statusLock   sync.RWMutex
+	status       types.DatabaseStatus
+	statusChange func(oldStatus types.DatabaseStatus, newStatus types.DatabaseStatus)
 }
 
 const (
 	// DefaultHeartbeatInterval is the default interval used for heartbeats and dqlite role probes.
 	DefaultHeartbeatInterval time.Duration = time.Second * 10
+
+	// MaintenanceWeight is the dqlite weight of a cluster member in maintenance. Dqlite promotes the cluster members
+	// with the lowest weight first, so other cluster members are preferred whenever they can take on the role.
+	MaintenanceWeight uint64 = 1 << 32
+
+	// PinnedWeight is the dqlite weight of a cluster member that was manually demoted, so that dqlite promotes other
+	// cluster members first and the cluster member keeps its assigned role. Restoring a cluster member from
+	// maintenance clears it.
+	PinnedWeight uint64 = 1 << 16
 )
 
+// FailureDomainCode returns the dqlite failure domain code for the named failure domain.
+// Members without a failure domain share the default code 0.
+func FailureDomainCode(failureDomain string) uint64 {
+	if failureDomain == "" {
+		return 0
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(failureDomain))
+
+	return hash.Sum64()
+}
+
 // Accept sends the outbound connection through the acceptCh channel to be received by dqlite.
 func (db *DqliteDB) Accept(conn net.Conn) {
 	db.acceptCh <- conn
 }
 
 // NewDB creates an empty db struct with no dqlite connection.
-func NewDB(ctx context.Context, serverCert func() *shared.CertInfo, clusterCert func() *shared.CertInfo, memberName func() string, os *sys.OS, heartbeatInterval time.Duration) *DqliteDB {
+// The heartbeat interval is used until the cluster-wide heartbeat configuration is learned from heartbeats,
+// and the role probe interval is used when the database starts, falling back to the heartbeat interval if zero.
+func NewDB(ctx context.Context, serverCert func() *shared.CertInfo, clusterCert func() *shared.CertInfo, alternateClusterCerts func() []*x509.Certificate, memberName func() string, failureDomain func() string, roleProbeInterval func() time.Duration, os *sys.OS, heartbeatInterval time.Duration) *DqliteDB {
 	shutdownCtx, shutdownCancel := context.WithCancel(ctx)
 
 	if heartbeatInterval == 0 {
@@ -81,18 +128,25 @@ func NewDB(ctx context.Context, serverCert func() *shared.CertInfo, clusterCert
 	}
 
 	return &DqliteDB{
-		memberName:        memberName,
-		serverCert:        serverCert,
-		clusterCert:       clusterCert,
-		dbName:            filepath.Base(os.DatabasePath()),
-		os:                os,
-		acceptCh:          make(chan net.Conn),
-		upgradeCh:         make(chan struct{}),
-		heartbeatInterval: heartbeatInterval,
-		ctx:               shutdownCtx,
-		cancel:            shutdownCancel,
-		status:            types.DatabaseNotReady,
-		maxConns:          1,
+		memberName:    memberName,
+		failureDomain: failureDomain,
+		serverCert:    serverCert,
+		clusterCert:   clusterCert,
+
+		alternateClusterCerts: alternateClusterCerts,
+
+		dbName:    filepath.Base(os.DatabasePath()),
+		os:        os,
+		acceptCh:  make(chan net.Conn),
+		upgradeCh: make(chan struct{}),
+		ctx:       shutdownCtx,
+		cancel:    shutdownCancel,
+		status:    types.DatabaseNotReady,
+		maxConns:  1,
+
+		defaultHeartbeatInterval: heartbeatInterval,
+		heartbeatInterval:        heartbeatInterval,
+		roleProbeInterval:        roleProbeInterval,
 	}
 }
 
@@ -119,8 +173,8 @@ func (db *DqliteDB) Bootstrap(extensions extensions.Extensions, project string,
 	db.listenAddr = addr
 	db.dqlite, err = dqlite.New(db.os.DatabaseDir,
 		dqlite.WithAddress(db.listenAddr.URL.Host),
-		dqlite.WithRolesAdjustmentFrequency(db.heartbeatInterval),
-		dqlite.WithRolesAdjustmentHook(db.heartbeat),
+		dqlite.WithFailureDomain(FailureDomainCode(db.failureDomain())),
+		dqlite.WithRolesAdjustmentFrequency(db.startRoleProbes()),
 		dqlite.WithConcurrentLeaderConns(&db.maxConns),
 		dqlite.WithExternalConn(db.dialFunc(), db.acceptCh),
 		dqlite.WithUnixSocket(os.Getenv(sys.DqliteSocket)))
@@ -153,8 +207,8 @@ func (db *DqliteDB) Join(extensions extensions.Extensions, project string, addr
 	db.listenAddr = addr
 	db.dqlite, err = dqlite.New(db.os.DatabaseDir,
 		dqlite.WithCluster(joinAddresses),
-		dqlite.WithRolesAdjustmentFrequency(db.heartbeatInterval),
-		dqlite.WithRolesAdjustmentHook(db.heartbeat),
+		dqlite.WithFailureDomain(FailureDomainCode(db.failureDomain())),
+		dqlite.WithRolesAdjustmentFrequency(db.startRoleProbes()),
 		dqlite.WithAddress(db.listenAddr.URL.Host),
 		dqlite.WithConcurrentLeaderConns(&db.maxConns),
 		dqlite.WithExternalConn(db.dialFunc(), db.acceptCh),
@@ -208,6 +262,42 @@ func (db *DqliteDB) Cluster(ctx context.Context, client *dqliteClient.Client) ([
 	return members, nil
 }
 
+// SetWeight sets the weight of the local dqlite node, used by dqlite to choose between cluster members to promote.
+// The weight is not persisted, and is reset when the database restarts.
+func (db *DqliteDB) SetWeight(ctx context.Context, weight uint64) error {
+	client, err := db.dqlite.Client(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to local dqlite node: %w", err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	err = client.Weight(ctx, weight)
+	if err != nil {
+		return fmt.Errorf("Failed to set dqlite weight: %w", err)
+	}
+
+	return nil
+}
+
+// SetNodeWeight sets the weight of the dqlite node with the given address.
+// The weight is not persisted, and is reset when the database of that cluster member restarts.
+func (db *DqliteDB) SetNodeWeight(ctx context.Context, address string, weight uint64) error {
+	client, err := dqliteClient.New(ctx, address, dqliteClient.WithDialFunc(db.dialFunc()))
+	if err != nil {
+		return fmt.Errorf("Failed to connect to dqlite node %q: %w", address, err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	err = client.Weight(ctx, weight)
+	if err != nil {
+		return fmt.Errorf("Failed to set dqlite weight of %q: %w", address, err)
+	}
+
+	return nil
+}
+
 // Status returns the current status of the database.
 func (db *DqliteDB) Status() types.DatabaseStatus {
 	if db == nil {
@@ -221,6 +311,26 @@ func (db *DqliteDB) Status() types.DatabaseStatus {
 	return status
 }
 
+// OnStatusChange sets a function to be called whenever the status of the database changes.
+func (db *DqliteDB) OnStatusChange(f func(oldStatus types.DatabaseStatus, newStatus types.DatabaseStatus)) {
+	db.statusLock.Lock()
+	db.statusChange = f
+	db.statusLock.Unlock()
+}
+
+// setStatus updates the status of the database and notifies the status change function, if the status has changed.
+func (db *DqliteDB) setStatus(status types.DatabaseStatus) {
+	db.statusLock.Lock()
+	oldStatus := db.status
+	db.status = status
+	statusChange := db.statusChange
+	db.statusLock.Unlock()
+
+	if statusChange != nil && oldStatus != status {
+		statusChange(oldStatus, status)
+	}
+}
+
 // IsOpen returns nil  only if the DB has been opened and the schema loaded.
 // Otherwise, it returns an error describing why the database is offline.
 // The returned error may have the http status 503, indicating that the database is in a valid but unavailable state.
@@ -297,17 +407,209 @@ func (db *DqliteDB) dialFunc() dqliteClient.DialFunc {
 
 // GetHeartbeatInterval returns the current database heartbeat interval.
 func (db *DqliteDB) GetHeartbeatInterval() time.Duration {
+	db.intervalLock.RLock()
+	defer db.intervalLock.RUnlock()
+
 	return db.heartbeatInterval
 }
 
+// HeartbeatConfig returns the heartbeat and role probe intervals in effect on this cluster member.
+// The role probe interval is the one dqlite was started with.
+func (db *DqliteDB) HeartbeatConfig() types.HeartbeatConfig {
+	db.intervalLock.RLock()
+	defer db.intervalLock.RUnlock()
+
+	return types.HeartbeatConfig{Interval: db.heartbeatInterval, RoleProbeInterval: db.runningRoleProbeInterval}
+}
+
+// ClusterHeartbeatConfig returns the heartbeat configuration described by the given cluster-wide config keys.
+// Unset intervals fall back to the heartbeat interval this cluster member was started with.
+func (db *DqliteDB) ClusterHeartbeatConfig(config map[string]string) (types.HeartbeatConfig, error) {
+	heartbeatConfig := types.HeartbeatConfig{Interval: db.defaultHeartbeatInterval, RoleProbeInterval: db.defaultHeartbeatInterval}
+	for key, interval := range map[string]*time.Duration{cluster.HeartbeatIntervalKey: &heartbeatConfig.Interval, cluster.RoleProbeIntervalKey: &heartbeatConfig.RoleProbeInterval} {
+		value := config[key]
+		if value == "" {
+			continue
+		}
+
+		var err error
+		*interval, err = time.ParseDuration(value)
+		if err != nil {
+			return types.HeartbeatConfig{}, fmt.Errorf("Invalid value %q for config key %q: %w", value, key, err)
+		}
+	}
+
+	return heartbeatConfig, nil
+}
+
+// SetHeartbeatInterval changes the interval between heartbeats. It takes effect from the next heartbeat.
+func (db *DqliteDB) SetHeartbeatInterval(interval time.Duration) {
+	db.intervalLock.Lock()
+	defer db.intervalLock.Unlock()
+
+	db.heartbeatInterval = interval
+}
+
+// startRoleProbes records and returns the interval between dqlite role probes to start dqlite with.
+func (db *DqliteDB) startRoleProbes() time.Duration {
+	db.intervalLock.Lock()
+	defer db.intervalLock.Unlock()
+
+	db.runningRoleProbeInterval = db.defaultHeartbeatInterval
+	if db.roleProbeInterval != nil && db.roleProbeInterval() > 0 {
+		db.runningRoleProbeInterval = db.roleProbeInterval()
+	}
+
+	return db.runningRoleProbeInterval
+}
+
+// SetLeaderAddress records the address of the current dqlite leader, and returns the previously recorded address.
+// If the leader has changed, the time of the change is recorded and the leader change function is notified.
+func (db *DqliteDB) SetLeaderAddress(address string) (oldAddress string) {
+	db.leaderLock.Lock()
+	oldAddress = db.leaderAddress
+	db.leaderAddress = address
+	leaderChange := db.leaderChange
+	if oldAddress != address {
+		db.leaderElectedAt = time.Now().UTC()
+	}
+
+	db.leaderLock.Unlock()
+
+	if leaderChange != nil && oldAddress != address {
+		leaderChange(oldAddress, address)
+	}
+
+	return oldAddress
+}
+
+// LeaderAddress returns the address of the dqlite leader as last recorded by heartbeats, and the time at which this
+// cluster member found out it had changed.
+func (db *DqliteDB) LeaderAddress() (address string, electedAt time.Time) {
+	db.leaderLock.Lock()
+	defer db.leaderLock.Unlock()
+
+	return db.leaderAddress, db.leaderElectedAt
+}
+
+// OnLeaderChange sets a function to be called whenever the recorded dqlite leader changes.
+// The old address is empty the first time the leader is recorded.
+func (db *DqliteDB) OnLeaderChange(f func(oldAddress string, newAddress string)) {
+	db.leaderLock.Lock()
+	db.leaderChange = f
+	db.leaderLock.Unlock()
+}
+
+// SetHeartbeatData records the application data of each cluster member, keyed by name, as aggregated by heartbeats.
+func (db *DqliteDB) SetHeartbeatData(data map[string]json.RawMessage) {
+	db.heartbeatDataLock.Lock()
+	defer db.heartbeatDataLock.Unlock()
+
+	db.heartbeatData = make(map[string]json.RawMessage, len(data))
+	for name, memberData := range data {
+		db.heartbeatData[name] = memberData
+	}
+}
+
+// HeartbeatData returns a copy of the application data of each cluster member, keyed by name, as last aggregated by heartbeats.
+func (db *DqliteDB) HeartbeatData() map[string]json.RawMessage {
+	db.heartbeatDataLock.Lock()
+	defer db.heartbeatDataLock.Unlock()
+
+	data := make(map[string]json.RawMessage, len(db.heartbeatData))
+	for name, memberData := range db.heartbeatData {
+		data[name] = memberData
+	}
+
+	return data
+}
+
+// SetMemberCertificates records the expiry of the keypairs managed by each cluster member, keyed by name, as
+// aggregated by heartbeats.
+func (db *DqliteDB) SetMemberCertificates(certificates map[string][]types.CertificateExpiry) {
+	db.memberCertificatesLock.Lock()
+	defer db.memberCertificatesLock.Unlock()
+
+	db.memberCertificates = make(map[string][]types.CertificateExpiry, len(certificates))
+	for name, memberCertificates := range certificates {
+		db.memberCertificates[name] = memberCertificates
+	}
+}
+
+// MemberCertificates returns a copy of the expiry of the keypairs managed by each cluster member, keyed by name, as
+// last aggregated by heartbeats.
+func (db *DqliteDB) MemberCertificates() map[string][]types.CertificateExpiry {
+	db.memberCertificatesLock.Lock()
+	defer db.memberCertificatesLock.Unlock()
+
+	certificates := make(map[string][]types.CertificateExpiry, len(db.memberCertificates))
+	for name, memberCertificates := range db.memberCertificates {
+		certificates[name] = memberCertificates
+	}
+
+	return certificates
+}
+
 // SendHeartbeat initiates a new heartbeat sequence if this is a leader node.
-func (db *DqliteDB) SendHeartbeat(ctx context.Context, c *internalClient.Client, hbInfo internalTypes.HeartbeatInfo) error {
+func (db *DqliteDB) SendHeartbeat(ctx context.Context, c *internalClient.Client, hbInfo internalTypes.HeartbeatInfo) (*internalTypes.HeartbeatResponse, error) {
 	// set the heartbeat timeout to twice the heartbeat interval.
-	heartbeatTimeout := db.heartbeatInterval * 2
+	heartbeatTimeout := db.GetHeartbeatInterval() * 2
 	queryCtx, cancel := context.WithTimeout(ctx, heartbeatTimeout)
 	defer cancel()
 
-	return c.QueryStruct(queryCtx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("heartbeat"), hbInfo, nil)
+	hbResp := internalTypes.HeartbeatResponse{}
+	err := c.QueryStruct(queryCtx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("heartbeat"), hbInfo, &hbResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hbResp, nil
+}
+
+// heartbeatLoop initiates a heartbeat every heartbeat interval until the database is stopped.
+// The interval is read again after each heartbeat, so that changes take effect without a restart.
+func (db *DqliteDB) heartbeatLoop() {
+	for {
+		select {
+		case <-db.ctx.Done():
+			return
+		case <-time.After(db.GetHeartbeatInterval()):
+		}
+
+		err := db.maybeHeartbeat()
+		if err != nil {
+			logger.Debug("Failed to check for heartbeat", logger.Ctx{"address": db.listenAddr.String(), "error": err})
+		}
+	}
+}
+
+// maybeHeartbeat initiates a heartbeat if this cluster member is the dqlite leader.
+func (db *DqliteDB) maybeHeartbeat() error {
+	ctx, cancel := context.WithTimeout(db.ctx, db.GetHeartbeatInterval())
+	defer cancel()
+
+	client, err := db.dqlite.Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	leaderInfo, err := client.Leader(ctx)
+	if err != nil {
+		return err
+	}
+
+	if leaderInfo == nil || leaderInfo.Address != db.listenAddr.URL.Host {
+		return nil
+	}
+
+	servers, err := client.Cluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	return db.heartbeat(*leaderInfo, servers)
 }
 
 func (db *DqliteDB) heartbeat(leaderInfo dqliteClient.NodeInfo, servers []dqliteClient.NodeInfo) error {
@@ -342,7 +644,7 @@ func (db *DqliteDB) heartbeat(leaderInfo dqliteClient.NodeInfo, servers []dqlite
 		hbInfo.DqliteRoles[server.Address] = server.Role.String()
 	}
 
-	err = db.SendHeartbeat(db.ctx, client, hbInfo)
+	_, err = db.SendHeartbeat(db.ctx, client, hbInfo)
 	if err != nil && err.Error() != "Attempt to initiate heartbeat from non-leader" {
 		logger.Error("Failed to initiate heartbeat round", logger.Ctx{"address": db.dqlite.Address(), "error": err})
 		return nil
@@ -358,7 +660,7 @@ func dqliteNetworkDial(ctx context.Context, addr string, db *DqliteDB) (net.Conn
 		return nil, err
 	}
 
-	config, err := internalClient.TLSClientConfig(db.serverCert(), peerCert)
+	config, err := internalClient.TLSClientConfig(db.serverCert(), peerCert, db.alternateClusterCerts()...)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to parse TLS config: %w", err)
 	}
@@ -451,10 +753,8 @@ func dqliteNetworkDial(ctx context.Context, addr string, db *DqliteDB) (net.Conn
 
 // Stop closes the database and dqlite connection.
 func (db *DqliteDB) Stop() error {
-	db.statusLock.Lock()
 	db.cancel()
-	db.status = types.DatabaseOffline
-	db.statusLock.Unlock()
+	db.setStatus(types.DatabaseOffline)
 
 	if db.IsOpen(context.TODO()) == nil {
 		// The database might refuse to close if many nodes are stopping at the same time,
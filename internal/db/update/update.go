@@ -38,6 +38,13 @@ func NewSchema() *SchemaUpdateManager {
 			mgr.updateFromV3,
 			updateFromV4,
 			updateFromV5,
+			updateFromV6,
+			updateFromV7,
+			updateFromV8,
+			updateFromV9,
+			updateFromV10,
+			updateFromV11,
+			updateFromV12,
 		},
 	}
 
@@ -75,6 +82,138 @@ func (s *SchemaUpdateManager) AppendSchema(schemaExtensions []schema.Update, api
 	s.apiExtensions = apiExtensions
 }
 
+// updateFromV12 adds tables to track the rotation of the cluster certificate, and the phase each cluster member has
+// acknowledged.
+func updateFromV12(ctx context.Context, tx *sql.Tx) error {
+	stmt := `CREATE TABLE core_certificate_rotations (
+  id                    INTEGER   PRIMARY  KEY    AUTOINCREMENT  NOT  NULL,
+  fingerprint           TEXT      NOT      NULL,
+  previous_fingerprint  TEXT      NOT      NULL,
+  certificate           TEXT      NOT      NULL,
+  key                   TEXT      NOT      NULL,
+  phase                 TEXT      NOT      NULL,
+  grace_period          INTEGER   NOT      NULL,
+  started_at            DATETIME  NOT      NULL,
+  switched_at           DATETIME  DEFAULT  NULL,
+  completed_at          DATETIME  DEFAULT  NULL
+);
+CREATE TABLE core_certificate_rotation_members (
+  id           INTEGER  PRIMARY  KEY    AUTOINCREMENT  NOT  NULL,
+  rotation_id  INTEGER  NOT      NULL,
+  member       TEXT     NOT      NULL,
+  phase        TEXT     NOT      NULL,
+  UNIQUE (rotation_id, member)
+);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// updateFromV11 adds tables for the certificates of clients that are not cluster members, and for the tokens clients
+// can redeem to have their certificate trusted.
+func updateFromV11(ctx context.Context, tx *sql.Tx) error {
+	stmt := `CREATE TABLE core_client_certificates (
+  id           INTEGER  PRIMARY  KEY    AUTOINCREMENT  NOT  NULL,
+  name         TEXT     NOT      NULL,
+  fingerprint  TEXT     NOT      NULL,
+  certificate  TEXT     NOT      NULL,
+  UNIQUE (name),
+  UNIQUE (fingerprint)
+);
+CREATE TABLE core_client_tokens (
+  id           INTEGER   PRIMARY  KEY    AUTOINCREMENT  NOT  NULL,
+  name         TEXT      NOT      NULL,
+  secret       TEXT      NOT      NULL,
+  expiry_date  DATETIME  DEFAULT  NULL,
+  UNIQUE (name),
+  UNIQUE (secret)
+);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// updateFromV10 adds tables for the authorization policy. Unix socket peers keep being granted every entitlement.
+func updateFromV10(ctx context.Context, tx *sql.Tx) error {
+	stmt := `CREATE TABLE core_auth_roles (
+  id           INTEGER  PRIMARY  KEY    AUTOINCREMENT  NOT  NULL,
+  name         TEXT     NOT      NULL,
+  entitlement  TEXT     NOT      NULL,
+  UNIQUE (name, entitlement)
+);
+CREATE TABLE core_auth_bindings (
+  id             INTEGER  PRIMARY  KEY    AUTOINCREMENT  NOT  NULL,
+  role           TEXT     NOT      NULL,
+  identity_type  TEXT     NOT      NULL,
+  identity       TEXT     NOT      NULL,
+  UNIQUE (role, identity_type, identity)
+);
+INSERT INTO core_auth_bindings (role, identity_type, identity) VALUES ('admin', 'unix', '*');
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// updateFromV9 adds a table for configuration. Cluster-wide keys have an empty member, while other keys are set per
+// cluster member.
+func updateFromV9(ctx context.Context, tx *sql.Tx) error {
+	stmt := `CREATE TABLE core_config (
+  id      INTEGER  PRIMARY  KEY    AUTOINCREMENT  NOT  NULL,
+  key     TEXT     NOT      NULL,
+  value   TEXT     NOT      NULL,
+  member  TEXT     NOT      NULL   DEFAULT '',
+  UNIQUE  (key, member)
+);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// updateFromV8 adds a maintenance flag to cluster members.
+func updateFromV8(ctx context.Context, tx *sql.Tx) error {
+	stmt := `ALTER TABLE core_cluster_members ADD COLUMN maintenance INTEGER NOT NULL DEFAULT 0;`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// updateFromV7 adds labels and a failure domain to cluster members.
+func updateFromV7(ctx context.Context, tx *sql.Tx) error {
+	stmt := `ALTER TABLE core_cluster_members ADD COLUMN labels TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE core_cluster_members ADD COLUMN failure_domain TEXT NOT NULL DEFAULT '';
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// updateFromV6 adds a table for cluster-wide leases.
+func updateFromV6(ctx context.Context, tx *sql.Tx) error {
+	stmt := `CREATE TABLE core_leases (
+  id           INTEGER   PRIMARY  KEY    AUTOINCREMENT  NOT  NULL,
+  name         TEXT      NOT      NULL,
+  holder       TEXT      NOT      NULL,
+  token        INTEGER   NOT      NULL,
+  expiry_date  DATETIME  NOT      NULL,
+  UNIQUE       (name)
+);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
 // updateFromV5 adds an expiration column for join tokens.
 func updateFromV5(ctx context.Context, tx *sql.Tx) error {
 	stmt := `CREATE TABLE core_token_records_new (
@@ -0,0 +1,182 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// writeTimeout is the maximum time allowed to write a single event to a listener.
+const writeTimeout = 5 * time.Second
+
+// queueSize is the number of events that can be waiting to be written to a listener.
+// Listeners that fall further behind are disconnected.
+const queueSize = 256
+
+// Server maintains the set of listeners subscribed to the local event stream.
+type Server struct {
+	lock      sync.RWMutex
+	listeners map[string]*Listener
+}
+
+// Listener is a single websocket connection subscribed to the event stream.
+type Listener struct {
+	id         string
+	conn       *websocket.Conn
+	eventTypes []types.EventType
+	queue      chan types.Event
+
+	lock   sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewServer returns a new event server with no listeners.
+func NewServer() *Server {
+	return &Server{listeners: map[string]*Listener{}}
+}
+
+// AddListener subscribes the given websocket connection to events of the given types.
+// If no types are given, the listener receives all events.
+func (s *Server) AddListener(conn *websocket.Conn, eventTypes []types.EventType) *Listener {
+	ctx, cancel := context.WithCancel(context.Background())
+	listener := &Listener{
+		id:         uuid.New().String(),
+		conn:       conn,
+		eventTypes: eventTypes,
+		queue:      make(chan types.Event, queueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+
+	s.lock.Lock()
+	s.listeners[listener.id] = listener
+	s.lock.Unlock()
+
+	// Read from the connection so that control messages are handled and we can tell when the client goes away.
+	go func() {
+		defer s.removeListener(listener)
+
+		for {
+			_, _, err := conn.NextReader()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// Write the events from a single goroutine so that they arrive in the order they were sent.
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-listener.queue:
+				err := listener.send(event)
+				if err != nil {
+					logger.Debug("Failed to send event to listener", logger.Ctx{"id": listener.id, "error": err})
+					s.removeListener(listener)
+					return
+				}
+			}
+		}
+	}()
+
+	logger.Debug("New event listener", logger.Ctx{"id": listener.id, "remote": conn.RemoteAddr().String()})
+
+	return listener
+}
+
+// Send dispatches the event to all local listeners subscribed to its type.
+func (s *Server) Send(event types.Event) {
+	s.lock.RLock()
+	listeners := make([]*Listener, 0, len(s.listeners))
+	for _, listener := range s.listeners {
+		listeners = append(listeners, listener)
+	}
+
+	s.lock.RUnlock()
+
+	for _, listener := range listeners {
+		if !listener.subscribed(event.Type) {
+			continue
+		}
+
+		select {
+		case listener.queue <- event:
+		default:
+			// Don't let a slow listener hold up the others, or miss events without noticing.
+			logger.Warn("Disconnecting event listener that is falling behind", logger.Ctx{"id": listener.id})
+			go s.removeListener(listener)
+		}
+	}
+}
+
+// Close disconnects all listeners.
+func (s *Server) Close() {
+	s.lock.RLock()
+	listeners := make([]*Listener, 0, len(s.listeners))
+	for _, listener := range s.listeners {
+		listeners = append(listeners, listener)
+	}
+
+	s.lock.RUnlock()
+
+	for _, listener := range listeners {
+		s.removeListener(listener)
+	}
+}
+
+// removeListener disconnects the listener and removes it from the server.
+func (s *Server) removeListener(listener *Listener) {
+	s.lock.Lock()
+	_, ok := s.listeners[listener.id]
+	delete(s.listeners, listener.id)
+	s.lock.Unlock()
+
+	if !ok {
+		return
+	}
+
+	listener.lock.Lock()
+	_ = listener.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(writeTimeout))
+	_ = listener.conn.Close()
+	listener.lock.Unlock()
+
+	listener.cancel()
+
+	logger.Debug("Event listener disconnected", logger.Ctx{"id": listener.id})
+}
+
+// Wait blocks until the listener is disconnected or the given context is cancelled.
+func (l *Listener) Wait(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-l.ctx.Done():
+	}
+}
+
+// subscribed returns whether the listener should receive events of the given type.
+func (l *Listener) subscribed(eventType types.EventType) bool {
+	return len(l.eventTypes) == 0 || shared.ValueInSlice(eventType, l.eventTypes)
+}
+
+// send writes the event to the listener's websocket connection.
+func (l *Listener) send(event types.Event) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	err := l.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err != nil {
+		return err
+	}
+
+	return l.conn.WriteJSON(event)
+}
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared/ws"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type eventsSuite struct {
+	suite.Suite
+}
+
+func TestEventsSuite(t *testing.T) {
+	suite.Run(t, new(eventsSuite))
+}
+
+// listen returns a websocket client subscribed to the given server with the given event types.
+func (t *eventsSuite) listen(server *Server, eventTypes []types.EventType) *websocket.Conn {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.Upgrader.Upgrade(w, r, nil)
+		t.Require().NoError(err)
+
+		server.AddListener(conn, eventTypes)
+	}))
+
+	t.T().Cleanup(httpServer.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	t.Require().NoError(err)
+
+	t.T().Cleanup(func() { _ = conn.Close() })
+
+	// Wait for the listener to be registered.
+	t.Require().Eventually(func() bool {
+		server.lock.RLock()
+		defer server.lock.RUnlock()
+
+		return len(server.listeners) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return conn
+}
+
+func (t *eventsSuite) Test_send() {
+	cases := []struct {
+		name       string
+		eventTypes []types.EventType
+		send       []types.EventType
+		expect     types.EventType
+	}{
+		{
+			name:   "All event types",
+			send:   []types.EventType{types.EventMemberJoined},
+			expect: types.EventMemberJoined,
+		},
+		{
+			name:       "Filtered event types",
+			eventTypes: []types.EventType{types.EventMemberRemoved},
+			send:       []types.EventType{types.EventMemberJoined, types.EventMemberRemoved},
+			expect:     types.EventMemberRemoved,
+		},
+		{
+			name:       "Custom event types",
+			eventTypes: []types.EventType{"custom"},
+			send:       []types.EventType{types.EventLeaderChanged, "custom"},
+			expect:     "custom",
+		},
+	}
+
+	for i, c := range cases {
+		t.T().Logf("%s (case %d)", c.name, i)
+
+		server := NewServer()
+		conn := t.listen(server, c.eventTypes)
+
+		for _, eventType := range c.send {
+			server.Send(types.Event{Type: eventType, Location: "n0"})
+		}
+
+		event := types.Event{}
+		t.Require().NoError(conn.SetReadDeadline(time.Now().Add(5 * time.Second)))
+		t.Require().NoError(conn.ReadJSON(&event))
+		t.Equal(c.expect, event.Type)
+		t.Equal("n0", event.Location)
+
+		server.Close()
+	}
+}
+
+func (t *eventsSuite) Test_order() {
+	server := NewServer()
+	conn := t.listen(server, nil)
+
+	for i := 0; i < queueSize; i++ {
+		server.Send(types.Event{Type: types.EventMemberJoined, Location: strconv.Itoa(i)})
+	}
+
+	t.Require().NoError(conn.SetReadDeadline(time.Now().Add(5 * time.Second)))
+	for i := 0; i < queueSize; i++ {
+		event := types.Event{}
+		t.Require().NoError(conn.ReadJSON(&event))
+		t.Equal(strconv.Itoa(i), event.Location)
+	}
+
+	server.Close()
+}
+
+func (t *eventsSuite) Test_overflow() {
+	server := NewServer()
+	conn := t.listen(server, nil)
+
+	// The client never reads, so the listener falls behind once the socket buffers are full.
+	t.Require().Eventually(func() bool {
+		server.Send(types.Event{Type: types.EventMemberJoined, Location: strings.Repeat("n", 64*1024)})
+
+		server.lock.RLock()
+		defer server.lock.RUnlock()
+
+		return len(server.listeners) == 0
+	}, 30*time.Second, time.Millisecond)
+
+	_ = conn.Close()
+}
+
+func (t *eventsSuite) Test_close() {
+	server := NewServer()
+	conn := t.listen(server, nil)
+
+	server.Close()
+
+	t.Require().NoError(conn.SetReadDeadline(time.Now().Add(5 * time.Second)))
+	_, _, err := conn.ReadMessage()
+	t.True(websocket.IsCloseError(err, websocket.CloseNormalClosure))
+
+	server.lock.RLock()
+	t.Len(server.listeners, 0)
+	server.lock.RUnlock()
+}
@@ -30,6 +30,26 @@ type Extensions []string
 var internalExtensions = Extensions{
 	"internal:runtime_extension_v1",
 	"internal:rename_core_endpoints",
+	"internal:events",
+	"internal:operations",
+	"internal:tasks",
+	"internal:leases",
+	"internal:database_backup",
+	"internal:recovery_push",
+	"internal:member_labels",
+	"internal:member_roles",
+	"internal:member_maintenance",
+	"internal:leader_change",
+	"internal:heartbeat_data",
+	"internal:failure_detector",
+	"internal:heartbeat_config",
+	"internal:cluster_config",
+	"internal:member_config",
+	"internal:openapi",
+	"internal:auth",
+	"internal:client_certificates",
+	"internal:certificate_rotation",
+	"internal:certificate_expiry",
 }
 
 // validateExternalExtension validates the given external extension.
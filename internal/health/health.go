@@ -0,0 +1,248 @@
+package health
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+const (
+	// DefaultSuspectThreshold is the default suspicion level above which a cluster member is suspected to have failed.
+	DefaultSuspectThreshold float64 = 3
+
+	// DefaultUnreachableThreshold is the default suspicion level above which a cluster member is considered unreachable.
+	DefaultUnreachableThreshold float64 = 8
+
+	// DefaultWindowSize is the default number of heartbeat intervals kept for each cluster member.
+	DefaultWindowSize int = 100
+)
+
+// Config configures the accrual failure detector used to derive the status of cluster members from heartbeats.
+type Config struct {
+	// SuspectThreshold is the suspicion level above which a cluster member is given the SUSPECT status.
+	// Defaults to DefaultSuspectThreshold if zero.
+	SuspectThreshold float64
+
+	// UnreachableThreshold is the suspicion level above which a cluster member is given the UNREACHABLE status.
+	// Defaults to DefaultUnreachableThreshold if zero.
+	UnreachableThreshold float64
+
+	// WindowSize is the number of most recent heartbeat intervals used to estimate when the next heartbeat is due.
+	// Defaults to DefaultWindowSize if zero.
+	WindowSize int
+}
+
+// Validate applies the defaults to unset fields and checks that the thresholds are consistent.
+func (c *Config) Validate() error {
+	if c.SuspectThreshold == 0 {
+		c.SuspectThreshold = DefaultSuspectThreshold
+	}
+
+	if c.UnreachableThreshold == 0 {
+		c.UnreachableThreshold = DefaultUnreachableThreshold
+	}
+
+	if c.WindowSize == 0 {
+		c.WindowSize = DefaultWindowSize
+	}
+
+	if c.SuspectThreshold < 0 {
+		return fmt.Errorf("Suspect threshold %v must be positive", c.SuspectThreshold)
+	}
+
+	if c.UnreachableThreshold <= c.SuspectThreshold {
+		return fmt.Errorf("Unreachable threshold %v must be greater than the suspect threshold %v", c.UnreachableThreshold, c.SuspectThreshold)
+	}
+
+	if c.WindowSize < 2 {
+		return fmt.Errorf("Window size %d must be at least 2", c.WindowSize)
+	}
+
+	return nil
+}
+
+// Member is a summary of the heartbeats answered by a cluster member.
+type Member struct {
+	// LastSeen is when the cluster member last answered a heartbeat.
+	LastSeen time.Time `json:"last_seen" yaml:"last_seen"`
+
+	// RTT is the round-trip time of the last heartbeat answered by the cluster member.
+	RTT time.Duration `json:"rtt" yaml:"rtt"`
+
+	// MissedHeartbeats is the number of consecutive heartbeats the cluster member failed to answer.
+	MissedHeartbeats int `json:"missed_heartbeats" yaml:"missed_heartbeats"`
+
+	// IntervalMean is the mean time between heartbeats answered by the cluster member. Zero if not yet known.
+	IntervalMean time.Duration `json:"interval_mean" yaml:"interval_mean"`
+
+	// IntervalStdDev is the standard deviation of the time between heartbeats answered by the cluster member.
+	IntervalStdDev time.Duration `json:"interval_std_dev" yaml:"interval_std_dev"`
+}
+
+// Phi returns the suspicion level that the cluster member has failed, given how long it has been since it was last seen
+// and the expected heartbeat interval. The suspicion level grows the longer the next heartbeat is overdue, compared to
+// the usual variation in heartbeat intervals. A level of 1 means roughly a 10% chance of a false positive, 2 means 1%, and so on.
+func (m Member) Phi(now time.Time, heartbeatInterval time.Duration) float64 {
+	mean := m.IntervalMean
+	if mean == 0 {
+		mean = heartbeatInterval
+	}
+
+	// Don't let a very regular cluster member become suspect as soon as a heartbeat runs a little late.
+	stdDev := max(m.IntervalStdDev, heartbeatInterval/2, time.Millisecond)
+
+	elapsed := now.Sub(m.LastSeen)
+
+	// Logistic approximation of the cumulative normal distribution.
+	y := float64(elapsed-mean) / float64(stdDev)
+	e := math.Exp(-y * (1.5976 + 0.070566*y*y))
+	if elapsed > mean {
+		return -math.Log10(e / (1 + e))
+	}
+
+	return -math.Log10(1 - 1/(1+e))
+}
+
+// history records the heartbeats answered by a cluster member.
+type history struct {
+	member    Member
+	intervals []time.Duration
+}
+
+// Detector is an accrual failure detector. It is fed by the heartbeats sent by the leader, and estimates how likely it is
+// that each cluster member has failed based on how overdue its next heartbeat is.
+type Detector struct {
+	config            Config
+	heartbeatInterval func() time.Duration
+
+	lock    sync.Mutex
+	members map[string]*history
+}
+
+// NewDetector returns a failure detector with the given validated configuration.
+func NewDetector(config Config, heartbeatInterval func() time.Duration) *Detector {
+	return &Detector{
+		config:            config,
+		heartbeatInterval: heartbeatInterval,
+		members:           map[string]*history{},
+	}
+}
+
+// Heartbeat records that the named cluster member answered a heartbeat at the given time, with the given round-trip time.
+func (d *Detector) Heartbeat(name string, at time.Time, rtt time.Duration) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	h, ok := d.members[name]
+	if !ok {
+		h = &history{}
+		d.members[name] = h
+	}
+
+	if !h.member.LastSeen.IsZero() && at.After(h.member.LastSeen) {
+		h.intervals = append(h.intervals, at.Sub(h.member.LastSeen))
+		if len(h.intervals) > d.config.WindowSize {
+			h.intervals = h.intervals[len(h.intervals)-d.config.WindowSize:]
+		}
+	}
+
+	h.member.LastSeen = at
+	h.member.RTT = rtt
+	h.member.MissedHeartbeats = 0
+
+	// Until a few intervals have been recorded, keep the estimates learned from the previous leader, if any.
+	if len(h.intervals) >= 2 {
+		h.member.IntervalMean, h.member.IntervalStdDev = meanStdDev(h.intervals)
+	}
+}
+
+// Missed records that the named cluster member failed to answer a heartbeat.
+func (d *Detector) Missed(name string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	h, ok := d.members[name]
+	if !ok {
+		h = &history{}
+		d.members[name] = h
+	}
+
+	h.member.MissedHeartbeats++
+}
+
+// Retain forgets about all cluster members not in the given list.
+func (d *Detector) Retain(names []string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	keep := make(map[string]bool, len(names))
+	for _, name := range names {
+		keep[name] = true
+	}
+
+	for name := range d.members {
+		if !keep[name] {
+			delete(d.members, name)
+		}
+	}
+}
+
+// Snapshot returns a summary of the heartbeats answered by each cluster member, keyed by name.
+func (d *Detector) Snapshot() map[string]Member {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	snapshot := make(map[string]Member, len(d.members))
+	for name, h := range d.members {
+		snapshot[name] = h.member
+	}
+
+	return snapshot
+}
+
+// Load replaces the recorded heartbeats with the given snapshot, as sent by the leader.
+// If this cluster member later becomes the leader, it carries on from the snapshot.
+func (d *Detector) Load(snapshot map[string]Member) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.members = make(map[string]*history, len(snapshot))
+	for name, member := range snapshot {
+		d.members[name] = &history{member: member}
+	}
+}
+
+// Status returns the status of the cluster member according to its suspicion level, along with the suspicion level.
+func (d *Detector) Status(member Member, now time.Time) (types.MemberStatus, float64) {
+	phi := member.Phi(now, d.heartbeatInterval())
+	switch {
+	case phi >= d.config.UnreachableThreshold:
+		return types.MemberUnreachable, phi
+	case phi >= d.config.SuspectThreshold:
+		return types.MemberSuspect, phi
+	default:
+		return types.MemberOnline, phi
+	}
+}
+
+// meanStdDev returns the mean and standard deviation of the given durations.
+func meanStdDev(durations []time.Duration) (mean time.Duration, stdDev time.Duration) {
+	var sum float64
+	for _, d := range durations {
+		sum += float64(d)
+	}
+
+	avg := sum / float64(len(durations))
+
+	var variance float64
+	for _, d := range durations {
+		variance += (float64(d) - avg) * (float64(d) - avg)
+	}
+
+	variance /= float64(len(durations))
+
+	return time.Duration(avg), time.Duration(math.Sqrt(variance))
+}
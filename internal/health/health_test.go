@@ -0,0 +1,103 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type healthSuite struct {
+	suite.Suite
+}
+
+func TestHealthSuite(t *testing.T) {
+	suite.Run(t, new(healthSuite))
+}
+
+func (s *healthSuite) Test_configValidate() {
+	config := Config{}
+	s.NoError(config.Validate())
+	s.Equal(Config{SuspectThreshold: DefaultSuspectThreshold, UnreachableThreshold: DefaultUnreachableThreshold, WindowSize: DefaultWindowSize}, config)
+
+	config = Config{SuspectThreshold: 5, UnreachableThreshold: 4}
+	s.Error(config.Validate())
+
+	config = Config{SuspectThreshold: -1}
+	s.Error(config.Validate())
+
+	config = Config{WindowSize: 1}
+	s.Error(config.Validate())
+}
+
+func (s *healthSuite) Test_detectorStatus() {
+	interval := 10 * time.Second
+	config := Config{}
+	s.Require().NoError(config.Validate())
+
+	detector := NewDetector(config, func() time.Duration { return interval })
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		detector.Heartbeat("n1", start.Add(time.Duration(i)*interval), 5*time.Millisecond)
+	}
+
+	lastSeen := start.Add(9 * interval)
+	member := detector.Snapshot()["n1"]
+	s.Equal(lastSeen, member.LastSeen)
+	s.Equal(5*time.Millisecond, member.RTT)
+	s.Equal(interval, member.IntervalMean)
+	s.Equal(time.Duration(0), member.IntervalStdDev)
+
+	// A heartbeat that is a little late is not suspicious.
+	status, phi := detector.Status(member, lastSeen.Add(interval+3*time.Second))
+	s.Equal(types.MemberOnline, status)
+	s.Less(phi, config.SuspectThreshold)
+
+	// A single missed heartbeat is suspicious, but not enough to be considered unreachable.
+	detector.Missed("n1")
+	member = detector.Snapshot()["n1"]
+	s.Equal(1, member.MissedHeartbeats)
+
+	status, _ = detector.Status(member, lastSeen.Add(3*interval))
+	s.Equal(types.MemberSuspect, status)
+
+	status, _ = detector.Status(member, lastSeen.Add(5*interval))
+	s.Equal(types.MemberUnreachable, status)
+
+	// Answering a heartbeat clears the missed heartbeats.
+	detector.Heartbeat("n1", lastSeen.Add(interval), time.Millisecond)
+	s.Equal(0, detector.Snapshot()["n1"].MissedHeartbeats)
+}
+
+func (s *healthSuite) Test_detectorLoadAndRetain() {
+	interval := 10 * time.Second
+	config := Config{WindowSize: 2}
+	s.Require().NoError(config.Validate())
+
+	detector := NewDetector(config, func() time.Duration { return interval })
+
+	now := time.Now()
+	detector.Load(map[string]Member{
+		"n1": {LastSeen: now, IntervalMean: 20 * time.Second, IntervalStdDev: time.Second},
+		"n2": {LastSeen: now},
+	})
+
+	// Estimates loaded from the leader are kept until enough intervals have been recorded.
+	detector.Heartbeat("n1", now.Add(interval), 0)
+	s.Equal(20*time.Second, detector.Snapshot()["n1"].IntervalMean)
+
+	detector.Heartbeat("n1", now.Add(2*interval), 0)
+	s.Equal(interval, detector.Snapshot()["n1"].IntervalMean)
+
+	// Only the most recent intervals within the window are used.
+	detector.Heartbeat("n1", now.Add(4*interval), 0)
+	detector.Heartbeat("n1", now.Add(6*interval), 0)
+	s.Equal(2*interval, detector.Snapshot()["n1"].IntervalMean)
+
+	detector.Retain([]string{"n1"})
+	s.Len(detector.Snapshot(), 1)
+	s.Contains(detector.Snapshot(), "n1")
+}
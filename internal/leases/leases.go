@@ -0,0 +1,279 @@
+package leases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/db"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// heartbeatGracePeriods is the number of heartbeat intervals after which the leases of a cluster member
+// that has stopped heartbeating are considered expired.
+const heartbeatGracePeriods = 5
+
+// Manager grants cluster-wide leases backed by the core_leases table.
+// A lease is held by at most one cluster member at a time, until it is released, its TTL passes,
+// or its holder is removed from the cluster or stops heartbeating.
+type Manager struct {
+	db                db.DB
+	name              func() string
+	heartbeatInterval func() time.Duration
+}
+
+// NewManager returns a lease manager for the local cluster member.
+func NewManager(database db.DB, name func() string, heartbeatInterval func() time.Duration) *Manager {
+	return &Manager{
+		db:                database,
+		name:              name,
+		heartbeatInterval: heartbeatInterval,
+	}
+}
+
+// Acquire takes the named lease for the local cluster member for the given duration.
+// A 409 error is returned if the lease is currently held, including by the local cluster member.
+// The returned token is larger than that of any previous holder of the lease, and can be used for fencing.
+func (m *Manager) Acquire(ctx context.Context, name string, ttl time.Duration) (*types.Lease, error) {
+	if name == "" {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Lease name cannot be empty")
+	}
+
+	if ttl <= 0 {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Lease TTL must be positive")
+	}
+
+	var lease *cluster.CoreLease
+	err := m.db.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		lease, err = acquire(ctx, tx, name, m.name(), ttl, m.staleAfter(), time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Acquired lease", logger.Ctx{"name": name, "token": lease.Token, "expiry": lease.ExpiryDate})
+
+	return toAPI(*lease, false), nil
+}
+
+// Renew extends the named lease held by the local cluster member with the given token for the given duration.
+// A 409 error is returned if the lease is no longer held with that token.
+func (m *Manager) Renew(ctx context.Context, name string, token int64, ttl time.Duration) (*types.Lease, error) {
+	if ttl <= 0 {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Lease TTL must be positive")
+	}
+
+	var lease *cluster.CoreLease
+	err := m.db.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		lease, err = renew(ctx, tx, name, m.name(), token, ttl, m.staleAfter(), time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toAPI(*lease, false), nil
+}
+
+// Release gives up the named lease held by the local cluster member with the given token.
+// Releasing a lease that has already expired is not an error, but a 409 error is returned if it has since been acquired by another holder.
+func (m *Manager) Release(ctx context.Context, name string, token int64) error {
+	return m.db.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		return release(ctx, tx, name, m.name(), token, time.Now().UTC())
+	})
+}
+
+// Break forcibly expires the named lease, regardless of its holder.
+func (m *Manager) Break(ctx context.Context, name string) error {
+	err := m.db.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		lease, err := cluster.GetCoreLease(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
+		lease.ExpiryDate = time.Now().UTC()
+
+		return cluster.UpdateCoreLease(ctx, tx, lease.Name, *lease)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Warn("Lease was forcibly broken", logger.Ctx{"name": name})
+
+	return nil
+}
+
+// Get returns the current state of the named lease.
+func (m *Manager) Get(ctx context.Context, name string) (*types.Lease, error) {
+	var apiLease *types.Lease
+	err := m.db.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		lease, err := cluster.GetCoreLease(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
+		active, err := isActive(ctx, tx, *lease, m.staleAfter(), time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		apiLease = toAPI(*lease, !active)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return apiLease, nil
+}
+
+// List returns the current state of all leases.
+func (m *Manager) List(ctx context.Context) ([]types.Lease, error) {
+	var apiLeases []types.Lease
+	err := m.db.Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		leases, err := cluster.GetCoreLeases(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		apiLeases = make([]types.Lease, 0, len(leases))
+		for _, lease := range leases {
+			active, err := isActive(ctx, tx, lease, m.staleAfter(), now)
+			if err != nil {
+				return err
+			}
+
+			apiLeases = append(apiLeases, *toAPI(lease, !active))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return apiLeases, nil
+}
+
+// staleAfter returns how long after its last heartbeat a cluster member loses its leases.
+func (m *Manager) staleAfter() time.Duration {
+	return heartbeatGracePeriods * m.heartbeatInterval()
+}
+
+// acquire records the holder of the named lease if it is not currently held.
+func acquire(ctx context.Context, tx *sql.Tx, name string, holder string, ttl time.Duration, staleAfter time.Duration, now time.Time) (*cluster.CoreLease, error) {
+	lease, err := cluster.GetCoreLease(ctx, tx, name)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return nil, err
+	}
+
+	if lease == nil {
+		lease = &cluster.CoreLease{Name: name, Holder: holder, Token: 1, ExpiryDate: now.Add(ttl)}
+		_, err = cluster.CreateCoreLease(ctx, tx, *lease)
+
+		return lease, err
+	}
+
+	active, err := isActive(ctx, tx, *lease, staleAfter, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if active {
+		return nil, api.StatusErrorf(http.StatusConflict, "Lease %q is held by %q until %s", name, lease.Holder, lease.ExpiryDate.Format(time.RFC3339))
+	}
+
+	lease.Holder = holder
+	lease.Token++
+	lease.ExpiryDate = now.Add(ttl)
+
+	return lease, cluster.UpdateCoreLease(ctx, tx, lease.Name, *lease)
+}
+
+// renew extends the expiry of the named lease if it is still held by the holder with the given token.
+func renew(ctx context.Context, tx *sql.Tx, name string, holder string, token int64, ttl time.Duration, staleAfter time.Duration, now time.Time) (*cluster.CoreLease, error) {
+	lease, err := cluster.GetCoreLease(ctx, tx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := isActive(ctx, tx, *lease, staleAfter, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if !active || lease.Holder != holder || lease.Token != token {
+		return nil, api.StatusErrorf(http.StatusConflict, "Lease %q is no longer held with token %d", name, token)
+	}
+
+	lease.ExpiryDate = now.Add(ttl)
+
+	return lease, cluster.UpdateCoreLease(ctx, tx, lease.Name, *lease)
+}
+
+// release expires the named lease if it is held by the holder with the given token.
+func release(ctx context.Context, tx *sql.Tx, name string, holder string, token int64, now time.Time) error {
+	lease, err := cluster.GetCoreLease(ctx, tx, name)
+	if err != nil {
+		return err
+	}
+
+	if lease.Holder != holder || lease.Token != token {
+		return api.StatusErrorf(http.StatusConflict, "Lease %q is no longer held with token %d", name, token)
+	}
+
+	if !now.Before(lease.ExpiryDate) {
+		return nil
+	}
+
+	lease.ExpiryDate = now
+
+	return cluster.UpdateCoreLease(ctx, tx, lease.Name, *lease)
+}
+
+// isActive returns whether the lease is still held. A lease is no longer held once it expires,
+// or if its holder has been removed from the cluster or has not heartbeated within the staleAfter duration.
+func isActive(ctx context.Context, tx *sql.Tx, lease cluster.CoreLease, staleAfter time.Duration, now time.Time) (bool, error) {
+	if !now.Before(lease.ExpiryDate) {
+		return false, nil
+	}
+
+	member, err := cluster.GetCoreClusterMember(ctx, tx, lease.Holder)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("Failed to get lease holder %q: %w", lease.Holder, err)
+	}
+
+	// Newly joined members have not been sent a heartbeat yet.
+	if !member.Heartbeat.IsZero() && now.Sub(member.Heartbeat) > staleAfter {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// toAPI returns the API representation of the lease.
+func toAPI(lease cluster.CoreLease, expired bool) *types.Lease {
+	return &types.Lease{
+		Name:      lease.Name,
+		Holder:    lease.Holder,
+		Token:     lease.Token,
+		ExpiresAt: lease.ExpiryDate,
+		Expired:   expired,
+	}
+}
@@ -0,0 +1,194 @@
+package leases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/db/update"
+)
+
+type leasesSuite struct {
+	suite.Suite
+
+	db *sql.DB
+}
+
+func TestLeasesSuite(t *testing.T) {
+	suite.Run(t, new(leasesSuite))
+}
+
+// newTestDB returns a sqlite DB set up with the default microcluster schema.
+func newTestDB() (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each connection to an in-memory database has its own data.
+	db.SetMaxOpenConns(1)
+
+	_, err = update.NewSchema().Schema().Ensure(db)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cluster.PrepareStmts(db, cluster.GetCallerProject(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (s *leasesSuite) SetupTest() {
+	var err error
+	s.db, err = newTestDB()
+	s.Require().NoError(err)
+
+	now := time.Now().UTC()
+	s.transaction(func(ctx context.Context, tx *sql.Tx) error {
+		for i, heartbeat := range []time.Time{now, now, now.Add(-time.Hour), {}} {
+			member := cluster.CoreClusterMember{
+				Name:        fmt.Sprintf("n%d", i),
+				Address:     fmt.Sprintf("10.0.0.%d:8443", i),
+				Certificate: fmt.Sprintf("cert-%d", i),
+				Heartbeat:   heartbeat,
+				Role:        cluster.Pending,
+			}
+
+			_, err := cluster.CreateCoreClusterMember(ctx, tx, member)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *leasesSuite) TearDownTest() {
+	s.NoError(s.db.Close())
+}
+
+func (s *leasesSuite) transaction(f func(ctx context.Context, tx *sql.Tx) error) {
+	s.Require().NoError(query.Transaction(context.Background(), s.db, f))
+}
+
+func (s *leasesSuite) Test_acquireRenewRelease() {
+	staleAfter := time.Minute
+	now := time.Now().UTC()
+
+	s.transaction(func(ctx context.Context, tx *sql.Tx) error {
+		lease, err := acquire(ctx, tx, "ovn", "n0", time.Minute, staleAfter, now)
+		s.Require().NoError(err)
+		s.Equal(int64(1), lease.Token)
+		s.Equal(now.Add(time.Minute), lease.ExpiryDate)
+
+		// The lease can't be taken by another member, or again by its holder.
+		for _, holder := range []string{"n0", "n1"} {
+			_, err = acquire(ctx, tx, "ovn", holder, time.Minute, staleAfter, now)
+			s.True(api.StatusErrorCheck(err, http.StatusConflict))
+		}
+
+		// Leases are independent of one another.
+		other, err := acquire(ctx, tx, "other", "n1", time.Minute, staleAfter, now)
+		s.Require().NoError(err)
+		s.Equal(int64(1), other.Token)
+
+		_, err = renew(ctx, tx, "ovn", "n0", 2, time.Minute, staleAfter, now)
+		s.True(api.StatusErrorCheck(err, http.StatusConflict))
+
+		lease, err = renew(ctx, tx, "ovn", "n0", 1, 2*time.Minute, staleAfter, now)
+		s.Require().NoError(err)
+		s.Equal(now.Add(2*time.Minute), lease.ExpiryDate)
+
+		s.True(api.StatusErrorCheck(release(ctx, tx, "ovn", "n1", 1, now), http.StatusConflict))
+		s.NoError(release(ctx, tx, "ovn", "n0", 1, now))
+
+		// A released lease can no longer be renewed, and its next holder gets a larger token.
+		_, err = renew(ctx, tx, "ovn", "n0", 1, time.Minute, staleAfter, now)
+		s.True(api.StatusErrorCheck(err, http.StatusConflict))
+
+		lease, err = acquire(ctx, tx, "ovn", "n1", time.Minute, staleAfter, now)
+		s.Require().NoError(err)
+		s.Equal(int64(2), lease.Token)
+
+		// The previous holder can't release the lease once it has changed hands.
+		s.True(api.StatusErrorCheck(release(ctx, tx, "ovn", "n0", 1, now), http.StatusConflict))
+
+		return nil
+	})
+}
+
+func (s *leasesSuite) Test_expiry() {
+	staleAfter := time.Minute
+	now := time.Now().UTC()
+
+	cases := []struct {
+		name     string
+		holder   string
+		ttl      time.Duration
+		acquired time.Time
+	}{
+		{
+			name:     "TTL has passed",
+			holder:   "n0",
+			ttl:      time.Minute,
+			acquired: now.Add(-2 * time.Minute),
+		},
+		{
+			name:     "Holder has stopped heartbeating",
+			holder:   "n2",
+			ttl:      time.Hour,
+			acquired: now,
+		},
+		{
+			name:     "Holder is not a cluster member",
+			holder:   "n9",
+			ttl:      time.Hour,
+			acquired: now,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%s (case %d)", c.name, i)
+
+		s.transaction(func(ctx context.Context, tx *sql.Tx) error {
+			name := fmt.Sprintf("lease-%d", i)
+			lease, err := acquire(ctx, tx, name, c.holder, c.ttl, staleAfter, c.acquired)
+			s.Require().NoError(err)
+
+			active, err := isActive(ctx, tx, *lease, staleAfter, now)
+			s.Require().NoError(err)
+			s.False(active)
+
+			lease, err = acquire(ctx, tx, name, "n1", time.Minute, staleAfter, now)
+			s.Require().NoError(err)
+			s.Equal(int64(2), lease.Token)
+
+			return nil
+		})
+	}
+
+	// Members that have not been sent a heartbeat yet keep their leases.
+	s.transaction(func(ctx context.Context, tx *sql.Tx) error {
+		lease, err := acquire(ctx, tx, "new-member", "n3", time.Minute, staleAfter, now)
+		s.Require().NoError(err)
+
+		active, err := isActive(ctx, tx, *lease, staleAfter, now)
+		s.Require().NoError(err)
+		s.True(active)
+
+		return nil
+	})
+}
@@ -0,0 +1,305 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/google/uuid"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// retention is how long a finished operation is kept before it is removed from the manager.
+const retention = 5 * time.Minute
+
+// deliveryTimeout is how long to wait for the result of a finished operation to be collected by a client.
+const deliveryTimeout = 30 * time.Second
+
+// RunFunc is the function executed by an operation.
+// The context is cancelled if the operation is cancelled or the daemon shuts down.
+type RunFunc func(ctx context.Context, op *Operation) error
+
+// Manager keeps track of the operations running on the local cluster member.
+type Manager struct {
+	lock       sync.RWMutex
+	operations map[string]*Operation
+
+	ctx      context.Context
+	location func() string
+	notify   func(op types.Operation)
+}
+
+// Operation is a long-running action started by an API request.
+type Operation struct {
+	lock        sync.RWMutex
+	id          string
+	description string
+	location    string
+	status      types.OperationStatus
+	createdAt   time.Time
+	updatedAt   time.Time
+	progress    *types.OperationProgress
+	metadata    map[string]any
+	cancellable bool
+	err         error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	done          chan struct{}
+	delivered     chan struct{}
+	deliveredOnce sync.Once
+
+	manager *Manager
+}
+
+// NewManager returns a new operation manager. Operations are cancelled when the given context is cancelled.
+// The notify function is called whenever an operation is created or updated.
+func NewManager(ctx context.Context, location func() string, notify func(op types.Operation)) *Manager {
+	return &Manager{
+		operations: map[string]*Operation{},
+		ctx:        ctx,
+		location:   location,
+		notify:     notify,
+	}
+}
+
+// Create starts a new operation in the background with the given description.
+// If cancellable is true, clients may cancel the operation which will cancel the context passed to run.
+func (m *Manager) Create(description string, cancellable bool, run RunFunc) (*Operation, error) {
+	if run == nil {
+		return nil, fmt.Errorf("Operation %q has no run function", description)
+	}
+
+	if m.ctx.Err() != nil {
+		return nil, api.StatusErrorf(http.StatusServiceUnavailable, "Daemon is shutting down")
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	now := time.Now().UTC()
+	op := &Operation{
+		id:          uuid.New().String(),
+		description: description,
+		location:    m.location(),
+		status:      types.OperationRunning,
+		createdAt:   now,
+		updatedAt:   now,
+		metadata:    map[string]any{},
+		cancellable: cancellable,
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+		delivered:   make(chan struct{}),
+		manager:     m,
+	}
+
+	m.lock.Lock()
+	m.operations[op.id] = op
+	m.lock.Unlock()
+
+	logger.Debug("New operation", logger.Ctx{"id": op.id, "description": description})
+	op.notify()
+
+	go op.start(run)
+
+	return op, nil
+}
+
+// Get returns the operation with the given ID.
+func (m *Manager) Get(id string) (*Operation, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	op, ok := m.operations[id]
+	if !ok {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Operation %q not found", id)
+	}
+
+	return op, nil
+}
+
+// List returns all operations known to the manager, sorted by creation time.
+func (m *Manager) List() []*Operation {
+	m.lock.RLock()
+	ops := make([]*Operation, 0, len(m.operations))
+	for _, op := range m.operations {
+		ops = append(ops, op)
+	}
+
+	m.lock.RUnlock()
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].createdAt.Before(ops[j].createdAt)
+	})
+
+	return ops
+}
+
+// start runs the operation and records its result.
+func (op *Operation) start(run RunFunc) {
+	err := run(op.ctx, op)
+
+	op.lock.Lock()
+	switch {
+	case err == nil:
+		op.status = types.OperationSuccess
+	case errors.Is(err, context.Canceled) && op.ctx.Err() != nil:
+		op.status = types.OperationCancelled
+		op.err = err
+	default:
+		op.status = types.OperationFailure
+		op.err = err
+	}
+
+	op.updatedAt = time.Now().UTC()
+	op.lock.Unlock()
+
+	if err != nil {
+		logger.Error("Operation failed", logger.Ctx{"id": op.id, "description": op.description, "error": err})
+	} else {
+		logger.Debug("Operation succeeded", logger.Ctx{"id": op.id, "description": op.description})
+	}
+
+	op.notify()
+	op.cancel()
+	close(op.done)
+
+	time.AfterFunc(deliveryTimeout, op.MarkDelivered)
+	time.AfterFunc(retention, func() {
+		op.manager.lock.Lock()
+		delete(op.manager.operations, op.id)
+		op.manager.lock.Unlock()
+	})
+}
+
+// notify sends the current state of the operation to the manager's notify function.
+func (op *Operation) notify() {
+	if op.manager.notify != nil {
+		op.manager.notify(op.ToAPI())
+	}
+}
+
+// ID returns the unique identifier of the operation.
+func (op *Operation) ID() string {
+	return op.id
+}
+
+// URL returns the API path of the operation.
+func (op *Operation) URL() string {
+	return api.NewURL().Path("core", "1.0", "operations", op.id).String()
+}
+
+// SetProgress records the current stage and completion percentage of the operation.
+func (op *Operation) SetProgress(stage string, percent int) {
+	op.lock.Lock()
+	op.progress = &types.OperationProgress{Stage: stage, Percent: percent}
+	op.updatedAt = time.Now().UTC()
+	op.lock.Unlock()
+
+	op.notify()
+}
+
+// UpdateMetadata merges the given values into the metadata of the operation.
+func (op *Operation) UpdateMetadata(metadata map[string]any) {
+	op.lock.Lock()
+	maps.Copy(op.metadata, metadata)
+	op.updatedAt = time.Now().UTC()
+	op.lock.Unlock()
+
+	op.notify()
+}
+
+// Cancel cancels the context of a running operation.
+func (op *Operation) Cancel() error {
+	op.lock.RLock()
+	status := op.status
+	cancellable := op.cancellable
+	op.lock.RUnlock()
+
+	if status.IsFinal() {
+		return api.StatusErrorf(http.StatusBadRequest, "Operation %q has already finished", op.id)
+	}
+
+	if !cancellable {
+		return api.StatusErrorf(http.StatusBadRequest, "Operation %q cannot be cancelled", op.id)
+	}
+
+	op.cancel()
+
+	return nil
+}
+
+// Wait blocks until the operation finishes or the context is cancelled.
+func (op *Operation) Wait(ctx context.Context) error {
+	select {
+	case <-op.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Done returns a channel that is closed when the operation finishes.
+func (op *Operation) Done() <-chan struct{} {
+	return op.done
+}
+
+// MarkDelivered records that the result of the finished operation has been sent to a client.
+func (op *Operation) MarkDelivered() {
+	select {
+	case <-op.done:
+	default:
+		return
+	}
+
+	op.deliveredOnce.Do(func() { close(op.delivered) })
+}
+
+// DeliveredContext returns a context that is cancelled once the operation has finished and its result
+// has been collected by a client, or some time after the operation finished if no client collected it.
+// This can be used to delay actions that would interrupt the daemon, like restarting it.
+func (op *Operation) DeliveredContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-op.delivered
+		cancel()
+	}()
+
+	return ctx
+}
+
+// ToAPI returns the API representation of the operation.
+func (op *Operation) ToAPI() types.Operation {
+	op.lock.RLock()
+	defer op.lock.RUnlock()
+
+	apiOp := types.Operation{
+		ID:          op.id,
+		Description: op.description,
+		Location:    op.location,
+		Status:      op.status,
+		CreatedAt:   op.createdAt,
+		UpdatedAt:   op.updatedAt,
+		Metadata:    maps.Clone(op.metadata),
+		MayCancel:   op.cancellable && !op.status.IsFinal(),
+	}
+
+	if op.progress != nil {
+		progress := *op.progress
+		apiOp.Progress = &progress
+	}
+
+	if op.err != nil {
+		apiOp.Err = op.err.Error()
+	}
+
+	return apiOp
+}
@@ -0,0 +1,159 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type operationsSuite struct {
+	suite.Suite
+}
+
+func TestOperationsSuite(t *testing.T) {
+	suite.Run(t, new(operationsSuite))
+}
+
+func (t *operationsSuite) Test_operationResult() {
+	cases := []struct {
+		name        string
+		cancellable bool
+		cancel      bool
+		runErr      error
+		expectErr   string
+		expect      types.OperationStatus
+	}{
+		{
+			name:   "Successful operation",
+			expect: types.OperationSuccess,
+		},
+		{
+			name:      "Failed operation",
+			runErr:    errors.New("Failed to do the thing"),
+			expect:    types.OperationFailure,
+			expectErr: "Failed to do the thing",
+		},
+		{
+			name:        "Cancelled operation",
+			cancellable: true,
+			cancel:      true,
+			expect:      types.OperationCancelled,
+			expectErr:   context.Canceled.Error(),
+		},
+	}
+
+	for i, c := range cases {
+		t.T().Logf("%s (case %d)", c.name, i)
+
+		notified := []types.Operation{}
+		m := NewManager(context.Background(), func() string { return "n0" }, func(op types.Operation) {
+			notified = append(notified, op)
+		})
+
+		release := make(chan struct{})
+		op, err := m.Create(c.name, c.cancellable, func(ctx context.Context, op *Operation) error {
+			op.SetProgress("Running", 50)
+
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			return c.runErr
+		})
+		t.Require().NoError(err)
+
+		found, err := m.Get(op.ID())
+		t.Require().NoError(err)
+		t.Equal(op, found)
+		t.Len(m.List(), 1)
+
+		if c.cancel {
+			t.NoError(op.Cancel())
+		} else {
+			close(release)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		t.Require().NoError(op.Wait(ctx))
+		cancel()
+
+		apiOp := op.ToAPI()
+		t.Equal(c.expect, apiOp.Status)
+		t.Equal(c.expectErr, apiOp.Err)
+		t.Equal("n0", apiOp.Location)
+		t.False(apiOp.MayCancel)
+		t.Equal("/core/1.0/operations/"+op.ID(), op.URL())
+
+		// Operations can't be cancelled once finished.
+		t.Error(op.Cancel())
+
+		// Updates are sent for creation, progress and completion.
+		t.Len(notified, 3)
+		t.Equal(types.OperationRunning, notified[0].Status)
+		t.Equal(&types.OperationProgress{Stage: "Running", Percent: 50}, notified[1].Progress)
+		t.Equal(c.expect, notified[2].Status)
+	}
+}
+
+func (t *operationsSuite) Test_notCancellable() {
+	m := NewManager(context.Background(), func() string { return "n0" }, nil)
+
+	release := make(chan struct{})
+	op, err := m.Create("Not cancellable", false, func(ctx context.Context, op *Operation) error {
+		<-release
+		return nil
+	})
+	t.Require().NoError(err)
+
+	err = op.Cancel()
+	t.True(api.StatusErrorCheck(err, http.StatusBadRequest))
+	close(release)
+
+	_, err = m.Get("missing")
+	t.True(api.StatusErrorCheck(err, http.StatusNotFound))
+}
+
+func (t *operationsSuite) Test_delivered() {
+	m := NewManager(context.Background(), func() string { return "n0" }, nil)
+
+	release := make(chan struct{})
+	op, err := m.Create("Delivery", false, func(ctx context.Context, op *Operation) error {
+		<-release
+		return nil
+	})
+	t.Require().NoError(err)
+
+	ctx := op.DeliveredContext()
+
+	// Marking a running operation as delivered does nothing.
+	op.MarkDelivered()
+	t.NoError(ctx.Err())
+
+	close(release)
+	<-op.Done()
+	op.MarkDelivered()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fail("Context was not cancelled after the result was delivered")
+	}
+}
+
+func (t *operationsSuite) Test_shutdown() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewManager(ctx, func() string { return "n0" }, nil)
+	cancel()
+
+	_, err := m.Create("Shutdown", false, func(ctx context.Context, op *Operation) error { return nil })
+	t.True(api.StatusErrorCheck(err, http.StatusServiceUnavailable))
+}
@@ -0,0 +1,49 @@
+package operations
+
+import (
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type operationResponse struct {
+	url string
+	op  func() types.Operation
+}
+
+// OperationResponse returns an asynchronous response pointing the client at the given operation.
+func OperationResponse(op *Operation) response.Response {
+	return &operationResponse{url: op.URL(), op: op.ToAPI}
+}
+
+// ForwardedOperationResponse returns an asynchronous response for an operation running on the target cluster member.
+func ForwardedOperationResponse(target string, op types.Operation) response.Response {
+	url := api.NewURL().Path("core", "1.0", "operations", op.ID).WithQuery("target", target)
+
+	return &operationResponse{url: url.String(), op: func() types.Operation { return op }}
+}
+
+// Render renders the operation response.
+func (r *operationResponse) Render(w http.ResponseWriter) error {
+	body := api.ResponseRaw{
+		Type:       api.AsyncResponse,
+		Status:     api.OperationCreated.String(),
+		StatusCode: int(api.OperationCreated),
+		Operation:  r.url,
+		Metadata:   r.op(),
+	}
+
+	w.Header().Set("Location", r.url)
+	w.WriteHeader(http.StatusAccepted)
+
+	return util.WriteJSON(w, body, nil)
+}
+
+// String returns the operation URL.
+func (r *operationResponse) String() string {
+	return r.url
+}
@@ -0,0 +1,259 @@
+package recover
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+	"gopkg.in/yaml.v3"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/extensions"
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+)
+
+const (
+	// backupMetadataFile is the name of the tarball entry holding the backup metadata.
+	backupMetadataFile = "backup.yaml"
+
+	// backupDumpFile is the name of the tarball entry holding the SQL dump of the database.
+	backupDumpFile = "database.sql"
+)
+
+// restoreExcludedTables are left untouched when restoring a database backup, as they describe the live state
+// of the cluster rather than its data, or grant access to it. Restoring a backup must not bring back revoked
+// client certificates or authorization bindings.
+var restoreExcludedTables = []string{"schemas", "core_cluster_members", "core_token_records", "core_leases", "core_client_tokens", "core_certificate_rotations", "core_certificate_rotation_members", "core_client_certificates", "core_auth_roles", "core_auth_bindings"}
+
+// CreateOnlineDatabaseBackup returns the metadata of a backup of the running database, taken within the given transaction.
+// The metadata records the schema version and API extensions of the local cluster member and each member in
+// core_cluster_members. The database itself is dumped by WriteDatabaseBackup within the same transaction.
+func CreateOnlineDatabaseBackup(ctx context.Context, tx *sql.Tx, location string, schemaInternal uint64, schemaExternal uint64, apiExtensions extensions.Extensions) (*internalTypes.DatabaseBackup, error) {
+	members, err := cluster.GetCoreClusterMembers(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get cluster members: %w", err)
+	}
+
+	backup := &internalTypes.DatabaseBackup{
+		CreatedAt:      time.Now().UTC(),
+		Location:       location,
+		SchemaInternal: schemaInternal,
+		SchemaExternal: schemaExternal,
+		APIExtensions:  apiExtensions,
+		ClusterMembers: make([]internalTypes.DatabaseBackupMember, 0, len(members)),
+	}
+
+	for _, member := range members {
+		backup.ClusterMembers = append(backup.ClusterMembers, internalTypes.DatabaseBackupMember{
+			Name:           member.Name,
+			Address:        member.Address,
+			SchemaInternal: member.SchemaInternal,
+			SchemaExternal: member.SchemaExternal,
+			APIExtensions:  member.APIExtensions,
+		})
+	}
+
+	return backup, nil
+}
+
+// countingWriter discards everything written to it, counting the number of bytes.
+type countingWriter int64
+
+// Write implements io.Writer for countingWriter.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	*c += countingWriter(len(p))
+
+	return len(p), nil
+}
+
+// WriteDatabaseBackup writes a gzip-compressed tarball containing the backup metadata and a dump of the database.
+// The dump is streamed from the given transaction without holding it in memory, so the database is dumped twice:
+// once to compute the size of the tarball entry, and once to write it. Secrets are left out of the dump.
+func WriteDatabaseBackup(ctx context.Context, tx *sql.Tx, w io.Writer, backup internalTypes.DatabaseBackup) error {
+	metadata, err := yaml.Marshal(backup)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal backup metadata: %w", err)
+	}
+
+	var dumpSize countingWriter
+	err = DumpDatabase(ctx, tx, &dumpSize, false)
+	if err != nil {
+		return fmt.Errorf("Failed to dump database: %w", err)
+	}
+
+	gzWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	entries := []struct {
+		name  string
+		size  int64
+		write func(w io.Writer) error
+	}{
+		{
+			name: backupMetadataFile,
+			size: int64(len(metadata)),
+			write: func(w io.Writer) error {
+				_, err := w.Write(metadata)
+				return err
+			},
+		},
+		{
+			name: backupDumpFile,
+			size: int64(dumpSize),
+			write: func(w io.Writer) error {
+				return DumpDatabase(ctx, tx, w, false)
+			},
+		},
+	}
+
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o600,
+			Size:     entry.size,
+			ModTime:  backup.CreatedAt,
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		err = entry.write(tarWriter)
+		if err != nil {
+			return fmt.Errorf("Failed to write %q to backup tarball: %w", entry.name, err)
+		}
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzWriter.Close()
+}
+
+// ReadDatabaseBackup reads the backup metadata and database dump from a tarball written by WriteDatabaseBackup.
+func ReadDatabaseBackup(r io.Reader) (*internalTypes.DatabaseBackup, string, error) {
+	gzReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to read backup tarball: %w", err)
+	}
+
+	tarReader := tar.NewReader(gzReader)
+
+	var backup *internalTypes.DatabaseBackup
+	var dump *string
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, "", fmt.Errorf("Failed to read backup tarball: %w", err)
+		}
+
+		var buf bytes.Buffer
+		_, err = io.Copy(&buf, tarReader)
+		if err != nil {
+			return nil, "", fmt.Errorf("Failed to read %q from backup tarball: %w", header.Name, err)
+		}
+
+		switch header.Name {
+		case backupMetadataFile:
+			backup = &internalTypes.DatabaseBackup{}
+			err = yaml.Unmarshal(buf.Bytes(), backup)
+			if err != nil {
+				return nil, "", fmt.Errorf("Failed to parse backup metadata: %w", err)
+			}
+
+		case backupDumpFile:
+			content := buf.String()
+			dump = &content
+		default:
+			return nil, "", fmt.Errorf("Unexpected entry %q in backup tarball", header.Name)
+		}
+	}
+
+	if backup == nil || dump == nil {
+		return nil, "", fmt.Errorf("Backup tarball must contain %q and %q", backupMetadataFile, backupDumpFile)
+	}
+
+	return backup, *dump, nil
+}
+
+// ValidateDatabaseBackup checks that the backup was taken from a database with the given schema version.
+func ValidateDatabaseBackup(backup internalTypes.DatabaseBackup, schemaInternal uint64, schemaExternal uint64) error {
+	if backup.SchemaInternal != schemaInternal || backup.SchemaExternal != schemaExternal {
+		return api.StatusErrorf(http.StatusBadRequest, "Backup schema version (internal %d, external %d) does not match the running schema version (internal %d, external %d)", backup.SchemaInternal, backup.SchemaExternal, schemaInternal, schemaExternal)
+	}
+
+	return nil
+}
+
+// RestoreDatabaseDump replaces the rows of every table in the database with those of the given dump,
+// except for the tables that describe the live state of the cluster.
+// The dump must have been validated against the running schema version.
+func RestoreDatabaseDump(ctx context.Context, tx *sql.Tx, dump string) error {
+	tables, err := query.SelectStrings(ctx, tx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY rowid")
+	if err != nil {
+		return fmt.Errorf("Failed to get database tables: %w", err)
+	}
+
+	inserts := map[string][]string{}
+	for _, line := range strings.Split(dump, "\n") {
+		// Each row is dumped as a single line INSERT statement.
+		stmt, ok := strings.CutPrefix(line, "INSERT INTO ")
+		if !ok {
+			continue
+		}
+
+		table, _, ok := strings.Cut(stmt, " VALUES(")
+		if !ok {
+			return fmt.Errorf("Invalid statement in database backup: %q", line)
+		}
+
+		if table == "sqlite_sequence" || slices.Contains(restoreExcludedTables, table) {
+			continue
+		}
+
+		if !slices.Contains(tables, table) {
+			return api.StatusErrorf(http.StatusBadRequest, "Database backup contains unknown table %q", table)
+		}
+
+		inserts[table] = append(inserts[table], line)
+	}
+
+	// Clear tables in reverse order of creation so that rows referencing earlier tables are removed first.
+	for i := len(tables) - 1; i >= 0; i-- {
+		if slices.Contains(restoreExcludedTables, tables[i]) {
+			continue
+		}
+
+		_, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", tables[i]))
+		if err != nil {
+			return fmt.Errorf("Failed to clear table %q: %w", tables[i], err)
+		}
+	}
+
+	for _, table := range tables {
+		for _, stmt := range inserts[table] {
+			_, err := tx.ExecContext(ctx, stmt)
+			if err != nil {
+				return fmt.Errorf("Failed to restore row of table %q: %w", table, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package recover
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/db/schema"
+	"github.com/canonical/lxd/shared/api"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/db/update"
+	"github.com/canonical/microcluster/v2/internal/extensions"
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+)
+
+type backupSuite struct {
+	suite.Suite
+}
+
+func TestBackupSuite(t *testing.T) {
+	suite.Run(t, new(backupSuite))
+}
+
+// newTestDB returns a sqlite DB set up with the default microcluster schema and an external table.
+func newTestDB() (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each connection to an in-memory database has its own data.
+	db.SetMaxOpenConns(1)
+
+	schemaManager := update.NewSchema()
+	schemaManager.AppendSchema([]schema.Update{func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "CREATE TABLE services (id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL, name TEXT NOT NULL, config TEXT NOT NULL, UNIQUE(name));")
+		return err
+	}}, nil)
+
+	_, err = schemaManager.Schema().Ensure(db)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cluster.PrepareStmts(db, cluster.GetCallerProject(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (s *backupSuite) Test_backupRestore() {
+	db, err := newTestDB()
+	s.Require().NoError(err)
+	defer db.Close()
+
+	ctx := context.Background()
+	exec := func(stmt string) {
+		s.Require().NoError(query.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, stmt)
+			return err
+		}))
+	}
+
+	exec("INSERT INTO core_cluster_members (name, address, certificate, schema_internal, schema_external, api_extensions, heartbeat, role) VALUES ('n0', '10.0.0.1:8443', 'cert', 7, 1, '[\"ext\"]', '2024-01-01 00:00:00+00:00', 'voter')")
+	exec("INSERT INTO services (name, config) VALUES ('ovn', 'a=1\nb=''2''')")
+
+	exec("INSERT INTO core_token_records (name, secret) VALUES ('n2', 'token-secret')")
+	exec("INSERT INTO core_client_tokens (name, secret) VALUES ('tool', 'client-token-secret')")
+	exec("INSERT INTO core_certificate_rotations (fingerprint, previous_fingerprint, certificate, key, phase, grace_period, started_at) VALUES ('next', 'old', 'cert', 'rotation-key', 'prepare', 0, '2024-01-01 00:00:00+00:00')")
+	exec("INSERT INTO core_client_certificates (name, fingerprint, certificate) VALUES ('admin', 'abcd', 'cert')")
+
+	buf := bytes.Buffer{}
+	s.Require().NoError(query.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		backup, err := CreateOnlineDatabaseBackup(ctx, tx, "n0", 7, 1, extensions.Extensions{"ext"})
+		s.Require().NoError(err)
+		s.Len(backup.ClusterMembers, 1)
+		s.Equal("n0", backup.ClusterMembers[0].Name)
+		s.Equal(uint64(7), backup.ClusterMembers[0].SchemaInternal)
+
+		return WriteDatabaseBackup(ctx, tx, &buf, *backup)
+	}))
+
+	// Modify the data after the backup, including tables that are not restored.
+	exec("UPDATE services SET config = 'changed'")
+	exec("INSERT INTO services (name, config) VALUES ('ceph', '')")
+	exec("DELETE FROM core_token_records")
+	exec("DELETE FROM core_client_tokens")
+	exec("DELETE FROM core_certificate_rotations")
+	exec("DELETE FROM core_client_certificates")
+	exec("INSERT INTO core_token_records (name, secret) VALUES ('n1', 'secret')")
+	exec("INSERT INTO core_client_tokens (name, secret) VALUES ('admin-tool', 'secret')")
+	exec("INSERT INTO core_certificate_rotations (fingerprint, previous_fingerprint, certificate, key, phase, grace_period, started_at) VALUES ('new', 'old', 'cert', '', 'switch', 0, '2024-01-01 00:00:00+00:00')")
+	exec("INSERT INTO core_certificate_rotation_members (rotation_id, member, phase) VALUES (1, 'n0', 'switch')")
+	exec("INSERT INTO core_client_certificates (name, fingerprint, certificate) VALUES ('other', 'efgh', 'cert')")
+
+	readBackup, dump, err := ReadDatabaseBackup(&buf)
+	s.Require().NoError(err)
+
+	// Secrets are left out of the backup.
+	for _, secret := range []string{"token-secret", "client-token-secret", "rotation-key"} {
+		s.NotContains(dump, secret)
+	}
+
+	s.Contains(dump, "INSERT INTO core_certificate_rotations VALUES(1,'next','old','cert','','prepare',")
+	s.Equal("n0", readBackup.Location)
+	s.Equal(extensions.Extensions{"ext"}, readBackup.APIExtensions)
+	s.WithinDuration(time.Now(), readBackup.CreatedAt, time.Minute)
+
+	err = ValidateDatabaseBackup(*readBackup, 7, 2)
+	s.True(api.StatusErrorCheck(err, http.StatusBadRequest))
+	s.NoError(ValidateDatabaseBackup(*readBackup, 7, 1))
+
+	s.Require().NoError(query.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		return RestoreDatabaseDump(ctx, tx, dump)
+	}))
+
+	s.Require().NoError(query.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		configs, err := query.SelectStrings(ctx, tx, "SELECT name || '=' || config FROM services ORDER BY name")
+		s.Require().NoError(err)
+		s.Equal([]string{"ovn=a=1\nb='2'"}, configs)
+
+		tokens, err := query.SelectStrings(ctx, tx, "SELECT name FROM core_token_records")
+		s.Require().NoError(err)
+		s.Equal([]string{"n1"}, tokens)
+
+		clientTokens, err := query.SelectStrings(ctx, tx, "SELECT name FROM core_client_tokens")
+		s.Require().NoError(err)
+		s.Equal([]string{"admin-tool"}, clientTokens)
+
+		rotations, err := query.SelectStrings(ctx, tx, "SELECT fingerprint || '=' || phase FROM core_certificate_rotations")
+		s.Require().NoError(err)
+		s.Equal([]string{"new=switch"}, rotations)
+
+		rotationMembers, err := query.SelectStrings(ctx, tx, "SELECT member || '=' || phase FROM core_certificate_rotation_members")
+		s.Require().NoError(err)
+		s.Equal([]string{"n0=switch"}, rotationMembers)
+
+		clientCerts, err := query.SelectStrings(ctx, tx, "SELECT name FROM core_client_certificates")
+		s.Require().NoError(err)
+		s.Equal([]string{"other"}, clientCerts)
+
+		return nil
+	}))
+
+	// Backups referencing tables missing from the running schema are rejected.
+	err = query.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		return RestoreDatabaseDump(ctx, tx, "INSERT INTO missing VALUES(1);\n")
+	})
+	s.True(api.StatusErrorCheck(err, http.StatusBadRequest))
+}
+
+func (s *backupSuite) Test_readInvalidBackup() {
+	db, err := newTestDB()
+	s.Require().NoError(err)
+	defer db.Close()
+
+	buf := bytes.Buffer{}
+	s.Require().NoError(query.Transaction(context.Background(), db, func(ctx context.Context, tx *sql.Tx) error {
+		return WriteDatabaseBackup(ctx, tx, &buf, internalTypes.DatabaseBackup{})
+	}))
+
+	_, dump, err := ReadDatabaseBackup(&buf)
+	s.NoError(err)
+	s.Contains(dump, "COMMIT;")
+
+	_, _, err = ReadDatabaseBackup(bytes.NewBufferString("not a tarball"))
+	s.Error(err)
+}
+
+func (s *backupSuite) Test_dumpDatabase() {
+	db, err := newTestDB()
+	s.Require().NoError(err)
+	defer db.Close()
+
+	s.Require().NoError(query.Transaction(context.Background(), db, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO services (name, config) VALUES ('ovn', 'a=1\nb=''2''\r')")
+		s.Require().NoError(err)
+
+		// Without secrets, the dump matches that of query.Dump.
+		for _, schemaOnly := range []bool{false, true} {
+			expected, err := query.Dump(ctx, tx, schemaOnly)
+			s.Require().NoError(err)
+
+			var dump strings.Builder
+			s.Require().NoError(DumpDatabase(ctx, tx, &dump, schemaOnly))
+			s.Equal(expected, dump.String())
+		}
+
+		return nil
+	}))
+}
@@ -0,0 +1,187 @@
+package recover
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dumpExcludedTables hold secrets, so their rows are left out of database dumps. Their schema is still dumped.
+var dumpExcludedTables = []string{"core_token_records", "core_client_tokens"}
+
+// dumpRedactedColumns hold secrets, so their values are replaced with an empty string in database dumps.
+var dumpRedactedColumns = map[string][]string{
+	"core_certificate_rotations": {"key"},
+}
+
+// DumpDatabase writes a SQL text dump of the database to the given writer, similar to sqlite3's dump feature.
+// The dump has the same format as that of query.Dump, except that each row is written as soon as it is read, and that
+// the rows of dumpExcludedTables and the values of dumpRedactedColumns are left out.
+func DumpDatabase(ctx context.Context, tx *sql.Tx, w io.Writer, schemaOnly bool) error {
+	rows, err := tx.QueryContext(ctx, "SELECT name, type, sql FROM sqlite_master WHERE name NOT LIKE 'sqlite_%' ORDER BY rowid")
+	if err != nil {
+		return fmt.Errorf("Could not get table names and their schema: %w", err)
+	}
+
+	type entity struct {
+		name   string
+		kind   string
+		schema string
+	}
+
+	var entities []entity
+	for rows.Next() {
+		var e entity
+		err := rows.Scan(&e.name, &e.kind, &e.schema)
+		if err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("Could not scan table name and schema: %w", err)
+		}
+
+		// This is based on logic from dump_callback in sqlite source for sqlite3_db_dump function.
+		if strings.HasPrefix(e.schema, `CREATE TABLE "`) {
+			e.schema = strings.Replace(e.schema, "CREATE TABLE", "CREATE TABLE IF NOT EXISTS", 1)
+		}
+
+		entities = append(entities, e)
+	}
+
+	err = rows.Err()
+	_ = rows.Close()
+	if err != nil {
+		return fmt.Errorf("Could not get table names and their schema: %w", err)
+	}
+
+	_, err = io.WriteString(w, "PRAGMA foreign_keys=OFF;\nBEGIN TRANSACTION;\n")
+	if err != nil {
+		return err
+	}
+
+	// For each table, write the schema and optionally write the data.
+	for _, e := range entities {
+		_, err = io.WriteString(w, e.schema+";\n")
+		if err != nil {
+			return err
+		}
+
+		if schemaOnly || e.kind != "table" || slices.Contains(dumpExcludedTables, e.name) {
+			continue
+		}
+
+		err = dumpTableData(ctx, tx, w, e.name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !schemaOnly {
+		_, err = io.WriteString(w, "DELETE FROM sqlite_sequence;\n")
+		if err != nil {
+			return err
+		}
+
+		err = dumpTableData(ctx, tx, w, "sqlite_sequence")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, "COMMIT;\n")
+
+	return err
+}
+
+// dumpTableData writes an INSERT statement to the given writer for each row of the table.
+func dumpTableData(ctx context.Context, tx *sql.Tx, w io.Writer, table string) error {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s ORDER BY rowid", table))
+	if err != nil {
+		return fmt.Errorf("Failed to fetch rows for table %q: %w", table, err)
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("Failed to get columns for table %q: %w", table, err)
+	}
+
+	raw := make([]any, len(columns))
+	row := make([]any, len(columns))
+	for i := range raw {
+		row[i] = &raw[i]
+	}
+
+	values := make([]string, len(columns))
+	for i := 0; rows.Next(); i++ {
+		err := rows.Scan(row...)
+		if err != nil {
+			return fmt.Errorf("Failed to scan row %d in table %q: %w", i, table, err)
+		}
+
+		for j, v := range raw {
+			if slices.Contains(dumpRedactedColumns[table], columns[j]) {
+				values[j] = "''"
+				continue
+			}
+
+			values[j], err = dumpValue(v)
+			if err != nil {
+				return fmt.Errorf("Bad type in column %q of row %d in table %q: %w", columns[j], i, table, err)
+			}
+		}
+
+		_, err = fmt.Fprintf(w, "INSERT INTO %s VALUES(%s);\n", table, strings.Join(values, ","))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("Failed to fetch rows for table %q: %w", table, err)
+	}
+
+	return nil
+}
+
+// dumpValue returns the SQL literal for a value scanned from the database.
+func dumpValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "NULL", nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case string:
+		// This is based on logic from dump_callback in sqlite source for sqlite3_db_dump function.
+		value := fmt.Sprintf("'%s'", strings.ReplaceAll(v, "'", "''"))
+
+		if strings.Contains(value, "\r") {
+			value = "replace(" + strings.ReplaceAll(value, "\r", "\\r") + ",'\\r',char(13))"
+		}
+
+		if strings.Contains(value, "\n") {
+			value = "replace(" + strings.ReplaceAll(value, "\n", "\\n") + ",'\\n',char(10))"
+		}
+
+		return value, nil
+	case []byte:
+		return fmt.Sprintf("'%s'", string(v)), nil
+	case time.Time:
+		// Try and match the sqlite3 .dump output format.
+		format := "2006-01-02 15:04:05"
+		if v.Nanosecond() > 0 {
+			format = format + ".000000000"
+		}
+
+		return "'" + v.Format(format+"-07:00") + "'", nil
+	default:
+		return "", fmt.Errorf("Unsupported type %T", v)
+	}
+}
@@ -0,0 +1,252 @@
+package recover
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/go-dqlite"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/config"
+	"github.com/canonical/microcluster/v2/internal/sys"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// raftMetadataSize is the size of a raft metadata file: the disk format, version, term and vote, as little endian uint64s.
+const raftMetadataSize = 32
+
+// raftSegmentFormat is the disk format version at the start of each raft segment.
+const raftSegmentFormat = 1
+
+// raftSegmentRegex matches closed raft segment files, named after the first and last index of their entries.
+var raftSegmentRegex = regexp.MustCompile(`^([0-9]{16})-([0-9]{16})$`)
+
+// raftSnapshotRegex matches raft snapshot files, named after the term and index of the last entry they include.
+var raftSnapshotRegex = regexp.MustCompile(`^snapshot-([0-9]+)-([0-9]+)-([0-9]+)$`)
+
+// PlanRecovery reports what RecoverFromQuorumLoss would do with the given cluster configuration, without making any changes.
+// Invalid configurations are reported in the plan rather than returned as an error.
+func PlanRecovery(filesystem *sys.OS, members []cluster.DqliteMember) (*types.RecoveryPlan, error) {
+	oldMembers, err := GetDqliteClusterMembers(filesystem)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &types.RecoveryPlan{
+		Members:            make([]types.RecoveryPlanMember, 0, len(members)),
+		TrustStoreUpdates:  []types.RecoveryPlanAddressChange{},
+		TrustStoreRemovals: []string{},
+	}
+
+	err = ValidateMemberChanges(oldMembers, members)
+	if err != nil {
+		plan.ValidationError = err.Error()
+	}
+
+	plan.DaemonRunning, err = filesystem.IsControlSocketPresent()
+	if err != nil {
+		return nil, err
+	}
+
+	plan.RaftLog, err = readRaftLog(filesystem.DatabaseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var localInfo dqlite.NodeInfo
+	err = readYaml(path.Join(filesystem.DatabaseDir, "info.yaml"), &localInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	oldMembersByID := make(map[uint64]cluster.DqliteMember, len(oldMembers))
+	for _, oldMember := range oldMembers {
+		oldMembersByID[oldMember.DqliteID] = oldMember
+	}
+
+	newAddresses := make(map[string]string, len(members))
+	for _, member := range members {
+		newAddresses[member.Name] = member.Address
+
+		oldMember := oldMembersByID[member.DqliteID]
+		plan.Members = append(plan.Members, types.RecoveryPlanMember{
+			Name:       member.Name,
+			DqliteID:   member.DqliteID,
+			OldAddress: oldMember.Address,
+			NewAddress: member.Address,
+			OldRole:    oldMember.Role,
+			NewRole:    member.Role,
+			LosesVoter: oldMember.Role == "voter" && member.Role != "voter",
+		})
+
+		if member.DqliteID == localInfo.ID {
+			plan.LocalMember = member.Name
+		}
+	}
+
+	remotes, err := readTrustStore(filesystem.TrustDir)
+	if err != nil {
+		return nil, err
+	}
+
+	remotesByName := remotes.RemotesByName()
+	names := make([]string, 0, len(remotesByName))
+	for name := range remotesByName {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	for _, name := range names {
+		remote := remotesByName[name]
+		newAddress, ok := newAddresses[name]
+		if !ok {
+			plan.TrustStoreRemovals = append(plan.TrustStoreRemovals, name)
+		} else if newAddress != remote.Address.String() {
+			plan.TrustStoreUpdates = append(plan.TrustStoreUpdates, types.RecoveryPlanAddressChange{
+				Name:       name,
+				OldAddress: remote.Address.String(),
+				NewAddress: newAddress,
+			})
+		}
+	}
+
+	daemonConfig := config.NewDaemonConfig(path.Join(filesystem.StateDir, "daemon.yaml"))
+	err = daemonConfig.Load()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load daemon.yaml: %w", err)
+	}
+
+	newAddress, ok := newAddresses[plan.LocalMember]
+	if ok && newAddress != daemonConfig.GetAddress().String() {
+		plan.DaemonAddress = &types.RecoveryPlanAddressChange{
+			Name:       plan.LocalMember,
+			OldAddress: daemonConfig.GetAddress().String(),
+			NewAddress: newAddress,
+		}
+	}
+
+	return plan, nil
+}
+
+// readRaftLog summarises the raft log in the given dqlite directory.
+// The entries of open segments follow those of the closed segments, so they are counted on top of the last closed
+// segment. Without closed segments, the index of the first open entry is not known.
+func readRaftLog(dir string) (types.RecoveryRaftLog, error) {
+	raftLog := types.RecoveryRaftLog{}
+	var closedIndex, snapshotIndex, openEntries uint64
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return raftLog, err
+	}
+
+	var metadataVersion uint64
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		name := entry.Name()
+		switch {
+		case name == "metadata1" || name == "metadata2":
+			content, err := os.ReadFile(path.Join(dir, name))
+			if err != nil {
+				return raftLog, err
+			}
+
+			// The raft metadata file with the highest version is the current one.
+			if len(content) < raftMetadataSize {
+				continue
+			}
+
+			version := binary.LittleEndian.Uint64(content[8:16])
+			if version > metadataVersion {
+				metadataVersion = version
+				raftLog.Term = binary.LittleEndian.Uint64(content[16:24])
+				raftLog.VotedFor = binary.LittleEndian.Uint64(content[24:32])
+			}
+
+			continue
+		case raftSegmentRegex.MatchString(name):
+			lastIndex, err := strconv.ParseUint(raftSegmentRegex.FindStringSubmatch(name)[2], 10, 64)
+			if err != nil {
+				return raftLog, fmt.Errorf("Invalid raft file name %q: %w", name, err)
+			}
+
+			closedIndex = max(closedIndex, lastIndex)
+		case raftSnapshotRegex.MatchString(name):
+			lastIndex, err := strconv.ParseUint(raftSnapshotRegex.FindStringSubmatch(name)[2], 10, 64)
+			if err != nil {
+				return raftLog, fmt.Errorf("Invalid raft file name %q: %w", name, err)
+			}
+
+			snapshotIndex = max(snapshotIndex, lastIndex)
+		case strings.HasPrefix(name, "open-"):
+			content, err := os.ReadFile(path.Join(dir, name))
+			if err != nil {
+				return raftLog, err
+			}
+
+			raftLog.OpenSegments++
+			openEntries += countRaftSegmentEntries(content)
+		default:
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return raftLog, err
+		}
+
+		if info.ModTime().After(raftLog.LastModified) {
+			raftLog.LastModified = info.ModTime().UTC()
+		}
+	}
+
+	raftLog.LastIndex = max(snapshotIndex, closedIndex+openEntries)
+	raftLog.LastIndexUnknown = closedIndex == 0 && snapshotIndex > 0 && openEntries > 0
+
+	return raftLog, nil
+}
+
+// countRaftSegmentEntries returns the number of entries in the complete batches of the given raft segment.
+// Each batch starts with two checksums as little endian uint32s and the number of entries as a little endian uint64,
+// followed by a 16 byte header for each entry, and then the data of each entry padded to 8 bytes. The unused end of
+// a segment is zeroed, and a batch that was not fully written is ignored.
+func countRaftSegmentEntries(content []byte) uint64 {
+	if len(content) < 8 || binary.LittleEndian.Uint64(content[0:8]) != raftSegmentFormat {
+		return 0
+	}
+
+	var entries uint64
+	offset := uint64(8)
+	size := uint64(len(content))
+	for offset+16 <= size {
+		n := binary.LittleEndian.Uint64(content[offset+8 : offset+16])
+		if n == 0 || n > (size-offset-16)/16 {
+			break
+		}
+
+		headers := offset + 16
+		offset = headers + n*16
+		for i := uint64(0); i < n; i++ {
+			// The data length is the last field of the entry header, after the term, type and unused bytes.
+			length := uint64(binary.LittleEndian.Uint32(content[headers+i*16+12 : headers+i*16+16]))
+			offset += (length + 7) / 8 * 8
+		}
+
+		if offset > size {
+			break
+		}
+
+		entries += n
+	}
+
+	return entries
+}
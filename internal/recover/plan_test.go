@@ -0,0 +1,117 @@
+package recover
+
+import (
+	"encoding/binary"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type planSuite struct {
+	suite.Suite
+}
+
+func TestPlanSuite(t *testing.T) {
+	suite.Run(t, new(planSuite))
+}
+
+// raftMetadata encodes a raft metadata file with the given version, term and vote.
+func raftMetadata(version uint64, term uint64, votedFor uint64) []byte {
+	content := make([]byte, raftMetadataSize)
+	binary.LittleEndian.PutUint64(content[0:8], 1)
+	binary.LittleEndian.PutUint64(content[8:16], version)
+	binary.LittleEndian.PutUint64(content[16:24], term)
+	binary.LittleEndian.PutUint64(content[24:32], votedFor)
+
+	return content
+}
+
+// raftSegment encodes an open raft segment with a batch for each of the given lists of entry data lengths, followed by
+// the zeroed space that is left unused.
+func raftSegment(batches ...[]uint32) []byte {
+	content := binary.LittleEndian.AppendUint64(nil, raftSegmentFormat)
+	for _, lengths := range batches {
+		// The checksums aren't checked.
+		content = binary.LittleEndian.AppendUint64(content, 0)
+		content = binary.LittleEndian.AppendUint64(content, uint64(len(lengths)))
+		for _, length := range lengths {
+			content = binary.LittleEndian.AppendUint64(content, 1)
+			content = append(content, 1, 0, 0, 0)
+			content = binary.LittleEndian.AppendUint32(content, length)
+		}
+
+		for _, length := range lengths {
+			content = append(content, make([]byte, (length+7)/8*8)...)
+		}
+	}
+
+	return append(content, make([]byte, 64)...)
+}
+
+func (s *planSuite) Test_readRaftLog() {
+	dir := s.T().TempDir()
+
+	files := map[string][]byte{
+		"metadata1":                             raftMetadata(4, 3, 2),
+		"metadata2":                             raftMetadata(5, 4, 1),
+		"0000000000000001-0000000000000100":     nil,
+		"0000000000000101-0000000000000230":     nil,
+		"snapshot-3-200-1234567890":             nil,
+		"snapshot-3-200-1234567890.meta":        nil,
+		"open-1":                                raftSegment([]uint32{3, 8}, []uint32{0}),
+		"open-2":                                nil,
+		"info.yaml":                             nil,
+		"cluster.yaml":                          nil,
+		"0000000000000231-0000000000000231.bak": nil,
+	}
+
+	for name, content := range files {
+		s.Require().NoError(os.WriteFile(path.Join(dir, name), content, 0o600))
+	}
+
+	raftLog, err := readRaftLog(dir)
+	s.Require().NoError(err)
+	s.Equal(uint64(4), raftLog.Term)
+	s.Equal(uint64(1), raftLog.VotedFor)
+	s.Equal(uint64(233), raftLog.LastIndex)
+	s.False(raftLog.LastIndexUnknown)
+	s.Equal(2, raftLog.OpenSegments)
+	s.False(raftLog.LastModified.IsZero())
+
+	// Truncated metadata files are ignored.
+	s.Require().NoError(os.WriteFile(path.Join(dir, "metadata2"), []byte{1}, 0o600))
+
+	raftLog, err = readRaftLog(dir)
+	s.Require().NoError(err)
+	s.Equal(uint64(3), raftLog.Term)
+	s.Equal(uint64(2), raftLog.VotedFor)
+}
+
+func (s *planSuite) Test_readRaftLogOpenSegments() {
+	dir := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(path.Join(dir, "snapshot-3-200-1234567890"), nil, 0o600))
+	s.Require().NoError(os.WriteFile(path.Join(dir, "open-1"), nil, 0o600))
+
+	// Empty open segments don't change the last index.
+	raftLog, err := readRaftLog(dir)
+	s.Require().NoError(err)
+	s.Equal(uint64(200), raftLog.LastIndex)
+	s.False(raftLog.LastIndexUnknown)
+
+	// Entries of open segments can't be placed after a snapshot without closed segments.
+	s.Require().NoError(os.WriteFile(path.Join(dir, "open-1"), raftSegment([]uint32{16}), 0o600))
+
+	raftLog, err = readRaftLog(dir)
+	s.Require().NoError(err)
+	s.Equal(uint64(200), raftLog.LastIndex)
+	s.True(raftLog.LastIndexUnknown)
+
+	// Batches that were not fully written are ignored.
+	segment := raftSegment([]uint32{8}, []uint32{8, 1024})
+	s.Equal(uint64(3), countRaftSegmentEntries(segment))
+	s.Equal(uint64(1), countRaftSegmentEntries(segment[:len(segment)-1024]))
+	s.Equal(uint64(0), countRaftSegmentEntries(segment[:20]))
+	s.Equal(uint64(0), countRaftSegmentEntries(nil))
+}
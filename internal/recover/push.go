@@ -0,0 +1,223 @@
+package recover
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/canonical/go-dqlite"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"gopkg.in/yaml.v3"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
+	"github.com/canonical/microcluster/v2/internal/sys"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// PushRecoveryTarball sends the recovery tarball created by RecoverFromQuorumLoss to every other cluster member
+// listed in its recovery.yaml, at the address recorded there. Requests are authenticated with the local server
+// certificate, and each receiving member must report the dqlite ID recorded for it in recovery.yaml.
+// The receiving members must be running, and will load the tarball the next time they start.
+// A result is returned for each member, in the order of recovery.yaml.
+func PushRecoveryTarball(ctx context.Context, filesystem *sys.OS, tarballPath string) ([]types.RecoveryPushResult, error) {
+	tarball, err := os.Open(tarballPath)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := readRecoveryMembers(tarball)
+	_ = tarball.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var localInfo dqlite.NodeInfo
+	err = readYaml(path.Join(filesystem.DatabaseDir, "info.yaml"), &localInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	serverCert, err := filesystem.ServerCert()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterCert, err := filesystem.ClusterCert()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterKey, err := clusterCert.PublicKeyX509()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]types.RecoveryPushResult, 0, len(members))
+	for _, member := range members {
+		if member.DqliteID == localInfo.ID {
+			continue
+		}
+
+		results = append(results, types.RecoveryPushResult{
+			Name:     member.Name,
+			Address:  member.Address,
+			DqliteID: member.DqliteID,
+		})
+	}
+
+	wg := sync.WaitGroup{}
+	for i := range results {
+		wg.Add(1)
+		go func(result *types.RecoveryPushResult) {
+			defer wg.Done()
+
+			url := api.NewURL().Scheme("https").Host(result.Address)
+			c, err := internalClient.New(*url, serverCert, clusterKey, false)
+			if err == nil {
+				err = pushRecoveryTarball(ctx, c, tarballPath, result.Name, result.DqliteID)
+			}
+
+			if err != nil {
+				logger.Error("Failed to push recovery tarball", logger.Ctx{"name": result.Name, "address": result.Address, "err": err})
+				result.Error = err.Error()
+			}
+		}(&results[i])
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
+
+// pushRecoveryTarball sends the recovery tarball to a single cluster member and checks that it reports the expected identity.
+func pushRecoveryTarball(ctx context.Context, c *internalClient.Client, tarballPath string, name string, dqliteID uint64) error {
+	tarball, err := os.Open(tarballPath)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tarball.Close() }()
+
+	receipt, err := internalClient.PushRecoveryTarball(ctx, c, tarball)
+	if err != nil {
+		return err
+	}
+
+	if receipt.Name != name || receipt.DqliteID != dqliteID {
+		return fmt.Errorf("Recovery tarball was received by cluster member %q with dqlite ID %d instead of %q with dqlite ID %d", receipt.Name, receipt.DqliteID, name, dqliteID)
+	}
+
+	return nil
+}
+
+// StageRecoveryTarball writes a recovery tarball pushed by another cluster member to filesystem.StateDir, where it is
+// picked up by MaybeUnpackRecoveryTarball the next time the daemon starts. The tarball's recovery.yaml must contain
+// the local cluster member with the given name and the dqlite ID from the local info.yaml.
+// The local dqlite ID is returned.
+func StageRecoveryTarball(filesystem *sys.OS, name string, r io.Reader) (uint64, error) {
+	tarballPath := path.Join(filesystem.StateDir, "recovery_db.tar.gz")
+
+	tmpFile, err := os.CreateTemp(filesystem.StateDir, "recovery_db.*.tar.gz")
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	_, err = io.Copy(tmpFile, r)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to write recovery tarball: %w", err)
+	}
+
+	_, err = tmpFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+
+	members, err := readRecoveryMembers(tmpFile)
+	if err != nil {
+		return 0, api.StatusErrorf(http.StatusBadRequest, "Invalid recovery tarball: %w", err)
+	}
+
+	var localInfo dqlite.NodeInfo
+	err = readYaml(path.Join(filesystem.DatabaseDir, "info.yaml"), &localInfo)
+	if err != nil {
+		return 0, err
+	}
+
+	found := false
+	for _, member := range members {
+		if member.DqliteID == localInfo.ID {
+			if member.Name != name {
+				return 0, api.StatusErrorf(http.StatusBadRequest, "Recovery tarball assigns dqlite ID %d to %q instead of %q", localInfo.ID, member.Name, name)
+			}
+
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return 0, api.StatusErrorf(http.StatusBadRequest, "Missing local cluster member %q (dqlite ID %d) in incoming recovery.yaml", name, localInfo.ID)
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	err = os.Rename(tmpFile.Name(), tarballPath)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Warn("Staged recovery tarball; it will be loaded the next time the daemon starts", logger.Ctx{"tarball": tarballPath})
+
+	return localInfo.ID, nil
+}
+
+// readRecoveryMembers returns the cluster configuration stored as recovery.yaml in a recovery tarball.
+func readRecoveryMembers(r io.Reader) ([]cluster.DqliteMember, error) {
+	gzReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read recovery tarball: %w", err)
+	}
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("Recovery tarball does not contain %q", "recovery.yaml")
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed to read recovery tarball: %w", err)
+		}
+
+		if path.Clean(header.Name) != "recovery.yaml" {
+			continue
+		}
+
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read %q from recovery tarball: %w", header.Name, err)
+		}
+
+		var members []cluster.DqliteMember
+		err = yaml.Unmarshal(content, &members)
+		if err != nil {
+			return nil, fmt.Errorf("Unmarshal %q: %w", header.Name, err)
+		}
+
+		return members, nil
+	}
+}
@@ -0,0 +1,119 @@
+package recover
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/canonical/go-dqlite"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/sys"
+)
+
+type pushSuite struct {
+	suite.Suite
+}
+
+func TestPushSuite(t *testing.T) {
+	suite.Run(t, new(pushSuite))
+}
+
+// newTestOS returns a filesystem with a database dir containing an info.yaml for the given dqlite ID.
+func (s *pushSuite) newTestOS(dqliteID uint64) *sys.OS {
+	stateDir := s.T().TempDir()
+	filesystem := &sys.OS{StateDir: stateDir, DatabaseDir: path.Join(stateDir, "database")}
+
+	s.Require().NoError(os.Mkdir(filesystem.DatabaseDir, 0o700))
+	s.Require().NoError(writeYaml(path.Join(filesystem.DatabaseDir, "info.yaml"), dqlite.NodeInfo{ID: dqliteID, Address: "10.0.0.1:8443"}))
+
+	return filesystem
+}
+
+func (s *pushSuite) Test_stageRecoveryTarball() {
+	members := []cluster.DqliteMember{
+		{DqliteID: 1, Address: "10.0.0.1:8443", Role: "voter", Name: "n1"},
+		{DqliteID: 2, Address: "10.0.0.2:8443", Role: "spare", Name: "n2"},
+	}
+
+	sender := s.newTestOS(1)
+	tarballPath, err := createRecoveryTarball(sender, members)
+	s.Require().NoError(err)
+
+	tarball, err := os.ReadFile(tarballPath)
+	s.Require().NoError(err)
+
+	readMembers, err := readRecoveryMembers(bytes.NewReader(tarball))
+	s.Require().NoError(err)
+	s.Equal(members, readMembers)
+
+	cases := []struct {
+		name       string
+		memberName string
+		dqliteID   uint64
+		tarball    []byte
+		statusCode int
+	}{
+		{
+			name:       "Member is in recovery.yaml",
+			memberName: "n2",
+			dqliteID:   2,
+			tarball:    tarball,
+		},
+		{
+			name:       "Member is missing from recovery.yaml",
+			memberName: "n3",
+			dqliteID:   3,
+			tarball:    tarball,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Member name does not match its dqlite ID",
+			memberName: "n3",
+			dqliteID:   2,
+			tarball:    tarball,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid tarball",
+			memberName: "n2",
+			dqliteID:   2,
+			tarball:    []byte("not a tarball"),
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%s (case %d)", c.name, i)
+
+		receiver := s.newTestOS(c.dqliteID)
+		dqliteID, err := StageRecoveryTarball(receiver, c.memberName, bytes.NewReader(c.tarball))
+
+		stagedPath := path.Join(receiver.StateDir, "recovery_db.tar.gz")
+		if c.statusCode != 0 {
+			s.True(api.StatusErrorCheck(err, c.statusCode))
+			s.NoFileExists(stagedPath)
+		} else {
+			s.Require().NoError(err)
+			s.Equal(c.dqliteID, dqliteID)
+
+			staged, err := os.ReadFile(stagedPath)
+			s.Require().NoError(err)
+			s.Equal(tarball, staged)
+		}
+
+		// No temporary files are left behind next to the database dir and staged tarball.
+		expectedEntries := 1
+		if c.statusCode == 0 {
+			expectedEntries++
+		}
+
+		entries, err := os.ReadDir(receiver.StateDir)
+		s.Require().NoError(err)
+		s.Len(entries, expectedEntries)
+	}
+}
@@ -5,16 +5,26 @@ import (
 	"net/http"
 
 	"github.com/canonical/lxd/lxd/request"
+
+	"github.com/canonical/microcluster/v2/rest/types"
 )
 
 // TrustedRequest holds data pertaining to what level of trust we have for the request.
 type TrustedRequest struct {
 	Trusted bool
+
+	// Identity is the client that sent the request, if it could be identified.
+	Identity *types.Identity
 }
 
 // SetRequestAuthentication sets the trusted status for the request. A trusted request will be treated as having come from a trusted system.
 func SetRequestAuthentication(r *http.Request, trusted bool) *http.Request {
-	r = r.WithContext(context.WithValue(r.Context(), any(request.CtxAccess), TrustedRequest{Trusted: trusted}))
+	return SetRequestIdentity(r, trusted, nil)
+}
+
+// SetRequestIdentity sets the trusted status for the request, along with the identity of the client that sent it.
+func SetRequestIdentity(r *http.Request, trusted bool, identity *types.Identity) *http.Request {
+	r = r.WithContext(context.WithValue(r.Context(), any(request.CtxAccess), TrustedRequest{Trusted: trusted, Identity: identity}))
 
 	return r
 }
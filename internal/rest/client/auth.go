@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// GetAuthPolicy returns the authorization policy, along with the ETag to use when replacing it.
+func (c *Client) GetAuthPolicy(ctx context.Context) (*types.AuthPolicy, string, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	policy := types.AuthPolicy{}
+	etag, err := c.QueryStructETag(queryCtx, "GET", internalTypes.PublicEndpoint, api.NewURL().Path("auth", "policy"), nil, &policy, "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &policy, etag, nil
+}
+
+// UpdateAuthPolicy replaces the authorization policy. If etag is set, the update fails if the policy has changed since it was read.
+func (c *Client) UpdateAuthPolicy(ctx context.Context, policy types.AuthPolicy, etag string) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := c.QueryStructETag(queryCtx, "PUT", internalTypes.PublicEndpoint, api.NewURL().Path("auth", "policy"), policy, nil, etag)
+
+	return err
+}
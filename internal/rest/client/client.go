@@ -33,7 +33,8 @@ type Client struct {
 }
 
 // New returns a new client configured with the given url and certificates.
-func New(url api.URL, clientCert *shared.CertInfo, remoteCert *x509.Certificate, forwarding bool) (*Client, error) {
+// The server may also present any of the alternateRemoteCerts, such as the next or previous cluster certificate while it is rotated.
+func New(url api.URL, clientCert *shared.CertInfo, remoteCert *x509.Certificate, forwarding bool, alternateRemoteCerts ...*x509.Certificate) (*Client, error) {
 	var err error
 	var httpClient *http.Client
 
@@ -47,7 +48,7 @@ func New(url api.URL, clientCert *shared.CertInfo, remoteCert *x509.Certificate,
 			proxy = forwardingProxy
 		}
 
-		httpClient, err = tlsHTTPClient(clientCert, remoteCert, proxy)
+		httpClient, err = tlsHTTPClient(clientCert, remoteCert, alternateRemoteCerts, proxy)
 	}
 
 	if err != nil {
@@ -92,11 +93,11 @@ func unixHTTPClient(path string) (*http.Client, error) {
 	return client, nil
 }
 
-func tlsHTTPClient(clientCert *shared.CertInfo, remoteCert *x509.Certificate, proxy func(req *http.Request) (*url.URL, error)) (*http.Client, error) {
+func tlsHTTPClient(clientCert *shared.CertInfo, remoteCert *x509.Certificate, alternateRemoteCerts []*x509.Certificate, proxy func(req *http.Request) (*url.URL, error)) (*http.Client, error) {
 	var tlsConfig *tls.Config
 	if remoteCert != nil {
 		var err error
-		tlsConfig, err = TLSClientConfig(clientCert, remoteCert)
+		tlsConfig, err = TLSClientConfig(clientCert, remoteCert, alternateRemoteCerts...)
 		if err != nil {
 			return nil, fmt.Errorf("Failed to parse TLS config: %w", err)
 		}
@@ -190,7 +191,8 @@ func IsForwardedRequest(r *http.Request) bool {
 	return r.Header.Get("User-Agent") == clusterRequest.UserAgentNotifier
 }
 
-func (c *Client) rawQuery(ctx context.Context, method string, url *api.URL, data any) (*api.Response, error) {
+// rawQuery sends the request and returns the response along with its ETag. If etag is set, it is sent in the If-Match header.
+func (c *Client) rawQuery(ctx context.Context, method string, url *api.URL, data any, etag string) (*api.Response, string, error) {
 	var req *http.Request
 	var err error
 
@@ -209,7 +211,7 @@ func (c *Client) rawQuery(ctx context.Context, method string, url *api.URL, data
 			// Some data to be sent along with the request
 			req, err = http.NewRequestWithContext(ctx, method, url.String(), data)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 
 			// Set the encoding accordingly
@@ -219,14 +221,14 @@ func (c *Client) rawQuery(ctx context.Context, method string, url *api.URL, data
 			buf := bytes.Buffer{}
 			err := json.NewEncoder(&buf).Encode(data)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 
 			// Some data to be sent along with the request
 			// Use a reader since the request body needs to be seekable
 			req, err = http.NewRequestWithContext(ctx, method, url.String(), bytes.NewReader(buf.Bytes()))
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 
 			// Set the encoding accordingly
@@ -239,24 +241,36 @@ func (c *Client) rawQuery(ctx context.Context, method string, url *api.URL, data
 		// No data to be sent along with the request
 		req, err = http.NewRequestWithContext(ctx, method, url.String(), nil)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 	}
 
-	return c.MakeRequest(req)
+	// Only apply the change if the resource is unchanged since it was last read.
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	return c.makeRequest(req)
 }
 
 // MakeRequest performs a request and parses the response into an api.Response.
 func (c *Client) MakeRequest(r *http.Request) (*api.Response, error) {
+	resp, _, err := c.makeRequest(r)
+
+	return resp, err
+}
+
+// makeRequest sends the request and returns the parsed response along with its ETag.
+func (c *Client) makeRequest(r *http.Request) (*api.Response, string, error) {
 	// Send the request
 	resp, err := c.Do(r)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	parsedResponse, err := parseResponse(resp)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	defer resp.Body.Close()
@@ -265,7 +279,7 @@ func (c *Client) MakeRequest(r *http.Request) (*api.Response, error) {
 		logger.Error("Failed to read response body", logger.Ctx{"error": err})
 	}
 
-	return parsedResponse, nil
+	return parsedResponse, resp.Header.Get("ETag"), nil
 }
 
 // QueryStruct sends a request of the specified method to the provided endpoint (optional) on the API matching the endpointType.
@@ -273,7 +287,37 @@ func (c *Client) MakeRequest(r *http.Request) (*api.Response, error) {
 //
 // The final URL is that provided as the endpoint combined with the applicable prefix for the endpointType and the scheme and host from the client.
 func (c *Client) QueryStruct(ctx context.Context, method string, endpointType types.EndpointPrefix, endpoint *api.URL, data any, target any) error {
-	// Merge the provided URL with the one we have for the client.
+	_, err := c.QueryStructETag(ctx, method, endpointType, endpoint, data, target, "")
+
+	return err
+}
+
+// QueryStructETag sends a request like QueryStruct, but also sends the given ETag in the If-Match header if it is set.
+// Returns the ETag of the response.
+func (c *Client) QueryStructETag(ctx context.Context, method string, endpointType types.EndpointPrefix, endpoint *api.URL, data any, target any, etag string) (string, error) {
+	localURL := c.endpointURL(endpointType, endpoint)
+
+	// Send the actual query through.
+	resp, respETag, err := c.rawQuery(ctx, method, localURL, data, etag)
+	if err != nil {
+		return "", err
+	}
+
+	// Unpack into the target struct.
+	err = resp.MetadataAsStruct(&target)
+	if err != nil {
+		return "", err
+	}
+
+	// Log the data.
+	logger.Debug("Got response struct from microcluster daemon", logger.Ctx{"endpoint": localURL.String(), "method": method})
+	// TODO: Log.pretty.
+	return respETag, nil
+}
+
+// endpointURL merges the provided endpoint with the scheme, host, and query of the client's URL,
+// prefixing the path with that of the endpointType.
+func (c *Client) endpointURL(endpointType types.EndpointPrefix, endpoint *api.URL) *api.URL {
 	localURL := api.NewURL()
 	if endpoint != nil {
 		// Get a new local struct to avoid modifying the provided one.
@@ -294,22 +338,7 @@ func (c *Client) QueryStruct(ctx context.Context, method string, endpointType ty
 
 	localURL.URL.RawQuery = clientQuery.Encode()
 
-	// Send the actual query through.
-	resp, err := c.rawQuery(ctx, method, localURL, data)
-	if err != nil {
-		return err
-	}
-
-	// Unpack into the target struct.
-	err = resp.MetadataAsStruct(&target)
-	if err != nil {
-		return err
-	}
-
-	// Log the data.
-	logger.Debug("Got response struct from microcluster daemon", logger.Ctx{"endpoint": localURL.String(), "method": method})
-	// TODO: Log.pretty.
-	return nil
+	return localURL
 }
 
 // URL returns the address used for the client.
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// GetClientCertificates returns the trusted client certificates.
+func (c *Client) GetClientCertificates(ctx context.Context) ([]types.ClientCertificate, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	certs := []types.ClientCertificate{}
+	err := c.QueryStruct(queryCtx, "GET", internalTypes.PublicEndpoint, api.NewURL().Path("certificates"), nil, &certs)
+
+	return certs, err
+}
+
+// AddClientCertificate trusts the given client certificate under the given name.
+func (c *Client) AddClientCertificate(ctx context.Context, name string, cert *x509.Certificate) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req := types.ClientCertificatesPost{
+		Name:        name,
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})),
+	}
+
+	return c.QueryStruct(queryCtx, "POST", internalTypes.PublicEndpoint, api.NewURL().Path("certificates"), req, nil)
+}
+
+// RequestClientCertificateToken returns a token that a client can redeem to have its certificate trusted under the
+// given name. The token does not expire if expireAfter is zero.
+func (c *Client) RequestClientCertificateToken(ctx context.Context, name string, expireAfter time.Duration) (string, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req := types.ClientCertificatesPost{Name: name, Token: true, ExpireAfter: expireAfter}
+
+	var token string
+	err := c.QueryStruct(queryCtx, "POST", internalTypes.PublicEndpoint, api.NewURL().Path("certificates"), req, &token)
+
+	return token, err
+}
+
+// RedeemClientCertificateToken redeems the given token to have the certificate the client was created with trusted.
+func (c *Client) RedeemClientCertificateToken(ctx context.Context, token string) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req := types.ClientCertificatesPost{TrustToken: token}
+
+	return c.QueryStruct(queryCtx, "POST", internalTypes.PublicEndpoint, api.NewURL().Path("certificates"), req, nil)
+}
+
+// DeleteClientCertificate revokes the client certificate, or the pending token, with the given name.
+func (c *Client) DeleteClientCertificate(ctx context.Context, name string) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "DELETE", internalTypes.PublicEndpoint, api.NewURL().Path("certificates", name), nil, nil)
+}
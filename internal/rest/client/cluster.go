@@ -48,17 +48,97 @@ func (c *Client) GetClusterMembers(ctx context.Context) ([]types.ClusterMember,
 	return clusterMembers, err
 }
 
-// DeleteClusterMember deletes the cluster member with the given name.
-func (c *Client) DeleteClusterMember(ctx context.Context, name string, force bool) error {
+// UpdateClusterMember sets the labels and failure domain of the cluster member with the given name.
+// A new failure domain applies the next time the cluster member's database starts.
+func (c *Client) UpdateClusterMember(ctx context.Context, name string, args types.ClusterMemberPut) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "PUT", internalTypes.PublicEndpoint, api.NewURL().Path("cluster", name), args, nil)
+}
+
+// SetClusterMemberRole assigns the given dqlite role to the cluster member with the given name.
+func (c *Client) SetClusterMemberRole(ctx context.Context, name string, role string) error {
 	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	return c.QueryStruct(queryCtx, "PATCH", internalTypes.PublicEndpoint, api.NewURL().Path("cluster", name), types.ClusterMemberPatch{Role: role}, nil)
+}
+
+// EvacuateClusterMember puts the cluster member with the given name into maintenance, and waits for the evacuation operation to finish.
+func (c *Client) EvacuateClusterMember(ctx context.Context, name string) error {
+	return c.QueryOperation(ctx, "POST", internalTypes.PublicEndpoint, api.NewURL().Path("cluster", name, "state"), types.ClusterMemberStatePost{Action: types.ClusterMemberEvacuate})
+}
+
+// RestoreClusterMember takes the cluster member with the given name out of maintenance, and waits for the restore operation to finish.
+func (c *Client) RestoreClusterMember(ctx context.Context, name string) error {
+	return c.QueryOperation(ctx, "POST", internalTypes.PublicEndpoint, api.NewURL().Path("cluster", name, "state"), types.ClusterMemberStatePost{Action: types.ClusterMemberRestore})
+}
+
+// DeleteClusterMember deletes the cluster member with the given name, and waits for the removal operation to finish.
+func (c *Client) DeleteClusterMember(ctx context.Context, name string, force bool) error {
 	endpoint := api.NewURL().Path("cluster", name)
 	if force {
 		endpoint = endpoint.WithQuery("force", "1")
 	}
 
-	return c.QueryStruct(queryCtx, "DELETE", internalTypes.PublicEndpoint, endpoint, nil, nil)
+	return c.QueryOperation(ctx, "DELETE", internalTypes.PublicEndpoint, endpoint, nil)
+}
+
+// GetCertificateRotation returns the status of the latest rotation of the cluster certificate.
+func (c *Client) GetCertificateRotation(ctx context.Context) (*types.CertificateRotation, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rotation := types.CertificateRotation{}
+	endpoint := api.NewURL().Path("cluster", "certificates", string(types.ClusterCertificateName), "rotation")
+	err := c.QueryStruct(queryCtx, "GET", internalTypes.PublicEndpoint, endpoint, nil, &rotation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rotation, nil
+}
+
+// RotateClusterCertificate starts a rotation of the cluster certificate to the given keypair, and returns its status.
+// The rotation completes in the background once every cluster member has switched to the new certificate and the
+// grace period has passed.
+func (c *Client) RotateClusterCertificate(ctx context.Context, args types.CertificateRotationPost) (*types.CertificateRotation, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rotation := types.CertificateRotation{}
+	endpoint := api.NewURL().Path("cluster", "certificates", string(types.ClusterCertificateName), "rotation")
+	err := c.QueryStruct(queryCtx, "POST", internalTypes.PublicEndpoint, endpoint, args, &rotation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rotation, nil
+}
+
+// AbortCertificateRotation aborts the latest rotation of the cluster certificate, and returns its status.
+// Cluster members go back to the previous certificate in the background.
+func (c *Client) AbortCertificateRotation(ctx context.Context) (*types.CertificateRotation, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rotation := types.CertificateRotation{}
+	endpoint := api.NewURL().Path("cluster", "certificates", string(types.ClusterCertificateName), "rotation")
+	err := c.QueryStruct(queryCtx, "DELETE", internalTypes.PublicEndpoint, endpoint, nil, &rotation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rotation, nil
+}
+
+// UpdateCertificateRotation applies a phase of a rotation of the cluster certificate to the cluster member.
+func UpdateCertificateRotation(ctx context.Context, c *Client, args internalTypes.CertificateRotationPut) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "PUT", internalTypes.InternalEndpoint, api.NewURL().Path("certificates", "rotation"), args, nil)
 }
 
 // UpdateCertificate sets a new keypair and CA.
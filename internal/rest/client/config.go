@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// GetClusterConfig returns the cluster config keys that are set, along with the ETag to use when updating them.
+// Keys scoped to cluster members are those of the cluster member set with UseTarget, or of the one the client is connected to.
+func (c *Client) GetClusterConfig(ctx context.Context) (*types.ClusterConfig, string, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	config := types.ClusterConfig{}
+	etag, err := c.QueryStructETag(queryCtx, "GET", internalTypes.PublicEndpoint, api.NewURL().Path("config"), nil, &config, "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &config, etag, nil
+}
+
+// UpdateClusterConfig replaces the cluster config, unsetting any key not in the given config.
+// Keys scoped to cluster members are set for the cluster member set with UseTarget, or for the one the client is connected to.
+// If etag is set, the update fails if the config has changed since it was read.
+func (c *Client) UpdateClusterConfig(ctx context.Context, config types.ClusterConfig, etag string) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := c.QueryStructETag(queryCtx, "PUT", internalTypes.PublicEndpoint, api.NewURL().Path("config"), config, nil, etag)
+
+	return err
+}
+
+// PatchClusterConfig sets the given cluster config keys, unsetting those with an empty value. Other keys are left unchanged.
+// Keys scoped to cluster members are set for the cluster member set with UseTarget, or for the one the client is connected to.
+// If etag is set, the update fails if the config has changed since it was read.
+func (c *Client) PatchClusterConfig(ctx context.Context, config types.ClusterConfig, etag string) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := c.QueryStructETag(queryCtx, "PATCH", internalTypes.PublicEndpoint, api.NewURL().Path("config"), config, nil, etag)
+
+	return err
+}
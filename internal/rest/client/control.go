@@ -6,7 +6,7 @@ import (
 	"github.com/canonical/microcluster/v2/internal/rest/types"
 )
 
-// ControlDaemon posts control data to the daemon.
+// ControlDaemon posts control data to the daemon, and waits for the resulting operation to finish.
 func (c *Client) ControlDaemon(ctx context.Context, args types.Control) error {
-	return c.QueryStruct(ctx, "POST", types.ControlEndpoint, nil, args, nil)
+	return c.QueryOperation(ctx, "POST", types.ControlEndpoint, nil, args)
 }
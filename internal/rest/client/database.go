@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcluster/v2/internal/rest/types"
+)
+
+// BackupDatabase writes a gzip-compressed tarball containing a consistent snapshot of the database to the given writer.
+func BackupDatabase(ctx context.Context, c *Client, w io.Writer) error {
+	url := c.endpointURL(types.InternalEndpoint, api.NewURL().Path("database", "backup"))
+	req, err := http.NewRequestWithContext(ctx, "POST", url.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Type") != "application/gzip" {
+		_, err := parseResponse(resp)
+		if err != nil {
+			return err
+		}
+
+		return fmt.Errorf("Unexpected response to database backup request")
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	if err != nil {
+		return fmt.Errorf("Failed to read database backup: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreDatabase replaces the contents of the database with those of the given backup tarball.
+func RestoreDatabase(ctx context.Context, c *Client, backup io.Reader) error {
+	return c.QueryStruct(ctx, "POST", types.InternalEndpoint, api.NewURL().Path("database", "restore"), backup, nil)
+}
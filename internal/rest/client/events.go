@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/gorilla/websocket"
+
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// EventListener receives events from the event stream of a cluster member.
+type EventListener struct {
+	conn   *websocket.Conn
+	events chan types.Event
+
+	errLock sync.Mutex
+	err     error
+}
+
+// Events returns the channel on which events are received. The channel is closed when the listener disconnects.
+func (l *EventListener) Events() <-chan types.Event {
+	return l.events
+}
+
+// Err returns the error that caused the listener to disconnect, if any.
+func (l *EventListener) Err() error {
+	l.errLock.Lock()
+	defer l.errLock.Unlock()
+
+	return l.err
+}
+
+// Disconnect closes the connection to the event stream.
+func (l *EventListener) Disconnect() {
+	_ = l.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(5*time.Second))
+	_ = l.conn.Close()
+}
+
+// GetEvents subscribes to the event stream of the cluster member targeted by this client.
+// If no event types are given, all events are received. The listener disconnects when the context is cancelled.
+func (c *Client) GetEvents(ctx context.Context, eventTypes ...types.EventType) (*EventListener, error) {
+	transport, ok := c.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("Client transport does not support websockets")
+	}
+
+	dialer := websocket.Dialer{
+		NetDialContext:    transport.DialContext,
+		NetDialTLSContext: transport.DialTLSContext,
+		TLSClientConfig:   transport.TLSClientConfig,
+		Proxy:             transport.Proxy,
+		HandshakeTimeout:  30 * time.Second,
+	}
+
+	url := api.NewURL().Scheme("wss").Host(c.url.URL.Host)
+	if c.url.URL.Scheme == "http" {
+		url = url.Scheme("ws")
+	}
+
+	url.URL.Path = filepath.Join("/", string(internalTypes.PublicEndpoint), "events")
+	if len(eventTypes) > 0 {
+		typeNames := make([]string, 0, len(eventTypes))
+		for _, eventType := range eventTypes {
+			typeNames = append(typeNames, string(eventType))
+		}
+
+		url = url.WithQuery("type", strings.Join(typeNames, ","))
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, url.String(), nil)
+	if err != nil {
+		if resp != nil {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("Failed to connect to event stream: %w (%s)", err, resp.Status)
+		}
+
+		return nil, fmt.Errorf("Failed to connect to event stream: %w", err)
+	}
+
+	listener := &EventListener{
+		conn:   conn,
+		events: make(chan types.Event),
+	}
+
+	go func() {
+		<-ctx.Done()
+		listener.Disconnect()
+	}()
+
+	go func() {
+		defer close(listener.events)
+
+		for {
+			event := types.Event{}
+			err := conn.ReadJSON(&event)
+			if err != nil {
+				if !websocket.IsCloseError(err, websocket.CloseNormalClosure) && ctx.Err() == nil {
+					listener.errLock.Lock()
+					listener.err = err
+					listener.errLock.Unlock()
+				}
+
+				return
+			}
+
+			select {
+			case listener.events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return listener, nil
+}
+
+// SendEvent forwards the event to the cluster member targeted by this client, to be sent to its event listeners.
+func SendEvent(ctx context.Context, c *Client, event types.Event) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("events"), event, nil)
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// GetHeartbeat returns the cluster-wide heartbeat configuration, along with the configuration in effect on each cluster member.
+func (c *Client) GetHeartbeat(ctx context.Context) (*types.Heartbeat, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	heartbeat := types.Heartbeat{}
+	err := c.QueryStruct(queryCtx, "GET", internalTypes.PublicEndpoint, api.NewURL().Path("heartbeat"), nil, &heartbeat)
+	if err != nil {
+		return nil, err
+	}
+
+	return &heartbeat, nil
+}
+
+// UpdateHeartbeatConfig sets the cluster-wide heartbeat configuration.
+func (c *Client) UpdateHeartbeatConfig(ctx context.Context, config types.HeartbeatConfig) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "PUT", internalTypes.PublicEndpoint, api.NewURL().Path("heartbeat"), config, nil)
+}
+
+// GetHeartbeatConfig returns the heartbeat configuration in effect on the cluster member targeted by this client.
+func GetHeartbeatConfig(ctx context.Context, c *Client) (*types.HeartbeatConfig, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	config := types.HeartbeatConfig{}
+	err := c.QueryStruct(queryCtx, "GET", internalTypes.InternalEndpoint, api.NewURL().Path("heartbeat"), nil, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
@@ -26,6 +26,22 @@ func RunPostRemoveHook(ctx context.Context, c *Client, config internalTypes.Hook
 	return c.QueryStruct(queryCtx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("hooks", string(internalTypes.PostRemove)), config, nil)
 }
 
+// RunPreEvacuateHook executes the PreEvacuate hook on the cluster member targeted by this client.
+func RunPreEvacuateHook(ctx context.Context, c *Client) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("hooks", string(internalTypes.PreEvacuate)), nil, nil)
+}
+
+// RunPostRestoreHook executes the PostRestore hook on the cluster member targeted by this client.
+func RunPostRestoreHook(ctx context.Context, c *Client) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("hooks", string(internalTypes.PostRestore)), nil, nil)
+}
+
 // RunNewMemberHook executes the OnNewMember hook with the given configuration on the cluster member targeted by this client.
 func RunNewMemberHook(ctx context.Context, c *Client, config internalTypes.HookNewMemberOptions) error {
 	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
@@ -34,6 +50,14 @@ func RunNewMemberHook(ctx context.Context, c *Client, config internalTypes.HookN
 	return c.QueryStruct(queryCtx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("hooks", string(internalTypes.OnNewMember)), config, nil)
 }
 
+// RunMemberStateHook executes the OnMemberOffline or OnMemberOnline hook with the given configuration on the cluster member targeted by this client.
+func RunMemberStateHook(ctx context.Context, c *Client, hookType internalTypes.HookType, config internalTypes.HookMemberStateOptions) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("hooks", string(hookType)), config, nil)
+}
+
 // RunOnDaemonConfigUpdateHook executes the OnDaemonConfigUpdate hook with the given configuration on the cluster member targeted by this client.
 func RunOnDaemonConfigUpdateHook(ctx context.Context, c *Client, config *types.DaemonConfig) error {
 	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
@@ -41,3 +65,11 @@ func RunOnDaemonConfigUpdateHook(ctx context.Context, c *Client, config *types.D
 
 	return c.QueryStruct(queryCtx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("hooks", string(internalTypes.OnDaemonConfigUpdate)), config, nil)
 }
+
+// RunConfigChangeHook executes the OnConfigChange hook with the given configuration on the cluster member targeted by this client.
+func RunConfigChangeHook(ctx context.Context, c *Client, config internalTypes.HookConfigChangeOptions) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("hooks", string(internalTypes.OnConfigChange)), config, nil)
+}
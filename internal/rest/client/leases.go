@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// GetLeases returns all cluster-wide leases.
+func (c *Client) GetLeases(ctx context.Context) ([]types.Lease, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	leases := []types.Lease{}
+	err := c.QueryStruct(queryCtx, "GET", internalTypes.PublicEndpoint, api.NewURL().Path("leases"), nil, &leases)
+
+	return leases, err
+}
+
+// GetLease returns the cluster-wide lease with the given name.
+func (c *Client) GetLease(ctx context.Context, name string) (*types.Lease, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	lease := types.Lease{}
+	err := c.QueryStruct(queryCtx, "GET", internalTypes.PublicEndpoint, api.NewURL().Path("leases", name), nil, &lease)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lease, nil
+}
+
+// BreakLease forcibly expires the cluster-wide lease with the given name, regardless of its holder.
+func (c *Client) BreakLease(ctx context.Context, name string) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "DELETE", internalTypes.PublicEndpoint, api.NewURL().Path("leases", name), nil, nil)
+}
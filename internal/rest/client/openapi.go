@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	"github.com/canonical/microcluster/v2/rest/openapi"
+)
+
+// GetOpenAPI returns the OpenAPI document describing the resources served by the named listener.
+// If no listener is given, the document of the core API listener is returned.
+func (c *Client) GetOpenAPI(ctx context.Context, listener string) (*openapi.Document, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	endpoint := api.NewURL().Path("openapi")
+	if listener != "" {
+		endpoint = endpoint.WithQuery("listener", listener)
+	}
+
+	doc := openapi.Document{}
+	err := c.QueryStruct(queryCtx, "GET", internalTypes.ControlEndpoint, endpoint, nil, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// operationWaitTimeout is the maximum time a single wait request blocks on the daemon.
+const operationWaitTimeout = 20 * time.Second
+
+// GetOperations returns the operations on the cluster member targeted by this client.
+func (c *Client) GetOperations(ctx context.Context) ([]types.Operation, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	ops := []types.Operation{}
+	err := c.QueryStruct(queryCtx, "GET", internalTypes.PublicEndpoint, api.NewURL().Path("operations"), nil, &ops)
+
+	return ops, err
+}
+
+// GetOperation returns the operation with the given ID.
+func (c *Client) GetOperation(ctx context.Context, id string) (*types.Operation, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	op := types.Operation{}
+	err := c.QueryStruct(queryCtx, "GET", internalTypes.PublicEndpoint, api.NewURL().Path("operations", id), nil, &op)
+	if err != nil {
+		return nil, err
+	}
+
+	return &op, nil
+}
+
+// CancelOperation cancels the operation with the given ID.
+func (c *Client) CancelOperation(ctx context.Context, id string) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "DELETE", internalTypes.PublicEndpoint, api.NewURL().Path("operations", id), nil, nil)
+}
+
+// WaitOperation blocks until the operation with the given ID finishes or the context is cancelled, and returns the final operation.
+func (c *Client) WaitOperation(ctx context.Context, id string) (*types.Operation, error) {
+	timeout := strconv.Itoa(int(operationWaitTimeout.Seconds()))
+	endpoint := api.NewURL().Path("operations", id, "wait").WithQuery("timeout", timeout)
+	for {
+		queryCtx, cancel := context.WithTimeout(ctx, operationWaitTimeout+10*time.Second)
+		op := types.Operation{}
+		err := c.QueryStruct(queryCtx, "GET", internalTypes.PublicEndpoint, endpoint, nil, &op)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Status.IsFinal() {
+			return &op, nil
+		}
+
+		err = ctx.Err()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// QueryOperation sends a request to an endpoint that may start an operation, and waits for the operation to finish.
+// If the endpoint responds synchronously, the request is considered complete.
+// An error is returned if the operation fails or is cancelled.
+func (c *Client) QueryOperation(ctx context.Context, method string, endpointType types.EndpointPrefix, endpoint *api.URL, data any) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	resp, _, err := c.rawQuery(queryCtx, method, c.endpointURL(endpointType, endpoint), data, "")
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	if resp.Type != api.AsyncResponse {
+		return nil
+	}
+
+	op := types.Operation{}
+	err = resp.MetadataAsStruct(&op)
+	if err != nil {
+		return fmt.Errorf("Failed to parse operation: %w", err)
+	}
+
+	finalOp, err := c.WaitOperation(ctx, op.ID)
+	if err != nil {
+		return fmt.Errorf("Failed waiting for operation %q: %w", op.Description, err)
+	}
+
+	if finalOp.Status != types.OperationSuccess {
+		return fmt.Errorf("%s", finalOp.Err)
+	}
+
+	return nil
+}
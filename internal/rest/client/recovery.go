@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcluster/v2/internal/rest/types"
+)
+
+// PushRecoveryTarball sends the quorum-loss recovery tarball to the cluster member, to be loaded the next time it starts.
+func PushRecoveryTarball(ctx context.Context, c *Client, tarball io.Reader) (*types.RecoveryTarballReceipt, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	receipt := types.RecoveryTarballReceipt{}
+	err := c.QueryStruct(queryCtx, "POST", types.InternalEndpoint, api.NewURL().Path("recovery"), tarball, &receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &receipt, nil
+}
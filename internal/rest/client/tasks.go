@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// GetTasks returns the status of the scheduled tasks on the cluster member targeted by this client.
+func (c *Client) GetTasks(ctx context.Context) ([]types.TaskStatus, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	tasks := []types.TaskStatus{}
+	err := c.QueryStruct(queryCtx, "GET", internalTypes.PublicEndpoint, api.NewURL().Path("tasks"), nil, &tasks)
+
+	return tasks, err
+}
+
+// RunTask runs the named task on the cluster member targeted by this client, and waits for it to finish.
+// The run is bounded only by the given context.
+func RunTask(ctx context.Context, c *Client, name string) error {
+	return c.QueryStruct(ctx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("tasks", name), nil, nil)
+}
@@ -10,8 +10,10 @@ import (
 
 // TLSClientConfig returns a TLS configuration suitable for establishing horizontal and vertical connections.
 // clientCert contains the private key pair for the client. remoteCert is the public
-// key of the server we are connecting to.
-func TLSClientConfig(clientCert *shared.CertInfo, remoteCert *x509.Certificate) (*tls.Config, error) {
+// key of the server we are connecting to. The server may also present any of the alternateRemoteCerts, such as the
+// next or previous cluster certificate while it is rotated. If clientCert and remoteCert were issued by the same CA,
+// the certificate chain presented by the server must lead to that CA.
+func TLSClientConfig(clientCert *shared.CertInfo, remoteCert *x509.Certificate, alternateRemoteCerts ...*x509.Certificate) (*tls.Config, error) {
 	if clientCert == nil {
 		return nil, fmt.Errorf("Invalid client certificate")
 	}
@@ -20,6 +22,13 @@ func TLSClientConfig(clientCert *shared.CertInfo, remoteCert *x509.Certificate)
 		return nil, fmt.Errorf("Invalid remote public key")
 	}
 
+	// The CA is only enforced if the server is expected to present a certificate issued by it. Otherwise, such as
+	// when the cluster certificate is self-signed, the server could never satisfy it.
+	ca := clientCert.CA()
+	if ca != nil && !issuedBy(remoteCert, ca) {
+		ca = nil
+	}
+
 	keypair := clientCert.KeyPair()
 	config := shared.InitTLSConfig()
 	config.Certificates = []tls.Certificate{keypair}
@@ -41,5 +50,126 @@ func TLSClientConfig(clientCert *shared.CertInfo, remoteCert *x509.Certificate)
 		config.ServerName = remoteCert.DNSNames[0]
 	}
 
+	if len(alternateRemoteCerts) > 0 {
+		trustedCerts := append([]*x509.Certificate{remoteCert}, alternateRemoteCerts...)
+
+		// The server name can only match one of the trusted certificates, so verify the server certificate
+		// against each of them instead.
+		config.InsecureSkipVerify = true
+		config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyRemoteCert(rawCerts, trustedCerts)
+		}
+	}
+
+	// If the client keypair and the remote certificate were issued by the same CA, the server certificate chain must
+	// also lead to that CA, on top of matching a trusted certificate.
+	if ca != nil {
+		verifyTrusted := config.VerifyPeerCertificate
+		config.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+			if verifyTrusted != nil {
+				err := verifyTrusted(rawCerts, verifiedChains)
+				if err != nil {
+					return err
+				}
+			}
+
+			return verifyRemoteCertChain(rawCerts, ca)
+		}
+	}
+
 	return config, nil
 }
+
+// issuedBy returns whether the given certificate was issued by the given CA.
+func issuedBy(cert *x509.Certificate, ca *x509.Certificate) bool {
+	opts := x509.VerifyOptions{
+		Roots:     x509.NewCertPool(),
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+
+	opts.Roots.AddCert(ca)
+	_, err := cert.Verify(opts)
+
+	return err == nil
+}
+
+// parseRemoteCerts parses the certificate chain presented by the server, returning the server certificate and a pool
+// of the intermediate certificates that follow it.
+func parseRemoteCerts(rawCerts [][]byte) (*x509.Certificate, *x509.CertPool, error) {
+	if len(rawCerts) == 0 {
+		return nil, nil, fmt.Errorf("Server did not present a certificate")
+	}
+
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, rawCert := range rawCerts {
+		cert, err := x509.ParseCertificate(rawCert)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to parse server certificate: %w", err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	return certs[0], intermediates, nil
+}
+
+// verifyRemoteCertChain checks that the certificate chain presented by the server leads to the given CA.
+func verifyRemoteCertChain(rawCerts [][]byte, ca *x509.Certificate) error {
+	cert, intermediates, err := parseRemoteCerts(rawCerts)
+	if err != nil {
+		return err
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         x509.NewCertPool(),
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	opts.Roots.AddCert(ca)
+	_, err = cert.Verify(opts)
+	if err != nil {
+		return fmt.Errorf("Server certificate is not issued by the cluster CA: %w", err)
+	}
+
+	return nil
+}
+
+// verifyRemoteCert checks that the certificate chain presented by the server is trusted by any of the given certificates.
+// Each trusted certificate is treated as a CA and verifies the chain against its own DNS name.
+func verifyRemoteCert(rawCerts [][]byte, trustedCerts []*x509.Certificate) error {
+	cert, intermediates, err := parseRemoteCerts(rawCerts)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, trustedCert := range trustedCerts {
+		rootCert := *trustedCert
+		rootCert.IsCA = true
+		rootCert.KeyUsage = x509.KeyUsageCertSign
+
+		opts := x509.VerifyOptions{
+			Roots:         x509.NewCertPool(),
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+
+		opts.Roots.AddCert(&rootCert)
+		if len(trustedCert.DNSNames) > 0 {
+			opts.DNSName = trustedCert.DNSNames[0]
+		}
+
+		_, lastErr = cert.Verify(opts)
+		if lastErr == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Server certificate is not trusted: %w", lastErr)
+}
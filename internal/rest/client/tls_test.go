@@ -0,0 +1,167 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/stretchr/testify/suite"
+)
+
+type tlsSuite struct {
+	suite.Suite
+}
+
+func TestTLSSuite(t *testing.T) {
+	suite.Run(t, new(tlsSuite))
+}
+
+// newTestCert returns a new self-signed server certificate, and the keypair it belongs to.
+func (t *tlsSuite) newTestCert() (*x509.Certificate, *shared.CertInfo) {
+	certPEM, keyPEM, err := shared.GenerateMemCert(false, shared.CertOptions{AddHosts: true})
+	t.Require().NoError(err)
+
+	block, _ := pem.Decode(certPEM)
+	t.Require().NotNil(block)
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	t.Require().NoError(err)
+
+	keypair, err := shared.KeyPairFromRaw(certPEM, keyPEM)
+	t.Require().NoError(err)
+
+	return cert, keypair
+}
+
+func (t *tlsSuite) Test_alternateRemoteCerts() {
+	current, clientCert := t.newTestCert()
+	next, _ := t.newTestCert()
+	other, _ := t.newTestCert()
+
+	// Without alternate certificates, the standard verification against the remote certificate applies.
+	config, err := TLSClientConfig(clientCert, current)
+	t.Require().NoError(err)
+	t.False(config.InsecureSkipVerify)
+	t.Nil(config.VerifyPeerCertificate)
+
+	config, err = TLSClientConfig(clientCert, current, next)
+	t.Require().NoError(err)
+	t.Require().NotNil(config.VerifyPeerCertificate)
+
+	t.NoError(config.VerifyPeerCertificate([][]byte{current.Raw}, nil))
+	t.NoError(config.VerifyPeerCertificate([][]byte{next.Raw}, nil))
+	t.Error(config.VerifyPeerCertificate([][]byte{other.Raw}, nil))
+	t.Error(config.VerifyPeerCertificate(nil, nil))
+}
+
+// newTestCA returns a new self-signed CA certificate and its private key.
+func (t *tlsSuite) newTestCA() (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	t.Require().NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	t.Require().NoError(err)
+
+	cert, err := x509.ParseCertificate(certDER)
+	t.Require().NoError(err)
+
+	return cert, key
+}
+
+// newIssuedCert returns a new server certificate issued by the given CA, and the keypair it belongs to.
+func (t *tlsSuite) newIssuedCert(ca *x509.Certificate, caKey *ecdsa.PrivateKey, name string) (*x509.Certificate, *shared.CertInfo) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	t.Require().NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	t.Require().NoError(err)
+
+	cert, err := x509.ParseCertificate(certDER)
+	t.Require().NoError(err)
+
+	keypair := tls.Certificate{Certificate: [][]byte{certDER}, PrivateKey: key, Leaf: cert}
+
+	return cert, shared.NewCertInfo(keypair, ca, nil)
+}
+
+func (t *tlsSuite) Test_caChain() {
+	ca, caKey := t.newTestCA()
+	otherCA, otherCAKey := t.newTestCA()
+
+	_, clientCert := t.newIssuedCert(ca, caKey, "member1")
+	remote, _ := t.newIssuedCert(ca, caKey, "cluster")
+	foreign, _ := t.newIssuedCert(otherCA, otherCAKey, "cluster")
+	selfSigned, _ := t.newTestCert()
+
+	config, err := TLSClientConfig(clientCert, remote)
+	t.Require().NoError(err)
+	t.False(config.InsecureSkipVerify)
+	t.Require().NotNil(config.VerifyPeerCertificate)
+
+	// The server certificate chain must lead to the CA of the client keypair.
+	t.NoError(config.VerifyPeerCertificate([][]byte{remote.Raw}, nil))
+	t.Error(config.VerifyPeerCertificate([][]byte{foreign.Raw}, nil))
+	t.Error(config.VerifyPeerCertificate([][]byte{selfSigned.Raw}, nil))
+	t.Error(config.VerifyPeerCertificate(nil, nil))
+
+	// Alternate certificates must be trusted as well as lead to the CA.
+	next, _ := t.newIssuedCert(ca, caKey, "cluster")
+	other, _ := t.newIssuedCert(ca, caKey, "cluster")
+	config, err = TLSClientConfig(clientCert, remote, next, foreign)
+	t.Require().NoError(err)
+	t.Require().NotNil(config.VerifyPeerCertificate)
+
+	t.NoError(config.VerifyPeerCertificate([][]byte{next.Raw}, nil))
+	t.Error(config.VerifyPeerCertificate([][]byte{foreign.Raw}, nil))
+	t.Error(config.VerifyPeerCertificate([][]byte{other.Raw}, nil))
+}
+
+func (t *tlsSuite) Test_caChainSelfSignedRemote() {
+	ca, caKey := t.newTestCA()
+	_, clientCert := t.newIssuedCert(ca, caKey, "member1")
+	remote, _ := t.newTestCert()
+	next, _ := t.newTestCert()
+	other, _ := t.newTestCert()
+
+	// A self-signed remote certificate can't lead to the CA of the client keypair, so only trust is verified.
+	config, err := TLSClientConfig(clientCert, remote)
+	t.Require().NoError(err)
+	t.False(config.InsecureSkipVerify)
+	t.Nil(config.VerifyPeerCertificate)
+
+	config, err = TLSClientConfig(clientCert, remote, next)
+	t.Require().NoError(err)
+	t.Require().NotNil(config.VerifyPeerCertificate)
+
+	t.NoError(config.VerifyPeerCertificate([][]byte{remote.Raw}, nil))
+	t.NoError(config.VerifyPeerCertificate([][]byte{next.Raw}, nil))
+	t.Error(config.VerifyPeerCertificate([][]byte{other.Raw}, nil))
+}
@@ -18,6 +18,14 @@ func AddTrustStoreEntry(ctx context.Context, c *Client, args types.ClusterMember
 	return c.QueryStruct(queryCtx, "POST", internalTypes.InternalEndpoint, api.NewURL().Path("truststore"), args, nil)
 }
 
+// UpdateTrustStoreEntry replaces the certificate of the given cluster member in the truststore on all cluster members.
+func UpdateTrustStoreEntry(ctx context.Context, c *Client, args types.ClusterMemberLocal) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return c.QueryStruct(queryCtx, "PUT", internalTypes.InternalEndpoint, api.NewURL().Path("truststore", args.Name), args, nil)
+}
+
 // DeleteTrustStoreEntry deletes the record corresponding to the given cluster member from the trust store.
 func DeleteTrustStoreEntry(ctx context.Context, c *Client, name string) error {
 	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
@@ -15,7 +15,7 @@ import (
 var api10Cmd = rest.Endpoint{
 	AllowedBeforeInit: true,
 
-	Get: rest.EndpointAction{Handler: api10Get, AllowUntrusted: true},
+	Get: rest.EndpointAction{Handler: api10Get, AllowUntrusted: true, Summary: "Get the status of the cluster member", Response: internalTypes.Server{}},
 }
 
 func api10Get(s state.State, r *http.Request) response.Response {
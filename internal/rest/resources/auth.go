@@ -0,0 +1,88 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/util"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/auth"
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+var authPolicyCmd = rest.Endpoint{
+	Path: "auth/policy",
+
+	Get: rest.EndpointAction{Handler: authPolicyGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementAuthRead, Summary: "Get the authorization policy", Response: types.AuthPolicy{}},
+	Put: rest.EndpointAction{Handler: authPolicyPut, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementAuthWrite, Summary: "Replace the authorization policy", Request: types.AuthPolicy{}},
+}
+
+// authPolicyGet returns the authorization policy.
+func authPolicyGet(s state.State, r *http.Request) response.Response {
+	var policy *types.AuthPolicy
+	err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		policy, err = cluster.GetCoreAuthPolicy(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, policy, policy)
+}
+
+// authPolicyPut replaces the authorization policy, as long as the ETag in the If-Match header, if any, matches the current policy.
+func authPolicyPut(s state.State, r *http.Request) response.Response {
+	req := types.AuthPolicy{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Roles == nil {
+		req.Roles = map[string][]types.Entitlement{}
+	}
+
+	err = auth.Validate(req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		current, err := cluster.GetCoreAuthPolicy(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		err = util.EtagCheck(r, current)
+		if err != nil {
+			return err
+		}
+
+		return cluster.ReplaceCoreAuthPolicy(ctx, tx, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Apply the new policy on this cluster member straight away. Other cluster members pick it up once their cached
+	// policy expires.
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	intState.AuthPolicy.Set(req)
+
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,167 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	dqliteClient "github.com/canonical/go-dqlite/client"
+	"github.com/canonical/lxd/shared/logger"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/health"
+	"github.com/canonical/microcluster/v2/internal/operations"
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// autoRemoveMu is held while a cluster member is being removed automatically, so that only one is removed at a time.
+var autoRemoveMu sync.Mutex
+
+// maybeAutoRemoveClusterMember starts an operation that forcibly removes the cluster member that has missed heartbeats
+// for the longest, if it has missed them for longer than the auto-removal policy allows. Only the heartbeats sent by
+// this cluster member since it became the leader are considered, so no cluster member is removed until it has been the
+// leader for as long as the policy allows. No cluster member is removed if the removal would leave the dqlite voters
+// without a quorum of online cluster members, or take the cluster below the minimum number of members of the policy.
+func maybeAutoRemoveClusterMember(ctx context.Context, s state.State, policy internalState.AutoRemovePolicy) error {
+	if policy.After == 0 {
+		return nil
+	}
+
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return err
+	}
+
+	var members []cluster.CoreClusterMember
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		members, err = cluster.GetCoreClusterMembers(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	memberHealth := intState.FailureDetector.Snapshot()
+	member := autoRemoveCandidate(members, memberHealth, s.Address().URL.Host, memberStatus.leaderStart(), policy, time.Now())
+	if member == nil {
+		return nil
+	}
+
+	if !autoRemoveMu.TryLock() {
+		logger.Debug("Skipping automatic removal, another cluster member is being removed", logger.Ctx{"name": member.Name})
+		return nil
+	}
+
+	unlock := true
+	defer func() {
+		if unlock {
+			autoRemoveMu.Unlock()
+		}
+	}()
+
+	leaderCtx, cancel := context.WithTimeout(ctx, time.Second*30)
+	defer cancel()
+
+	leader, err := s.Database().Leader(leaderCtx)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := leader.Cluster(leaderCtx)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		if node.Address != member.Address {
+			continue
+		}
+
+		// Removing the cluster member affects the voters in the same way as demoting it to spare.
+		online := onlineDqliteMembers(leaderCtx, s, nodes)
+		err = checkRoleChangeQuorum(nodes, node, dqliteClient.Spare, online)
+		if err != nil {
+			logger.Warn("Skipping automatic removal of unreachable cluster member", logger.Ctx{"name": member.Name, "error": err})
+			return nil
+		}
+	}
+
+	lastSeen := memberHealth[member.Name].LastSeen
+	missed := time.Since(lastSeen).Truncate(time.Second)
+	logger.Warn("Automatically removing unreachable cluster member", logger.Ctx{"name": member.Name, "address": member.Address, "lastSeen": lastSeen, "missedHeartbeats": memberHealth[member.Name].MissedHeartbeats})
+
+	err = s.SendEvent(types.EventMemberAutoRemoved, types.EventMember{Name: member.Name, Address: member.Address, Role: string(member.Role), Error: fmt.Sprintf("Missed heartbeats for %s", missed)})
+	if err != nil {
+		return err
+	}
+
+	name := member.Name
+	_, err = s.Operations().Create(fmt.Sprintf("Automatically removing cluster member %q", name), false, func(ctx context.Context, op *operations.Operation) error {
+		defer autoRemoveMu.Unlock()
+
+		return removeClusterMember(ctx, op, s, name, true)
+	})
+	if err != nil {
+		return err
+	}
+
+	unlock = false
+
+	return nil
+}
+
+// autoRemoveCandidate returns the cluster member that has missed heartbeats for the longest, if it has missed them for
+// longer than the policy allows, and removing it would not take the cluster below the policy's minimum number of members.
+// The heartbeats of each cluster member are taken from the failure detector of the leader, and only those missed since
+// the leader took over at leaderSince count, as heartbeats recorded before then may be stale after an outage.
+// Pending cluster members, members in maintenance, members that have not missed a heartbeat and the leader are never candidates.
+func autoRemoveCandidate(members []cluster.CoreClusterMember, memberHealth map[string]health.Member, leaderAddress string, leaderSince time.Time, policy internalState.AutoRemovePolicy, now time.Time) *cluster.CoreClusterMember {
+	if leaderSince.IsZero() || now.Sub(leaderSince) < policy.After {
+		return nil
+	}
+
+	var candidate *cluster.CoreClusterMember
+	var candidateSince time.Time
+	activeMembers := 0
+	for i, member := range members {
+		if member.Role == cluster.Pending {
+			continue
+		}
+
+		activeMembers++
+		if member.Maintenance || member.Address == leaderAddress {
+			continue
+		}
+
+		memberHealth, ok := memberHealth[member.Name]
+		if !ok || memberHealth.MissedHeartbeats == 0 {
+			continue
+		}
+
+		unreachableSince := memberHealth.LastSeen
+		if unreachableSince.Before(leaderSince) {
+			unreachableSince = leaderSince
+		}
+
+		if now.Sub(unreachableSince) < policy.After {
+			continue
+		}
+
+		if candidate == nil || unreachableSince.Before(candidateSince) {
+			candidate = &members[i]
+			candidateSince = unreachableSince
+		}
+	}
+
+	if candidate == nil || activeMembers-1 < max(policy.MinMembers, 1) {
+		return nil
+	}
+
+	return candidate
+}
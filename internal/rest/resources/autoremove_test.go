@@ -0,0 +1,134 @@
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/health"
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+)
+
+type autoRemoveSuite struct {
+	suite.Suite
+}
+
+func TestAutoRemoveSuite(t *testing.T) {
+	suite.Run(t, new(autoRemoveSuite))
+}
+
+func (t *autoRemoveSuite) Test_autoRemoveCandidate() {
+	now := time.Now()
+	leaderSince := now.Add(-time.Hour)
+	policy := internalState.AutoRemovePolicy{After: time.Minute, MinMembers: 2}
+
+	member := func(name string) cluster.CoreClusterMember {
+		return cluster.CoreClusterMember{Name: name, Address: name + ":8443", Role: "voter", Heartbeat: now}
+	}
+
+	seen := func(missed time.Duration) health.Member {
+		if missed == 0 {
+			return health.Member{LastSeen: now}
+		}
+
+		return health.Member{LastSeen: now.Add(-missed), MissedHeartbeats: int(missed / time.Second)}
+	}
+
+	tests := []struct {
+		name         string
+		members      []cluster.CoreClusterMember
+		memberHealth map[string]health.Member
+		leaderSince  time.Time
+		candidate    string
+	}{
+		{
+			name:         "All members responding",
+			members:      []cluster.CoreClusterMember{member("n1"), member("n2"), member("n3")},
+			memberHealth: map[string]health.Member{"n1": seen(0), "n2": seen(0), "n3": seen(0)},
+			leaderSince:  leaderSince,
+		},
+		{
+			name:         "Longest unreachable member is chosen",
+			members:      []cluster.CoreClusterMember{member("n1"), member("n2"), member("n3"), member("n4")},
+			memberHealth: map[string]health.Member{"n1": seen(0), "n2": seen(2 * time.Minute), "n3": seen(3 * time.Minute), "n4": seen(0)},
+			leaderSince:  leaderSince,
+			candidate:    "n3",
+		},
+		{
+			name:         "Removal would go below the minimum number of members",
+			members:      []cluster.CoreClusterMember{member("n1"), member("n2")},
+			memberHealth: map[string]health.Member{"n1": seen(0), "n2": seen(2 * time.Minute)},
+			leaderSince:  leaderSince,
+		},
+		{
+			name: "Pending members don't count towards the minimum",
+			members: []cluster.CoreClusterMember{
+				member("n1"),
+				member("n2"),
+				{Name: "n3", Address: "n3:8443", Role: cluster.Pending},
+			},
+			memberHealth: map[string]health.Member{"n1": seen(0), "n2": seen(2 * time.Minute)},
+			leaderSince:  leaderSince,
+		},
+		{
+			name: "Members in maintenance or without heartbeats are skipped",
+			members: []cluster.CoreClusterMember{
+				member("n1"),
+				{Name: "n2", Address: "n2:8443", Role: "spare", Heartbeat: now.Add(-time.Hour), Maintenance: true},
+				{Name: "n3", Address: "n3:8443", Role: "spare"},
+				member("n4"),
+			},
+			memberHealth: map[string]health.Member{"n1": seen(0), "n2": seen(time.Hour), "n4": seen(0)},
+			leaderSince:  leaderSince,
+		},
+		{
+			name:         "Leader is never removed",
+			members:      []cluster.CoreClusterMember{member("n1"), member("n2"), member("n3")},
+			memberHealth: map[string]health.Member{"n1": seen(time.Hour), "n2": seen(0), "n3": seen(0)},
+			leaderSince:  leaderSince,
+		},
+		{
+			name:         "Members that have not missed a heartbeat are skipped",
+			members:      []cluster.CoreClusterMember{member("n1"), member("n2"), member("n3")},
+			memberHealth: map[string]health.Member{"n1": seen(0), "n2": {LastSeen: now.Add(-time.Hour)}, "n3": seen(0)},
+			leaderSince:  leaderSince,
+		},
+		{
+			name: "Leader just started with stale heartbeats",
+			members: []cluster.CoreClusterMember{
+				{Name: "n1", Address: "n1:8443", Role: "voter", Heartbeat: now.Add(-time.Hour)},
+				{Name: "n2", Address: "n2:8443", Role: "stand-by", Heartbeat: now.Add(-time.Hour)},
+				{Name: "n3", Address: "n3:8443", Role: "spare", Heartbeat: now.Add(-time.Hour)},
+				{Name: "n4", Address: "n4:8443", Role: "spare", Heartbeat: now.Add(-time.Hour)},
+			},
+			memberHealth: map[string]health.Member{"n1": seen(0), "n2": seen(time.Hour), "n3": seen(time.Hour), "n4": seen(time.Hour)},
+			leaderSince:  now.Add(-30 * time.Second),
+		},
+		{
+			name:         "Heartbeats missed before the leader took over don't count",
+			members:      []cluster.CoreClusterMember{member("n1"), member("n2"), member("n3"), member("n4")},
+			memberHealth: map[string]health.Member{"n1": seen(0), "n2": seen(time.Hour), "n3": seen(70 * time.Second), "n4": seen(0)},
+			leaderSince:  now.Add(-90 * time.Second),
+			candidate:    "n2",
+		},
+		{
+			name:         "Never the leader",
+			members:      []cluster.CoreClusterMember{member("n1"), member("n2"), member("n3"), member("n4")},
+			memberHealth: map[string]health.Member{"n1": seen(0), "n2": seen(time.Hour), "n3": seen(0), "n4": seen(0)},
+		},
+	}
+
+	for i, test := range tests {
+		t.T().Logf("%s (case %d)", test.name, i)
+
+		candidate := autoRemoveCandidate(test.members, test.memberHealth, "n1:8443", test.leaderSince, policy, now)
+		if test.candidate == "" {
+			t.Nil(candidate)
+		} else {
+			t.Require().NotNil(candidate)
+			t.Equal(test.candidate, candidate.Name)
+		}
+	}
+}
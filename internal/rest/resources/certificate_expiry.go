@@ -0,0 +1,306 @@
+package resources
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/internal/sys"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// certificateExpiryThreshold is how long before they expire that keypairs are reported as expiring, and that server
+// certificates are renewed.
+const certificateExpiryThreshold = 30 * 24 * time.Hour
+
+// certificateExpiryWarningInterval is how often an event is sent for each keypair that is close to expiring.
+const certificateExpiryWarningInterval = 24 * time.Hour
+
+// certificateExpiryCheckInterval is how often keypairs are checked for expiry, as heartbeats are far more frequent.
+const certificateExpiryCheckInterval = time.Hour
+
+// certificateExpiryCheckJitter is the maximum random delay added to each interval, so that cluster members started
+// together don't all renew their server certificates at once.
+const certificateExpiryCheckJitter = 10 * time.Minute
+
+// certificateExpiry keeps track of the expiry checks run by this cluster member.
+var certificateExpiry = &certificateExpiryTracker{warned: map[string]time.Time{}}
+
+// certificateExpiryTracker records when keypairs are next due to be checked, and when an event was last sent for each
+// expiring certificate, keyed by fingerprint, so that neither happens on every heartbeat.
+type certificateExpiryTracker struct {
+	// checkMu is held while checking the keypairs, so that only one server certificate renewal runs at a time.
+	checkMu sync.Mutex
+
+	lock      sync.Mutex
+	nextCheck time.Time
+	warned    map[string]time.Time
+}
+
+// shouldCheck returns whether the keypairs are due to be checked at the given time, and if so schedules the next check.
+func (t *certificateExpiryTracker) shouldCheck(now time.Time) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if now.Before(t.nextCheck) {
+		return false
+	}
+
+	t.nextCheck = now.Add(certificateExpiryCheckInterval + rand.N(certificateExpiryCheckJitter))
+
+	return true
+}
+
+// shouldWarn returns whether an event should be sent for the certificate with the given fingerprint, and if so records
+// that one was sent at the given time.
+func (t *certificateExpiryTracker) shouldWarn(fingerprint string, now time.Time) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	last, ok := t.warned[fingerprint]
+	if ok && now.Sub(last) < certificateExpiryWarningInterval {
+		return false
+	}
+
+	t.warned[fingerprint] = now
+
+	return true
+}
+
+// newCertificateExpiry returns the expiry of the given certificate.
+func newCertificateExpiry(name types.CertificateName, cert *x509.Certificate) types.CertificateExpiry {
+	return types.CertificateExpiry{Name: name, Fingerprint: shared.CertFingerprint(cert), NotAfter: cert.NotAfter}
+}
+
+// localCertificateExpiries returns the expiry of the server and cluster certificates of this cluster member, and of
+// the certificates of its additional listeners.
+func localCertificateExpiries(s state.State) ([]types.CertificateExpiry, error) {
+	serverCert, err := s.ServerCert().PublicKeyX509()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse server certificate: %w", err)
+	}
+
+	clusterCert, err := s.ClusterCert().PublicKeyX509()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse cluster certificate: %w", err)
+	}
+
+	expiries := []types.CertificateExpiry{
+		newCertificateExpiry(types.ServerCertificateName, serverCert),
+		newCertificateExpiry(types.ClusterCertificateName, clusterCert),
+	}
+
+	certsDir := s.FileSystem().CertificatesDir
+	entries, err := os.ReadDir(certsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Failed to list additional listener certificates: %w", err)
+	}
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".crt")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		certPEM, err := os.ReadFile(filepath.Join(certsDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read certificate of additional listener %q: %w", name, err)
+		}
+
+		cert, err := types.ParseX509Certificate(string(certPEM))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse certificate of additional listener %q: %w", name, err)
+		}
+
+		expiries = append(expiries, newCertificateExpiry(types.CertificateName(name), cert.Certificate))
+	}
+
+	return expiries, nil
+}
+
+// expiringCertificates returns the certificates that expire within the expiry threshold of the given time.
+func expiringCertificates(expiries []types.CertificateExpiry, now time.Time) []types.CertificateExpiry {
+	return slices.DeleteFunc(slices.Clone(expiries), func(expiry types.CertificateExpiry) bool {
+		return expiry.NotAfter.Sub(now) > certificateExpiryThreshold
+	})
+}
+
+// checkCertificateExpiry renews the server certificate of this cluster member if it is close to expiring, and sends
+// an event for any other keypair that is. Nothing is done if a check is already running, or if the keypairs were
+// checked less than certificateExpiryCheckInterval ago.
+func checkCertificateExpiry(ctx context.Context, s state.State) {
+	if !certificateExpiry.shouldCheck(time.Now()) {
+		return
+	}
+
+	if !certificateExpiry.checkMu.TryLock() {
+		return
+	}
+
+	defer certificateExpiry.checkMu.Unlock()
+
+	expiries, err := localCertificateExpiries(s)
+	if err != nil {
+		logger.Error("Failed to check certificate expiry", logger.Ctx{"error": err})
+		return
+	}
+
+	now := time.Now()
+	for _, expiry := range expiringCertificates(expiries, now) {
+		if expiry.Name == types.ServerCertificateName {
+			err := renewServerCertificate(ctx, s)
+			if err == nil {
+				logger.Info("Renewed expiring server certificate", logger.Ctx{"fingerprint": expiry.Fingerprint, "expiry": expiry.NotAfter})
+
+				err = s.SendEvent(types.EventCertificateUpdated, types.EventCertificate{Name: types.ServerCertificateName})
+				if err != nil {
+					logger.Warn("Failed to send certificate event", logger.Ctx{"name": expiry.Name, "error": err})
+				}
+
+				continue
+			}
+
+			logger.Error("Failed to renew expiring server certificate", logger.Ctx{"fingerprint": expiry.Fingerprint, "expiry": expiry.NotAfter, "error": err})
+		}
+
+		if !certificateExpiry.shouldWarn(expiry.Fingerprint, now) {
+			continue
+		}
+
+		logger.Warn("Certificate is close to expiring", logger.Ctx{"name": expiry.Name, "fingerprint": expiry.Fingerprint, "expiry": expiry.NotAfter})
+
+		err = s.SendEvent(types.EventCertificateExpiring, expiry)
+		if err != nil {
+			logger.Warn("Failed to send certificate event", logger.Ctx{"name": expiry.Name, "error": err})
+		}
+	}
+}
+
+// renewServerCertificate replaces the server certificate of this cluster member with a new keypair, issued by the
+// certificate issuer if there is one.
+// The new keypair is staged in the state directory first, so that an interrupted renewal resumes with the same
+// keypair. The database record of the cluster member is then updated, as heartbeats distribute it to the truststore
+// of every cluster member, and the truststore entry is updated on all cluster members right away. Cluster members
+// keep trusting the current certificate alongside the new one, until this cluster member switches to the new keypair
+// and authenticates with it.
+func renewServerCertificate(ctx context.Context, s state.State) error {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return err
+	}
+
+	certPEM, err := stageServerCertificate(ctx, intState)
+	if err != nil {
+		return err
+	}
+
+	cert, err := types.ParseX509Certificate(string(certPEM))
+	if err != nil {
+		return fmt.Errorf("Failed to parse server certificate: %w", err)
+	}
+
+	var member *cluster.CoreClusterMember
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		member, err = cluster.GetCoreClusterMember(ctx, tx, s.Name())
+		if err != nil {
+			return err
+		}
+
+		member.Certificate = cert.String()
+
+		return cluster.UpdateCoreClusterMember(ctx, tx, member.Name, *member)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to record renewed server certificate: %w", err)
+	}
+
+	addrPort, err := types.ParseAddrPort(member.Address)
+	if err != nil {
+		return fmt.Errorf("Failed to parse address of cluster member %q: %w", member.Name, err)
+	}
+
+	localClient, err := internalClient.New(s.FileSystem().ControlSocket(), nil, nil, false)
+	if err != nil {
+		return err
+	}
+
+	// Cluster members that can't be reached learn of the renewed certificate from a heartbeat, so don't give up on
+	// the renewal if some of them can't be reached.
+	entry := types.ClusterMemberLocal{Name: member.Name, Address: addrPort, Certificate: *cert}
+	err = internalClient.UpdateTrustStoreEntry(ctx, localClient, entry)
+	if err != nil {
+		logger.Warn("Failed to update truststore entry of renewed server certificate on all cluster members", logger.Ctx{"error": err})
+	}
+
+	err = sys.SwitchKeyPair(s.FileSystem().StateDir, string(types.ServerCertificateName), sys.NextServerCertName)
+	if err != nil {
+		return fmt.Errorf("Failed to switch to renewed server certificate: %w", err)
+	}
+
+	err = intState.ReloadCert(types.ServerCertificateName)
+	if err != nil {
+		return err
+	}
+
+	// Now that the renewed certificate is used, cluster members can stop trusting the previous one.
+	err = internalClient.UpdateTrustStoreEntry(ctx, localClient, entry)
+	if err != nil {
+		logger.Warn("Failed to retire previous server certificate on all cluster members", logger.Ctx{"error": err})
+	}
+
+	return nil
+}
+
+// stageServerCertificate writes a new server keypair to the state directory, where it waits to replace the server
+// certificate, and returns the new certificate. If a renewal was interrupted, its staged keypair is used instead.
+func stageServerCertificate(ctx context.Context, s *internalState.InternalState) ([]byte, error) {
+	dir := s.FileSystem().StateDir
+	certPath := filepath.Join(dir, sys.NextServerCertName+".crt")
+	keypair, err := tls.LoadX509KeyPair(certPath, filepath.Join(dir, sys.NextServerCertName+".key"))
+	if err == nil {
+		staged, err := x509.ParseCertificate(keypair.Certificate[0])
+		if err == nil && time.Until(staged.NotAfter) > certificateExpiryThreshold {
+			certPEM, err := os.ReadFile(certPath)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read staged server certificate: %w", err)
+			}
+
+			return certPEM, nil
+		}
+	}
+
+	var certPEM, keyPEM, caPEM []byte
+	if s.CertificateIssuer != nil {
+		certPEM, keyPEM, caPEM, err = internalState.IssueKeyPair(ctx, s.CertificateIssuer, types.ServerCertificateName, s.Name())
+	} else {
+		certPEM, keyPEM, err = shared.GenerateMemCert(false, shared.CertOptions{AddHosts: true, SubjectName: s.Name()})
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate server certificate: %w", err)
+	}
+
+	err = util.WriteCert(dir, sys.NextServerCertName, certPEM, keyPEM, caPEM)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to stage renewed server certificate: %w", err)
+	}
+
+	return certPEM, nil
+}
@@ -0,0 +1,186 @@
+package resources
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+
+	"github.com/canonical/microcluster/v2/internal/sys"
+	"github.com/canonical/microcluster/v2/internal/trust"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type certificateExpirySuite struct {
+	suite.Suite
+}
+
+func TestCertificateExpirySuite(t *testing.T) {
+	suite.Run(t, new(certificateExpirySuite))
+}
+
+func (t *certificateExpirySuite) Test_expiringCertificates() {
+	now := time.Now()
+	expiries := []types.CertificateExpiry{
+		{Name: types.ServerCertificateName, Fingerprint: "server", NotAfter: now.Add(certificateExpiryThreshold - time.Hour)},
+		{Name: types.ClusterCertificateName, Fingerprint: "cluster", NotAfter: now.Add(certificateExpiryThreshold + time.Hour)},
+		{Name: "listener", Fingerprint: "listener", NotAfter: now.Add(-time.Hour)},
+	}
+
+	expiring := expiringCertificates(expiries, now)
+	t.Equal([]types.CertificateExpiry{expiries[0], expiries[2]}, expiring)
+
+	// The given expiries are left untouched.
+	t.Len(expiries, 3)
+	t.Equal("cluster", expiries[1].Fingerprint)
+}
+
+func (t *certificateExpirySuite) Test_shouldCheck() {
+	tracker := &certificateExpiryTracker{warned: map[string]time.Time{}}
+	now := time.Now()
+
+	t.True(tracker.shouldCheck(now))
+	t.False(tracker.shouldCheck(now))
+	t.False(tracker.shouldCheck(now.Add(certificateExpiryCheckInterval - time.Minute)))
+
+	// Checks are delayed by no more than the jitter.
+	t.True(tracker.shouldCheck(now.Add(certificateExpiryCheckInterval + certificateExpiryCheckJitter)))
+	t.False(tracker.shouldCheck(now.Add(certificateExpiryCheckInterval + certificateExpiryCheckJitter)))
+}
+
+func (t *certificateExpirySuite) Test_shouldWarn() {
+	tracker := &certificateExpiryTracker{warned: map[string]time.Time{}}
+	now := time.Now()
+
+	t.True(tracker.shouldWarn("fp1", now))
+	t.False(tracker.shouldWarn("fp1", now.Add(time.Hour)))
+	t.True(tracker.shouldWarn("fp2", now.Add(time.Hour)))
+
+	// Warnings are repeated once the interval has passed.
+	t.True(tracker.shouldWarn("fp1", now.Add(certificateExpiryWarningInterval)))
+	t.False(tracker.shouldWarn("fp1", now.Add(certificateExpiryWarningInterval+time.Hour)))
+}
+
+func (t *certificateExpirySuite) Test_renewedCertificateOverlap() {
+	oldPEM, _, err := shared.GenerateMemCert(false, shared.CertOptions{})
+	t.Require().NoError(err)
+
+	newPEM, _, err := shared.GenerateMemCert(false, shared.CertOptions{})
+	t.Require().NoError(err)
+
+	oldCert, err := types.ParseX509Certificate(string(oldPEM))
+	t.Require().NoError(err)
+
+	newCert, err := types.ParseX509Certificate(string(newPEM))
+	t.Require().NoError(err)
+
+	addrPort, err := types.ParseAddrPort("10.0.0.1:9000")
+	t.Require().NoError(err)
+
+	dir := t.T().TempDir()
+	remotes := &trust.Remotes{}
+	t.Require().NoError(remotes.Load(dir))
+	t.Require().NoError(remotes.Add(dir, trust.Remote{Location: trust.Location{Name: "n1", Address: addrPort}, Certificate: *oldCert}))
+
+	member := types.ClusterMember{ClusterMemberLocal: types.ClusterMemberLocal{Name: "n1", Address: addrPort, Certificate: *newCert}}
+	oldFingerprint := shared.CertFingerprint(oldCert.Certificate)
+	newFingerprint := shared.CertFingerprint(newCert.Certificate)
+
+	// Both certificates are trusted once the certificate is replaced, including after another update with the same one.
+	for i := 0; i < 2; i++ {
+		t.Require().NoError(remotes.Replace(dir, member))
+		t.Contains(remotes.CertificatesNative(), oldFingerprint)
+		t.Contains(remotes.CertificatesNative(), newFingerprint)
+		t.Require().NotNil(remotes.RemoteByCertificateFingerprint(oldFingerprint))
+		t.Equal("n1", remotes.RemoteByCertificateFingerprint(oldFingerprint).Name)
+	}
+
+	// The overlap is kept in the truststore directory.
+	reloaded := &trust.Remotes{}
+	t.Require().NoError(reloaded.Load(dir))
+	t.Contains(reloaded.CertificatesNative(), oldFingerprint)
+
+	t.Require().NoError(remotes.ForgetPreviousCertificate(dir, "n1"))
+	t.NotContains(remotes.CertificatesNative(), oldFingerprint)
+	t.Contains(remotes.CertificatesNative(), newFingerprint)
+	t.Nil(remotes.RemoteByCertificateFingerprint(oldFingerprint))
+
+	t.Require().NoError(reloaded.Load(dir))
+	t.NotContains(reloaded.CertificatesNative(), oldFingerprint)
+
+	// The previous certificate is no longer trusted once it expires, even if the remote never authenticates with the new one.
+	for _, trusted := range []bool{true, false} {
+		expiry := time.Now().Add(-time.Minute)
+		if trusted {
+			expiry = time.Now().Add(time.Minute)
+		}
+
+		remote := trust.Remote{Location: trust.Location{Name: "n1", Address: addrPort}, Certificate: *newCert, PreviousCertificate: oldCert, PreviousCertificateExpiry: expiry}
+		content, err := yaml.Marshal(remote)
+		t.Require().NoError(err)
+		t.Require().NoError(os.WriteFile(filepath.Join(dir, "n1.yaml"), content, 0644))
+
+		t.Require().NoError(reloaded.Load(dir))
+		_, ok := reloaded.CertificatesNative()[oldFingerprint]
+		t.Equal(trusted, ok)
+		t.Equal(trusted, reloaded.RemoteByCertificateFingerprint(oldFingerprint) != nil)
+	}
+}
+
+func (t *certificateExpirySuite) Test_switchServerCertificate() {
+	oldCert, oldKey, err := shared.GenerateMemCert(false, shared.CertOptions{})
+	t.Require().NoError(err)
+
+	newCert, newKey, err := shared.GenerateMemCert(false, shared.CertOptions{})
+	t.Require().NoError(err)
+
+	caPEM, _, err := shared.GenerateMemCert(false, shared.CertOptions{})
+	t.Require().NoError(err)
+
+	name := string(types.ServerCertificateName)
+	readFile := func(dir string, file string) []byte {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		t.Require().NoError(err)
+
+		return content
+	}
+
+	// A staged keypair is left alone until the switch.
+	dir := t.T().TempDir()
+	t.Require().NoError(util.WriteCert(dir, name, oldCert, oldKey, caPEM))
+	t.Require().NoError(util.WriteCert(dir, sys.NextServerCertName, newCert, newKey, nil))
+	t.Require().NoError(sys.CompleteKeyPairSwitch(dir, name, sys.NextServerCertName))
+	t.Equal(oldCert, readFile(dir, name+".crt"))
+	t.Equal(caPEM, readFile(dir, name+".ca"))
+
+	// The CA of the replaced keypair is removed along with it.
+	t.Require().NoError(sys.SwitchKeyPair(dir, name, sys.NextServerCertName))
+	t.Equal(newCert, readFile(dir, name+".crt"))
+	t.Equal(newKey, readFile(dir, name+".key"))
+	t.NoFileExists(filepath.Join(dir, name+".ca"))
+	t.NoFileExists(filepath.Join(dir, sys.NextServerCertName+".crt"))
+
+	// A switch interrupted after the key was replaced is completed.
+	dir = t.T().TempDir()
+	t.Require().NoError(util.WriteCert(dir, name, oldCert, oldKey, nil))
+	t.Require().NoError(util.WriteCert(dir, sys.NextServerCertName, newCert, newKey, caPEM))
+	t.Require().NoError(os.Rename(filepath.Join(dir, sys.NextServerCertName+".key"), filepath.Join(dir, name+".key")))
+	t.Require().NoError(sys.CompleteKeyPairSwitch(dir, name, sys.NextServerCertName))
+	t.Equal(newCert, readFile(dir, name+".crt"))
+	t.Equal(newKey, readFile(dir, name+".key"))
+	t.Equal(caPEM, readFile(dir, name+".ca"))
+	t.NoFileExists(filepath.Join(dir, sys.NextServerCertName+".crt"))
+
+	// A switch interrupted before the CA was replaced is completed.
+	dir = t.T().TempDir()
+	t.Require().NoError(util.WriteCert(dir, name, newCert, newKey, nil))
+	t.Require().NoError(os.WriteFile(filepath.Join(dir, sys.NextServerCertName+".ca"), caPEM, 0644))
+	t.Require().NoError(sys.CompleteKeyPairSwitch(dir, name, sys.NextServerCertName))
+	t.Equal(caPEM, readFile(dir, name+".ca"))
+	t.NoFileExists(filepath.Join(dir, sys.NextServerCertName+".ca"))
+}
@@ -0,0 +1,634 @@
+package resources
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/internal/sys"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// defaultCertificateRotationGracePeriod is how long cluster members keep trusting the previous cluster certificate
+// once they all serve the new one, unless the rotation sets its own grace period.
+const defaultCertificateRotationGracePeriod = time.Hour
+
+// certificateRotationMemberTimeout is how long a cluster member has to apply a phase of a rotation of the cluster
+// certificate. Cluster members that don't respond in time are sent the phase again on a later attempt.
+const certificateRotationMemberTimeout = 10 * time.Second
+
+var certificateRotationCmd = rest.Endpoint{
+	Path: "cluster/certificates/cluster/rotation",
+
+	Get:    rest.EndpointAction{Handler: certificateRotationGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementClusterRead, Summary: "Get the status of the latest cluster certificate rotation", Response: types.CertificateRotation{}},
+	Post:   rest.EndpointAction{Handler: certificateRotationPost, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementClusterWrite, Summary: "Start a rotation of the cluster certificate", Request: types.CertificateRotationPost{}, Response: types.CertificateRotation{}},
+	Delete: rest.EndpointAction{Handler: certificateRotationDelete, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementClusterWrite, Summary: "Abort the latest cluster certificate rotation", Response: types.CertificateRotation{}},
+}
+
+var certificateRotationInternalCmd = rest.Endpoint{
+	Path: "certificates/rotation",
+
+	Put: rest.EndpointAction{Handler: certificateRotationInternalPut, AccessHandler: access.AllowClusterMembers, Summary: "Apply a phase of a cluster certificate rotation on the cluster member", Request: internalTypes.CertificateRotationPut{}},
+}
+
+// certificateRotationMu is held while the local cluster member drives a rotation of the cluster certificate forward.
+var certificateRotationMu sync.Mutex
+
+// certificateRotationApplyMu is held while a phase of a rotation is applied to the local cluster member.
+var certificateRotationApplyMu sync.Mutex
+
+// certificateRotationGet returns the status of the latest rotation of the cluster certificate.
+func certificateRotationGet(s state.State, r *http.Request) response.Response {
+	rotation, err := certificateRotationStatus(r.Context(), s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, rotation)
+}
+
+// certificateRotationPost starts a rotation of the cluster certificate, and starts distributing the new certificate to
+// the cluster members in the background. The rotation is then driven forward by the dqlite leader with each heartbeat.
+func certificateRotationPost(s state.State, r *http.Request) response.Response {
+	req := types.CertificateRotationPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	_, err = tls.X509KeyPair([]byte(req.Cert), []byte(req.Key))
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid cluster keypair: %w", err))
+	}
+
+	cert, err := types.ParseX509Certificate(req.Cert)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid cluster certificate: %w", err))
+	}
+
+	if req.GracePeriod < 0 {
+		return response.BadRequest(fmt.Errorf("Grace period cannot be negative"))
+	}
+
+	if req.GracePeriod == 0 {
+		req.GracePeriod = defaultCertificateRotationGracePeriod
+	}
+
+	currentCert, err := s.ClusterCert().PublicKeyX509()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	fingerprint := shared.CertFingerprint(cert.Certificate)
+	previousFingerprint := shared.CertFingerprint(currentCert)
+	if fingerprint == previousFingerprint {
+		return response.BadRequest(fmt.Errorf("Certificate is already the cluster certificate"))
+	}
+
+	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		return cluster.StartCoreCertificateRotation(ctx, tx, cluster.CoreCertificateRotation{
+			Fingerprint:         fingerprint,
+			PreviousFingerprint: previousFingerprint,
+			Certificate:         req.Cert,
+			Key:                 req.Key,
+			Phase:               types.CertificateRotationPrepare,
+			GracePeriod:         req.GracePeriod,
+			StartedAt:           time.Now().UTC(),
+		})
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = startCertificateRotationAdvance(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	rotation, err := certificateRotationStatus(r.Context(), s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, rotation)
+}
+
+// certificateRotationDelete aborts the latest rotation of the cluster certificate, as long as cluster members have not
+// started to retire the previous certificate. Cluster members go back to serving the previous certificate, and then
+// stop trusting the new one, as the rotation is driven forward in the background.
+func certificateRotationDelete(s state.State, r *http.Request) response.Response {
+	err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		rotation, err := cluster.GetCoreCertificateRotation(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		if rotation.Phase != types.CertificateRotationPrepare && rotation.Phase != types.CertificateRotationSwitch {
+			return api.StatusErrorf(http.StatusConflict, "Cannot abort a cluster certificate rotation in phase %q", rotation.Phase)
+		}
+
+		// Cluster members that serve the new certificate go back to their own copy of the previous keypair.
+		rotation.Phase = types.CertificateRotationRevert
+		rotation.Key = ""
+
+		return cluster.UpdateCoreCertificateRotation(ctx, tx, rotation.Fingerprint, *rotation)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = startCertificateRotationAdvance(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	rotation, err := certificateRotationStatus(r.Context(), s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, rotation)
+}
+
+// certificateRotationInternalPut applies a phase of a rotation of the cluster certificate to the local cluster member.
+func certificateRotationInternalPut(s state.State, r *http.Request) response.Response {
+	req := internalTypes.CertificateRotationPut{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = applyCertificateRotation(s, req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// certificateRotationStatus returns the status of the latest rotation of the cluster certificate, with the phase
+// acknowledged by each cluster member.
+func certificateRotationStatus(ctx context.Context, s state.State) (*types.CertificateRotation, error) {
+	var rotation types.CertificateRotation
+	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		dbRotation, err := cluster.GetCoreCertificateRotation(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		acks, err := cluster.GetCoreCertificateRotationAcks(ctx, tx, dbRotation.ID)
+		if err != nil {
+			return err
+		}
+
+		members, err := cluster.GetCoreClusterMembers(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(members))
+		for _, member := range members {
+			names = append(names, member.Name)
+		}
+
+		slices.Sort(names)
+		rotation = dbRotation.ToAPI(names, acks)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rotation, nil
+}
+
+// certificateRotationInProgress returns whether a rotation of the cluster certificate has been started and is not
+// complete yet.
+func certificateRotationInProgress(ctx context.Context, s state.State) (bool, error) {
+	var rotation *cluster.CoreCertificateRotation
+	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		rotation, err = cluster.GetCoreCertificateRotation(ctx, tx)
+
+		return err
+	})
+	if api.StatusErrorCheck(err, http.StatusNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return !rotation.Phase.Done(), nil
+}
+
+// startCertificateRotationAdvance drives the latest rotation of the cluster certificate forward in the background, so
+// that cluster members that are slow to respond don't hold up the caller.
+func startCertificateRotationAdvance(s state.State) error {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		err := advanceCertificateRotation(intState.Context, s)
+		if err != nil {
+			logger.Error("Failed to advance cluster certificate rotation", logger.Ctx{"error": err})
+		}
+	}()
+
+	return nil
+}
+
+// advanceCertificateRotation drives the latest rotation of the cluster certificate forward. The current phase is
+// applied to each cluster member that has not acknowledged it yet, and once all of them have, the rotation moves on
+// to the next phase. Cluster members that cannot be reached hold the rotation back until a later attempt.
+// - Prepare: cluster members trust the new certificate alongside the current one.
+// - Switch: cluster members serve the new certificate, and keep trusting the previous one. The private key of the new
+// certificate is removed from the database once every cluster member serves it.
+// - Retire: once the grace period has passed since every cluster member served the new certificate, cluster members
+// stop trusting the previous one.
+// An aborted rotation goes through its own phases instead:
+// - Revert: cluster members serve the previous certificate again, and keep trusting the new one.
+// - Discard: cluster members stop trusting the new certificate.
+func advanceCertificateRotation(ctx context.Context, s state.State) error {
+	if !certificateRotationMu.TryLock() {
+		return nil
+	}
+
+	defer certificateRotationMu.Unlock()
+
+	for {
+		var rotation *cluster.CoreCertificateRotation
+		var acks map[string]types.CertificateRotationPhase
+		var members []cluster.CoreClusterMember
+		err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			var err error
+			rotation, err = cluster.GetCoreCertificateRotation(ctx, tx)
+			if err != nil {
+				return err
+			}
+
+			acks, err = cluster.GetCoreCertificateRotationAcks(ctx, tx, rotation.ID)
+			if err != nil {
+				return err
+			}
+
+			members, err = cluster.GetCoreClusterMembers(ctx, tx)
+
+			return err
+		})
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if rotation.Phase.Done() {
+			return nil
+		}
+
+		pending := []cluster.CoreClusterMember{}
+		for _, member := range members {
+			if member.Role != cluster.Pending && acks[member.Name] != rotation.Phase {
+				pending = append(pending, member)
+			}
+		}
+
+		acked := applyCertificateRotationOnMembers(ctx, s, pending, internalTypes.CertificateRotationPut{Phase: rotation.Phase, Cert: rotation.Certificate, Key: rotation.Key})
+
+		next := *rotation
+		now := time.Now().UTC()
+		if len(acked) == len(pending) {
+			switch rotation.Phase {
+			case types.CertificateRotationPrepare:
+				next.Phase = types.CertificateRotationSwitch
+			case types.CertificateRotationSwitch:
+				// The private key is no longer needed once every cluster member serves the new certificate.
+				if !next.SwitchedAt.Valid {
+					next.SwitchedAt = sql.NullTime{Time: now, Valid: true}
+					next.Key = ""
+				}
+
+				if now.Sub(next.SwitchedAt.Time) >= next.GracePeriod {
+					next.Phase = types.CertificateRotationRetire
+				}
+
+			case types.CertificateRotationRetire:
+				next.Phase = types.CertificateRotationComplete
+				next.CompletedAt = sql.NullTime{Time: now, Valid: true}
+			case types.CertificateRotationRevert:
+				next.Phase = types.CertificateRotationDiscard
+			case types.CertificateRotationDiscard:
+				next.Phase = types.CertificateRotationAborted
+				next.CompletedAt = sql.NullTime{Time: now, Valid: true}
+			}
+		}
+
+		changed := next.Phase != rotation.Phase || next.SwitchedAt != rotation.SwitchedAt
+		superseded := false
+		err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			// The rotation may have been aborted, or replaced, while the phase was being applied.
+			current, err := cluster.GetCoreCertificateRotation(ctx, tx)
+			if err != nil {
+				return err
+			}
+
+			if current.ID != rotation.ID || current.Phase != rotation.Phase {
+				superseded = true
+				return nil
+			}
+
+			for _, name := range acked {
+				_, err := cluster.CreateOrReplaceCoreCertificateRotationMember(ctx, tx, cluster.CoreCertificateRotationMember{RotationID: rotation.ID, Member: name, Phase: rotation.Phase})
+				if err != nil {
+					return err
+				}
+			}
+
+			if !changed {
+				return nil
+			}
+
+			return cluster.UpdateCoreCertificateRotation(ctx, tx, next.Fingerprint, next)
+		})
+		if err != nil {
+			return err
+		}
+
+		if superseded {
+			continue
+		}
+
+		// Cluster members have switched to the new certificate, or back to the previous one.
+		if (!rotation.SwitchedAt.Valid && next.SwitchedAt.Valid) || (rotation.Phase == types.CertificateRotationRevert && next.Phase != rotation.Phase) {
+			err = s.SendEvent(types.EventCertificateUpdated, types.EventCertificate{Name: types.ClusterCertificateName})
+			if err != nil {
+				return err
+			}
+		}
+
+		if next.Phase == rotation.Phase {
+			return nil
+		}
+
+		logger.Info("Cluster certificate rotation advanced", logger.Ctx{"fingerprint": rotation.Fingerprint, "phase": next.Phase})
+	}
+}
+
+// applyCertificateRotationOnMembers concurrently applies a phase of a rotation of the cluster certificate to the given
+// cluster members, and returns the names of those that acknowledged it.
+func applyCertificateRotationOnMembers(ctx context.Context, s state.State, members []cluster.CoreClusterMember, req internalTypes.CertificateRotationPut) []string {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	acked := make([]string, 0, len(members))
+	for _, member := range members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			memberCtx, cancel := context.WithTimeout(ctx, certificateRotationMemberTimeout)
+			defer cancel()
+
+			err := applyCertificateRotationOnMember(memberCtx, s, member, req)
+			if err != nil {
+				logger.Warn("Failed to apply cluster certificate rotation phase", logger.Ctx{"member": member.Name, "phase": req.Phase, "error": err})
+				return
+			}
+
+			mu.Lock()
+			acked = append(acked, member.Name)
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return acked
+}
+
+// applyCertificateRotationOnMember applies a phase of a rotation of the cluster certificate to the given cluster member.
+func applyCertificateRotationOnMember(ctx context.Context, s state.State, member cluster.CoreClusterMember, req internalTypes.CertificateRotationPut) error {
+	if member.Name == s.Name() {
+		return applyCertificateRotation(s, req)
+	}
+
+	publicKey, err := s.ClusterCert().PublicKeyX509()
+	if err != nil {
+		return err
+	}
+
+	url := api.NewURL().Scheme("https").Host(member.Address)
+	c, err := internalClient.New(*url, s.ServerCert(), publicKey, false, s.AlternateClusterCerts()...)
+	if err != nil {
+		return err
+	}
+
+	return internalClient.UpdateCertificateRotation(ctx, c, req)
+}
+
+// applyCertificateRotation applies a phase of a rotation of the cluster certificate to the local cluster member,
+// along with the phases before it, so that cluster members that missed a phase catch up.
+// - Prepare: the new keypair is written to the state directory as the next cluster certificate.
+// - Switch: the cluster certificate is kept as the previous one, and the next one replaces it.
+// - Retire: the previous cluster certificate is removed.
+// - Revert: the previous cluster certificate replaces the new one again, which is only kept to be trusted.
+// - Discard: the new cluster certificate is removed.
+// Applying a phase again has no effect.
+func applyCertificateRotation(s state.State, req internalTypes.CertificateRotationPut) error {
+	if !slices.Contains([]types.CertificateRotationPhase{types.CertificateRotationPrepare, types.CertificateRotationSwitch, types.CertificateRotationRetire, types.CertificateRotationRevert, types.CertificateRotationDiscard}, req.Phase) {
+		return api.StatusErrorf(http.StatusBadRequest, "Invalid cluster certificate rotation phase %q", req.Phase)
+	}
+
+	cert, err := types.ParseX509Certificate(req.Cert)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "Invalid cluster certificate: %v", err)
+	}
+
+	// The private key is no longer sent once every cluster member serves the new certificate.
+	if req.Key != "" {
+		_, err = tls.X509KeyPair([]byte(req.Cert), []byte(req.Key))
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid cluster keypair: %v", err)
+		}
+	}
+
+	certificateRotationApplyMu.Lock()
+	defer certificateRotationApplyMu.Unlock()
+
+	dir := s.FileSystem().StateDir
+	if req.Phase == types.CertificateRotationRevert {
+		err = revertClusterCertificate(dir, cert, []byte(req.Cert))
+		if err != nil {
+			return err
+		}
+	} else if req.Phase != types.CertificateRotationDiscard && !clusterCertificateSwitched(dir, cert) {
+		if req.Phase == types.CertificateRotationRetire {
+			return api.StatusErrorf(http.StatusConflict, "Cluster member does not serve the new cluster certificate")
+		}
+
+		if req.Key == "" {
+			return api.StatusErrorf(http.StatusBadRequest, "Missing private key of the new cluster certificate")
+		}
+
+		if req.Phase == types.CertificateRotationPrepare {
+			err = util.WriteCert(dir, sys.NextClusterCertName, []byte(req.Cert), []byte(req.Key), nil)
+			if err != nil {
+				return fmt.Errorf("Failed to write next cluster certificate: %w", err)
+			}
+		} else {
+			err = switchClusterCertificate(dir, []byte(req.Cert), []byte(req.Key))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if req.Phase == types.CertificateRotationRetire || req.Phase == types.CertificateRotationDiscard {
+		for _, name := range []string{sys.PreviousClusterCertName, sys.NextClusterCertName} {
+			for _, ext := range []string{"crt", "key"} {
+				err = os.Remove(filepath.Join(dir, fmt.Sprintf("%s.%s", name, ext)))
+				if err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return fmt.Errorf("Failed to remove %s.%s: %w", name, ext, err)
+				}
+			}
+		}
+	}
+
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return err
+	}
+
+	return intState.ReloadCert(types.ClusterCertificateName)
+}
+
+// clusterCertificateSwitched returns whether the cluster keypair in the given state directory is the given certificate
+// along with its private key.
+func clusterCertificateSwitched(dir string, cert *types.X509Certificate) bool {
+	name := string(types.ClusterCertificateName)
+	keypair, err := tls.LoadX509KeyPair(filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key"))
+	if err != nil {
+		return false
+	}
+
+	leaf, err := x509.ParseCertificate(keypair.Certificate[0])
+	if err != nil {
+		return false
+	}
+
+	return shared.CertFingerprint(leaf) == shared.CertFingerprint(cert.Certificate)
+}
+
+// switchClusterCertificate replaces the cluster keypair in the given state directory with the given one, and keeps
+// the replaced keypair as the previous cluster certificate. Each file is replaced in a single rename, so a switch that
+// is interrupted leaves a mismatched cluster keypair behind, which is completed when the cluster certificate is next
+// loaded, or replaced again by the next attempt.
+func switchClusterCertificate(dir string, cert []byte, key []byte) error {
+	certPath := func(name string, ext string) string {
+		return filepath.Join(dir, fmt.Sprintf("%s.%s", name, ext))
+	}
+
+	name := string(types.ClusterCertificateName)
+
+	// A mismatched cluster keypair means an earlier switch was interrupted after the previous keypair was kept.
+	_, err := tls.LoadX509KeyPair(certPath(name, "crt"), certPath(name, "key"))
+	if err == nil {
+		currentCert, err := os.ReadFile(certPath(name, "crt"))
+		if err != nil {
+			return fmt.Errorf("Failed to read cluster certificate: %w", err)
+		}
+
+		currentKey, err := os.ReadFile(certPath(name, "key"))
+		if err != nil {
+			return fmt.Errorf("Failed to read cluster key: %w", err)
+		}
+
+		err = util.WriteCert(dir, sys.PreviousClusterCertName, currentCert, currentKey, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to keep previous cluster certificate: %w", err)
+		}
+	}
+
+	err = util.WriteCert(dir, sys.NextClusterCertName, cert, key, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to write next cluster certificate: %w", err)
+	}
+
+	err = sys.SwitchKeyPair(dir, name, sys.NextClusterCertName)
+	if err != nil {
+		return fmt.Errorf("Failed to switch to next cluster certificate: %w", err)
+	}
+
+	return nil
+}
+
+// revertClusterCertificate replaces the given new cluster certificate in the given state directory with the previous
+// cluster keypair, if it was switched to. The next keypair is removed first, so that a revert that is interrupted is
+// completed towards the previous keypair when the cluster certificate is next loaded, or by the next attempt. The new
+// certificate is then written as the next one without its key, so that it is still trusted.
+func revertClusterCertificate(dir string, cert *types.X509Certificate, certPEM []byte) error {
+	certPath := func(name string, ext string) string {
+		return filepath.Join(dir, fmt.Sprintf("%s.%s", name, ext))
+	}
+
+	name := string(types.ClusterCertificateName)
+	for _, ext := range []string{"crt", "key"} {
+		err := os.Remove(certPath(sys.NextClusterCertName, ext))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("Failed to remove %s.%s: %w", sys.NextClusterCertName, ext, err)
+		}
+	}
+
+	if clusterCertificateSwitched(dir, cert) {
+		_, err := tls.LoadX509KeyPair(certPath(sys.PreviousClusterCertName, "crt"), certPath(sys.PreviousClusterCertName, "key"))
+		if err != nil {
+			return api.StatusErrorf(http.StatusConflict, "Cluster member has no previous cluster certificate to revert to: %v", err)
+		}
+
+		err = sys.SwitchKeyPair(dir, name, sys.PreviousClusterCertName)
+		if err != nil {
+			return fmt.Errorf("Failed to switch back to previous cluster certificate: %w", err)
+		}
+	} else {
+		err := sys.CompleteKeyPairSwitch(dir, name, sys.PreviousClusterCertName)
+		if err != nil {
+			return fmt.Errorf("Failed to switch back to previous cluster certificate: %w", err)
+		}
+	}
+
+	err := os.WriteFile(certPath(sys.NextClusterCertName, "crt"), certPEM, 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to write next cluster certificate: %w", err)
+	}
+
+	return nil
+}
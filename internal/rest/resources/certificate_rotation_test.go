@@ -0,0 +1,268 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/sys"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type certificateRotationSuite struct {
+	suite.Suite
+}
+
+func TestCertificateRotationSuite(t *testing.T) {
+	suite.Run(t, new(certificateRotationSuite))
+}
+
+func (t *certificateRotationSuite) Test_rotationRecords() {
+	db, err := newTestDB()
+	t.Require().NoError(err)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	t.Require().NoError(err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = cluster.GetCoreCertificateRotation(ctx, tx)
+	t.True(api.StatusErrorCheck(err, http.StatusNotFound))
+
+	rotation := cluster.CoreCertificateRotation{
+		Fingerprint:         "new",
+		PreviousFingerprint: "old",
+		Certificate:         "cert",
+		Key:                 "key",
+		Phase:               types.CertificateRotationPrepare,
+		GracePeriod:         time.Minute,
+		StartedAt:           time.Now().UTC(),
+	}
+
+	t.Require().NoError(cluster.StartCoreCertificateRotation(ctx, tx, rotation))
+
+	// Only one rotation can be in progress at a time.
+	err = cluster.StartCoreCertificateRotation(ctx, tx, rotation)
+	t.True(api.StatusErrorCheck(err, http.StatusConflict))
+
+	current, err := cluster.GetCoreCertificateRotation(ctx, tx)
+	t.Require().NoError(err)
+	t.Equal(rotation.Fingerprint, current.Fingerprint)
+	t.Equal(rotation.GracePeriod, current.GracePeriod)
+	t.Equal(types.CertificateRotationPrepare, current.Phase)
+	t.False(current.SwitchedAt.Valid)
+
+	_, err = cluster.CreateOrReplaceCoreCertificateRotationMember(ctx, tx, cluster.CoreCertificateRotationMember{RotationID: current.ID, Member: "member1", Phase: types.CertificateRotationPrepare})
+	t.Require().NoError(err)
+	_, err = cluster.CreateOrReplaceCoreCertificateRotationMember(ctx, tx, cluster.CoreCertificateRotationMember{RotationID: current.ID, Member: "member1", Phase: types.CertificateRotationSwitch})
+	t.Require().NoError(err)
+
+	acks, err := cluster.GetCoreCertificateRotationAcks(ctx, tx, current.ID)
+	t.Require().NoError(err)
+	t.Equal(map[string]types.CertificateRotationPhase{"member1": types.CertificateRotationSwitch}, acks)
+
+	status := current.ToAPI([]string{"member1", "member2"}, acks)
+	t.Equal([]types.CertificateRotationMember{{Name: "member1", Phase: types.CertificateRotationSwitch}, {Name: "member2"}}, status.Members)
+
+	// Once complete, the rotation is replaced by the next one along with the acknowledgements.
+	current.Phase = types.CertificateRotationComplete
+	current.Key = ""
+	current.SwitchedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	current.CompletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	t.Require().NoError(cluster.UpdateCoreCertificateRotation(ctx, tx, current.Fingerprint, *current))
+
+	updated, err := cluster.GetCoreCertificateRotation(ctx, tx)
+	t.Require().NoError(err)
+	t.Equal(types.CertificateRotationComplete, updated.Phase)
+	t.Empty(updated.Key)
+	t.True(updated.CompletedAt.Valid)
+
+	rotation.Fingerprint = "newer"
+	rotation.PreviousFingerprint = "new"
+	t.Require().NoError(cluster.StartCoreCertificateRotation(ctx, tx, rotation))
+
+	current, err = cluster.GetCoreCertificateRotation(ctx, tx)
+	t.Require().NoError(err)
+	t.Equal("newer", current.Fingerprint)
+
+	acks, err = cluster.GetCoreCertificateRotationAcks(ctx, tx, current.ID)
+	t.Require().NoError(err)
+	t.Empty(acks)
+}
+
+func (t *certificateRotationSuite) Test_switchClusterCertificate() {
+	oldCert, oldKey, err := shared.GenerateMemCert(false, shared.CertOptions{})
+	t.Require().NoError(err)
+
+	newCert, newKey, err := shared.GenerateMemCert(false, shared.CertOptions{})
+	t.Require().NoError(err)
+
+	cert, err := types.ParseX509Certificate(string(newCert))
+	t.Require().NoError(err)
+
+	name := string(types.ClusterCertificateName)
+	tests := []struct {
+		name string
+		// interrupt simulates a switch that stopped after the previous keypair was kept.
+		interrupt func(dir string)
+	}{
+		{
+			name:      "Clean switch",
+			interrupt: func(dir string) {},
+		},
+		{
+			name: "Interrupted after replacing the key",
+			interrupt: func(dir string) {
+				t.Require().NoError(util.WriteCert(dir, sys.PreviousClusterCertName, oldCert, oldKey, nil))
+				t.Require().NoError(os.WriteFile(filepath.Join(dir, name+".key"), newKey, 0600))
+			},
+		},
+		{
+			name: "Interrupted after replacing the certificate",
+			interrupt: func(dir string) {
+				t.Require().NoError(util.WriteCert(dir, sys.PreviousClusterCertName, oldCert, oldKey, nil))
+				t.Require().NoError(os.WriteFile(filepath.Join(dir, name+".crt"), newCert, 0644))
+			},
+		},
+	}
+
+	for i, c := range tests {
+		t.T().Logf("%s (case %d)", c.name, i)
+
+		dir := t.T().TempDir()
+		t.Require().NoError(util.WriteCert(dir, name, oldCert, oldKey, nil))
+		c.interrupt(dir)
+
+		t.False(clusterCertificateSwitched(dir, cert))
+		t.Require().NoError(switchClusterCertificate(dir, newCert, newKey))
+		t.True(clusterCertificateSwitched(dir, cert))
+
+		// The previous keypair is the one that was replaced, and the next keypair was moved into place.
+		previousCert, err := os.ReadFile(filepath.Join(dir, sys.PreviousClusterCertName+".crt"))
+		t.Require().NoError(err)
+		t.Equal(oldCert, previousCert)
+
+		previousKey, err := os.ReadFile(filepath.Join(dir, sys.PreviousClusterCertName+".key"))
+		t.Require().NoError(err)
+		t.Equal(oldKey, previousKey)
+
+		t.NoFileExists(filepath.Join(dir, sys.NextClusterCertName+".crt"))
+		t.NoFileExists(filepath.Join(dir, sys.NextClusterCertName+".key"))
+	}
+
+	// A switch interrupted after the key was moved into place is completed when the cluster certificate is loaded.
+	dir := t.T().TempDir()
+	t.Require().NoError(util.WriteCert(dir, name, oldCert, oldKey, nil))
+	t.Require().NoError(util.WriteCert(dir, sys.NextClusterCertName, newCert, newKey, nil))
+	t.Require().NoError(os.Rename(filepath.Join(dir, sys.NextClusterCertName+".key"), filepath.Join(dir, name+".key")))
+	t.False(clusterCertificateSwitched(dir, cert))
+
+	fs := &sys.OS{StateDir: dir}
+	clusterCert, err := fs.ClusterCert()
+	t.Require().NoError(err)
+	t.Equal(shared.CertFingerprint(cert.Certificate), clusterCert.Fingerprint())
+	t.True(clusterCertificateSwitched(dir, cert))
+}
+
+func (t *certificateRotationSuite) Test_revertClusterCertificate() {
+	oldCert, oldKey, err := shared.GenerateMemCert(false, shared.CertOptions{})
+	t.Require().NoError(err)
+
+	newCert, newKey, err := shared.GenerateMemCert(false, shared.CertOptions{})
+	t.Require().NoError(err)
+
+	cert, err := types.ParseX509Certificate(string(newCert))
+	t.Require().NoError(err)
+
+	previous, err := types.ParseX509Certificate(string(oldCert))
+	t.Require().NoError(err)
+
+	name := string(types.ClusterCertificateName)
+	tests := []struct {
+		name  string
+		setup func(dir string)
+	}{
+		{
+			name: "Aborted before the switch",
+			setup: func(dir string) {
+				t.Require().NoError(util.WriteCert(dir, sys.NextClusterCertName, newCert, newKey, nil))
+			},
+		},
+		{
+			name: "Aborted after the switch",
+			setup: func(dir string) {
+				t.Require().NoError(switchClusterCertificate(dir, newCert, newKey))
+			},
+		},
+		{
+			name: "Interrupted switch",
+			setup: func(dir string) {
+				t.Require().NoError(util.WriteCert(dir, sys.PreviousClusterCertName, oldCert, oldKey, nil))
+				t.Require().NoError(util.WriteCert(dir, sys.NextClusterCertName, newCert, newKey, nil))
+				t.Require().NoError(os.Rename(filepath.Join(dir, sys.NextClusterCertName+".key"), filepath.Join(dir, name+".key")))
+			},
+		},
+		{
+			name: "Interrupted revert",
+			setup: func(dir string) {
+				t.Require().NoError(switchClusterCertificate(dir, newCert, newKey))
+				t.Require().NoError(os.Rename(filepath.Join(dir, sys.PreviousClusterCertName+".key"), filepath.Join(dir, name+".key")))
+			},
+		},
+	}
+
+	for i, c := range tests {
+		t.T().Logf("%s (case %d)", c.name, i)
+
+		dir := t.T().TempDir()
+		t.Require().NoError(util.WriteCert(dir, name, oldCert, oldKey, nil))
+		c.setup(dir)
+
+		// Reverting again has no effect.
+		for range 2 {
+			t.Require().NoError(revertClusterCertificate(dir, cert, newCert))
+
+			currentCert, err := os.ReadFile(filepath.Join(dir, name+".crt"))
+			t.Require().NoError(err)
+			t.Equal(oldCert, currentCert)
+
+			currentKey, err := os.ReadFile(filepath.Join(dir, name+".key"))
+			t.Require().NoError(err)
+			t.Equal(oldKey, currentKey)
+
+			// The new certificate is still trusted, but its key is gone.
+			nextCert, err := os.ReadFile(filepath.Join(dir, sys.NextClusterCertName+".crt"))
+			t.Require().NoError(err)
+			t.Equal(newCert, nextCert)
+			t.NoFileExists(filepath.Join(dir, sys.NextClusterCertName+".key"))
+			t.NoFileExists(filepath.Join(dir, sys.PreviousClusterCertName+".key"))
+
+			fs := &sys.OS{StateDir: dir}
+			clusterCert, err := fs.ClusterCert()
+			t.Require().NoError(err)
+			t.Equal(shared.CertFingerprint(previous.Certificate), clusterCert.Fingerprint())
+		}
+	}
+
+	// A revert interrupted after the key was moved back into place is completed when the cluster certificate is loaded.
+	dir := t.T().TempDir()
+	t.Require().NoError(util.WriteCert(dir, name, oldCert, oldKey, nil))
+	t.Require().NoError(switchClusterCertificate(dir, newCert, newKey))
+	t.Require().NoError(os.Rename(filepath.Join(dir, sys.PreviousClusterCertName+".key"), filepath.Join(dir, name+".key")))
+
+	fs := &sys.OS{StateDir: dir}
+	clusterCert, err := fs.ClusterCert()
+	t.Require().NoError(err)
+	t.Equal(shared.CertFingerprint(previous.Certificate), clusterCert.Fingerprint())
+}
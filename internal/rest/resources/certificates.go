@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/gorilla/mux"
 
@@ -27,7 +28,7 @@ var clusterCertificatesCmd = rest.Endpoint{
 	AllowedBeforeInit: true,
 	Path:              "cluster/certificates/{name}",
 
-	Put: rest.EndpointAction{Handler: clusterCertificatesPut, AccessHandler: access.AllowAuthenticated},
+	Put: rest.EndpointAction{Handler: clusterCertificatesPut, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementClusterWrite, Summary: "Replace a certificate used by the cluster", Request: types.KeyPair{}},
 }
 
 func clusterCertificatesPut(s state.State, r *http.Request) response.Response {
@@ -49,9 +50,21 @@ func clusterCertificatesPut(s state.State, r *http.Request) response.Response {
 		logger.Warn(fmt.Sprintf("Database is offline, only updating local %q certificate", certificateName), logger.Ctx{"error": err})
 	}
 
+	// Replacing the cluster certificate would break an ongoing rotation.
+	if certificateName == string(types.ClusterCertificateName) && !client.IsNotification(r) && err == nil {
+		inProgress, err := certificateRotationInProgress(r.Context(), s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if inProgress {
+			return response.SmartError(api.StatusErrorf(http.StatusConflict, "Cannot replace the cluster certificate during a cluster certificate rotation"))
+		}
+	}
+
 	// Forward the request to all other nodes if we are the first.
 	if !client.IsNotification(r) && err == nil {
-		cluster, err := s.Cluster(true)
+		cluster, err := s.ClusterWithMaintenance(true)
 		if err != nil {
 			return response.SmartError(err)
 		}
@@ -135,5 +148,13 @@ func clusterCertificatesPut(s state.State, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Only the member that received the original request sends the event.
+	if !client.IsNotification(r) {
+		err = s.SendEvent(types.EventCertificateUpdated, types.EventCertificate{Name: types.CertificateName(certificateName)})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	return response.EmptySyncResponse
 }
@@ -0,0 +1,238 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+var clientCertificatesCmd = rest.Endpoint{
+	Path: "certificates",
+
+	// Untrusted clients may redeem a token, so the handler checks access to the other actions itself.
+	Post: rest.EndpointAction{Handler: clientCertificatesPost, AllowUntrusted: true, Summary: "Trust a client certificate, or issue or redeem a token to trust one", Request: types.ClientCertificatesPost{}, Response: ""},
+	Get:  rest.EndpointAction{Handler: clientCertificatesGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementCertificatesRead, Summary: "List the trusted client certificates", Response: []types.ClientCertificate{}},
+}
+
+var clientCertificateCmd = rest.Endpoint{
+	Path: "certificates/{name}",
+
+	Delete: rest.EndpointAction{Handler: clientCertificateDelete, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementCertificatesWrite, Summary: "Revoke a client certificate or token"},
+}
+
+// clientCertificatesPost trusts the certificate in the request, or issues a token for a client to redeem.
+// Untrusted clients can instead redeem a token, to have the certificate the request was sent with trusted.
+func clientCertificatesPost(s state.State, r *http.Request) response.Response {
+	req := types.ClientCertificatesPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.TrustToken != "" {
+		return clientCertificateRedeemToken(s, r, req.TrustToken)
+	}
+
+	trusted, resp := access.AllowAuthenticated(s, r)
+	if !trusted {
+		return resp
+	}
+
+	err = access.CheckPermission(s, r, types.EntitlementCertificatesWrite)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if req.Name == "" {
+		return response.BadRequest(fmt.Errorf("Client certificates and tokens must have a name"))
+	}
+
+	if req.Token {
+		return clientCertificateIssueToken(s, r, req)
+	}
+
+	cert, err := types.ParseX509Certificate(req.Certificate)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid client certificate: %w", err))
+	}
+
+	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		return cluster.TrustCoreClientCertificate(ctx, tx, cluster.CoreClientCertificate{
+			Name:        req.Name,
+			Fingerprint: shared.CertFingerprint(cert.Certificate),
+			Certificate: cert.String(),
+		})
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// clientCertificateIssueToken records a token that a client can redeem to have its certificate trusted under the
+// requested name, and returns it. Like join tokens, it holds the fingerprint of the cluster certificate and the
+// addresses of the cluster members, so that the client can verify and reach the cluster.
+func clientCertificateIssueToken(s state.State, r *http.Request, req types.ClientCertificatesPost) response.Response {
+	secret, err := shared.RandomCryptoString()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	clusterCert, err := s.ClusterCert().PublicKeyX509()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	addresses := []types.AddrPort{}
+	for _, addr := range s.Remotes().Addresses() {
+		addresses = append(addresses, addr)
+	}
+
+	expiryDate := sql.NullTime{Valid: req.ExpireAfter != 0}
+	if expiryDate.Valid {
+		expiryDate.Time = time.Now().Add(req.ExpireAfter)
+	}
+
+	token := internalTypes.Token{
+		Secret:        secret,
+		Fingerprint:   shared.CertFingerprint(clusterCert),
+		JoinAddresses: addresses,
+	}
+
+	tokenString, err := token.String()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		err := cluster.DeleteExpiredCoreClientTokens(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		return cluster.IssueCoreClientToken(ctx, tx, cluster.CoreClientToken{Name: req.Name, Secret: secret, ExpiryDate: expiryDate})
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, tokenString)
+}
+
+// clientCertificateRedeemToken trusts the certificate the request was sent with under the name of the given token,
+// which is then revoked.
+func clientCertificateRedeemToken(s state.State, r *http.Request, tokenString string) response.Response {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return response.BadRequest(fmt.Errorf("Redeeming a token requires a client certificate"))
+	}
+
+	token, err := internalTypes.DecodeToken(tokenString)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid token: %w", err))
+	}
+
+	cert := types.X509Certificate{Certificate: r.TLS.PeerCertificates[0]}
+	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		record, err := cluster.GetCoreClientTokenBySecret(ctx, tx, token.Secret)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				return api.StatusErrorf(http.StatusForbidden, "Invalid token")
+			}
+
+			return err
+		}
+
+		// Expired tokens are cleaned up when the next token is issued.
+		if record.Expired() {
+			return api.StatusErrorf(http.StatusForbidden, "Token has expired")
+		}
+
+		err = cluster.DeleteCoreClientToken(ctx, tx, record.Name)
+		if err != nil {
+			return err
+		}
+
+		return cluster.TrustCoreClientCertificate(ctx, tx, cluster.CoreClientCertificate{
+			Name:        record.Name,
+			Fingerprint: shared.CertFingerprint(cert.Certificate),
+			Certificate: cert.String(),
+		})
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// clientCertificatesGet returns the trusted client certificates.
+func clientCertificatesGet(s state.State, r *http.Request) response.Response {
+	var certs []types.ClientCertificate
+	err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		records, err := cluster.GetCoreClientCertificates(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		certs = make([]types.ClientCertificate, 0, len(records))
+		for _, record := range records {
+			cert, err := record.ToAPI()
+			if err != nil {
+				return err
+			}
+
+			certs = append(certs, *cert)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, certs)
+}
+
+// clientCertificateDelete revokes the client certificate, or else the pending token, with the given name.
+func clientCertificateDelete(s state.State, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		err := cluster.DeleteCoreClientCertificate(ctx, tx, name)
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		err = cluster.DeleteCoreClientToken(ctx, tx, name)
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return api.StatusErrorf(http.StatusNotFound, "No client certificate or token named %q", name)
+		}
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
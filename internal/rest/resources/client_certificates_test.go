@@ -0,0 +1,127 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/db/update"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type clientCertificatesSuite struct {
+	suite.Suite
+}
+
+func TestClientCertificatesSuite(t *testing.T) {
+	suite.Run(t, new(clientCertificatesSuite))
+}
+
+// newTestDB returns a sqlite DB set up with the default microcluster schema.
+func newTestDB() (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each connection to an in-memory database has its own data.
+	db.SetMaxOpenConns(1)
+
+	_, err = update.NewSchema().Schema().Ensure(db)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cluster.PrepareStmts(db, cluster.GetCallerProject(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (t *clientCertificatesSuite) Test_trustStore() {
+	db, err := newTestDB()
+	t.Require().NoError(err)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	t.Require().NoError(err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	certPEM, _, err := shared.GenerateMemCert(true, shared.CertOptions{})
+	t.Require().NoError(err)
+
+	cert, err := types.ParseX509Certificate(string(certPEM))
+	t.Require().NoError(err)
+
+	record := cluster.CoreClientCertificate{Name: "admin-tool", Fingerprint: shared.CertFingerprint(cert.Certificate), Certificate: cert.String()}
+	t.Require().NoError(cluster.TrustCoreClientCertificate(ctx, tx, record))
+
+	found, err := cluster.GetCoreClientCertificateByFingerprint(ctx, tx, record.Fingerprint)
+	t.Require().NoError(err)
+	t.Equal(record.Name, found.Name)
+
+	apiCert, err := found.ToAPI()
+	t.Require().NoError(err)
+	t.Equal(record.Fingerprint, apiCert.Fingerprint)
+
+	// Names are unique across certificates and tokens, and a certificate can only be trusted once.
+	err = cluster.IssueCoreClientToken(ctx, tx, cluster.CoreClientToken{Name: record.Name, Secret: "secret"})
+	t.True(api.StatusErrorCheck(err, http.StatusConflict))
+
+	record.Name = "other-tool"
+	err = cluster.TrustCoreClientCertificate(ctx, tx, record)
+	t.True(api.StatusErrorCheck(err, http.StatusConflict))
+
+	t.Require().NoError(cluster.DeleteCoreClientCertificate(ctx, tx, "admin-tool"))
+	_, err = cluster.GetCoreClientCertificateByFingerprint(ctx, tx, record.Fingerprint)
+	t.True(api.StatusErrorCheck(err, http.StatusNotFound))
+
+	err = cluster.DeleteCoreClientCertificate(ctx, tx, "admin-tool")
+	t.True(api.StatusErrorCheck(err, http.StatusNotFound))
+}
+
+func (t *clientCertificatesSuite) Test_tokens() {
+	db, err := newTestDB()
+	t.Require().NoError(err)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	t.Require().NoError(err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	expired := cluster.CoreClientToken{Name: "expired", Secret: "secret1", ExpiryDate: sql.NullTime{Valid: true, Time: time.Now().Add(-time.Minute)}}
+	valid := cluster.CoreClientToken{Name: "valid", Secret: "secret2", ExpiryDate: sql.NullTime{Valid: true, Time: time.Now().Add(time.Hour)}}
+	unlimited := cluster.CoreClientToken{Name: "unlimited", Secret: "secret3"}
+	for _, token := range []cluster.CoreClientToken{expired, valid, unlimited} {
+		t.Require().NoError(cluster.IssueCoreClientToken(ctx, tx, token))
+	}
+
+	found, err := cluster.GetCoreClientTokenBySecret(ctx, tx, expired.Secret)
+	t.Require().NoError(err)
+	t.True(found.Expired())
+
+	// Only expired tokens are cleaned up.
+	t.Require().NoError(cluster.DeleteExpiredCoreClientTokens(ctx, tx))
+
+	_, err = cluster.GetCoreClientTokenBySecret(ctx, tx, expired.Secret)
+	t.True(api.StatusErrorCheck(err, http.StatusNotFound))
+
+	for _, token := range []cluster.CoreClientToken{valid, unlimited} {
+		found, err := cluster.GetCoreClientTokenBySecret(ctx, tx, token.Secret)
+		t.Require().NoError(err)
+		t.Equal(token.Name, found.Name)
+		t.False(found.Expired())
+	}
+}
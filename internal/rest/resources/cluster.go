@@ -11,10 +11,13 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	dqlite "github.com/canonical/go-dqlite/app"
 	dqliteClient "github.com/canonical/go-dqlite/client"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/shared"
@@ -25,6 +28,8 @@ import (
 
 	"github.com/canonical/microcluster/v2/client"
 	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/db"
+	"github.com/canonical/microcluster/v2/internal/operations"
 	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
 	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
 	internalState "github.com/canonical/microcluster/v2/internal/state"
@@ -40,26 +45,31 @@ var clusterCmd = rest.Endpoint{
 	Path:              "cluster",
 	AllowedBeforeInit: true,
 
-	Get: rest.EndpointAction{Handler: clusterGet, AccessHandler: access.AllowAuthenticated},
+	Get: rest.EndpointAction{Handler: clusterGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementClusterRead, Summary: "List the cluster members", Response: []types.ClusterMember{}},
 }
 
 var clusterInternalCmd = rest.Endpoint{
 	Path:              "cluster",
 	AllowedBeforeInit: true,
 
-	Post: rest.EndpointAction{Handler: clusterPost, AllowUntrusted: true},
+	Post: rest.EndpointAction{Handler: clusterPost, AllowUntrusted: true, Summary: "Request to join the cluster", Request: types.ClusterMember{}, Response: internalTypes.TokenResponse{}},
 }
 
 var clusterMemberCmd = rest.Endpoint{
 	Path: "cluster/{name}",
 
-	Delete: rest.EndpointAction{Handler: clusterMemberDelete, AccessHandler: access.AllowAuthenticated},
+	Put:    rest.EndpointAction{Handler: clusterMemberUpdate, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementClusterWrite, Summary: "Update a cluster member", Request: types.ClusterMemberPut{}},
+	Patch:  rest.EndpointAction{Handler: clusterMemberPatch, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementClusterWrite, Summary: "Partially update a cluster member", Request: types.ClusterMemberPatch{}},
+	Delete: rest.EndpointAction{Handler: clusterMemberDelete, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementClusterWrite, Summary: "Remove a cluster member", Response: types.Operation{}},
 }
 
+// memberLabelRegex matches valid cluster member label keys and failure domains.
+var memberLabelRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._/-]*[a-zA-Z0-9])?$`)
+
 var clusterMemberInternalCmd = rest.Endpoint{
 	Path: "cluster/{name}",
 
-	Put: rest.EndpointAction{Handler: clusterMemberPut, AccessHandler: access.AllowAuthenticated},
+	Put: rest.EndpointAction{Handler: clusterMemberPut, AccessHandler: access.AllowClusterMembers, Summary: "Reset the cluster member after it has been removed from the cluster"},
 }
 
 func clusterPost(s state.State, r *http.Request) response.Response {
@@ -94,6 +104,11 @@ func clusterPost(s state.State, r *http.Request) response.Response {
 		return response.SmartError(fmt.Errorf("Invalid cluster member name %q: %w", req.Name, err))
 	}
 
+	err = validateMemberPut(types.ClusterMemberPut{Labels: req.Labels, FailureDomain: req.FailureDomain})
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	// Check if any of the remote's addresses are currently in use.
 	existingRemote := s.Remotes().RemoteByAddress(req.Address)
 	if existingRemote != nil {
@@ -136,6 +151,8 @@ func clusterPost(s state.State, r *http.Request) response.Response {
 			APIExtensions:  req.Extensions,
 			Heartbeat:      time.Time{},
 			Role:           cluster.Pending,
+			Labels:         req.Labels,
+			FailureDomain:  req.FailureDomain,
 		}
 
 		record, err := cluster.GetCoreTokenRecord(ctx, tx, req.Secret)
@@ -199,6 +216,11 @@ func clusterPost(s state.State, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	err = s.SendEvent(types.EventMemberJoined, types.EventMember{Name: req.Name, Address: req.Address.String(), Role: string(cluster.Pending)})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	tokenResponse.ClusterAdditionalCerts = make(map[string]types.KeyPair)
 
 	// Load the list of custom certificates from its state directory.
@@ -262,6 +284,7 @@ func clusterGet(s state.State, r *http.Request) response.Response {
 			return err
 		}
 
+		heartbeatData := s.HeartbeatData()
 		apiClusterMembers = make([]types.ClusterMember, 0, len(clusterMembers))
 		for _, clusterMember := range clusterMembers {
 			apiClusterMember, err := clusterMember.ToAPI()
@@ -269,6 +292,8 @@ func clusterGet(s state.State, r *http.Request) response.Response {
 				return err
 			}
 
+			apiClusterMember.HeartbeatData = heartbeatData[apiClusterMember.Name]
+
 			// Assign an upgrade status if the cluster member is awaiting an upgrade.
 			if awaitingUpgrade != nil {
 				if awaitingUpgrade[apiClusterMember.Name] {
@@ -287,16 +312,61 @@ func clusterGet(s state.State, r *http.Request) response.Response {
 		return response.SmartError(fmt.Errorf("Failed to get cluster members: %w", err))
 	}
 
-	// Send a small request to each node to ensure they are reachable if the database is fully online.
+	// Determine the status of each node from heartbeats if the database is fully online.
 	if status == types.DatabaseReady {
 		clusterCert, err := s.ClusterCert().PublicKeyX509()
 		if err != nil {
 			return response.SmartError(err)
 		}
 
+		err = setClusterLeader(r.Context(), s, apiClusterMembers)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		intState, err := internalState.ToInternal(s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Report the keypairs of this cluster member as they are now, rather than as of the last heartbeat.
+		memberCertificates := intState.InternalDatabase.MemberCertificates()
+		memberCertificates[s.Name()], err = localCertificateExpiries(s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		now := time.Now()
+		memberHealth := intState.FailureDetector.Snapshot()
 		for i, clusterMember := range apiClusterMembers {
+			apiClusterMembers[i].Certificates = memberCertificates[clusterMember.Name]
+
+			health, ok := memberHealth[clusterMember.Name]
+			if ok {
+				apiClusterMembers[i].LastSeen = health.LastSeen
+				apiClusterMembers[i].HeartbeatRTT = health.RTT
+				apiClusterMembers[i].MissedHeartbeats = health.MissedHeartbeats
+			}
+
+			// Members in maintenance keep their status whether or not they are reachable.
+			if clusterMember.Status == types.MemberMaintenance {
+				continue
+			}
+
+			// We are answering this request, so we are clearly online.
+			if clusterMember.Name == s.Name() {
+				apiClusterMembers[i].Status = types.MemberOnline
+				continue
+			}
+
+			if ok && !health.LastSeen.IsZero() {
+				apiClusterMembers[i].Status, apiClusterMembers[i].Suspicion = intState.FailureDetector.Status(health, now)
+				continue
+			}
+
+			// Until the cluster member has answered a heartbeat, send it a small request to check that it is reachable.
 			addr := api.NewURL().Scheme("https").Host(clusterMember.Address.String())
-			d, err := internalClient.New(*addr, s.ServerCert(), clusterCert, false)
+			d, err := internalClient.New(*addr, s.ServerCert(), clusterCert, false, s.AlternateClusterCerts()...)
 			if err != nil {
 				return response.SmartError(fmt.Errorf("Failed to create HTTPS client for cluster member with address %q: %w", addr.String(), err))
 			}
@@ -313,6 +383,462 @@ func clusterGet(s state.State, r *http.Request) response.Response {
 	return response.SyncResponse(true, apiClusterMembers)
 }
 
+// setClusterLeader marks the dqlite leader among the given cluster members, along with the time at which the local
+// cluster member learned of its election.
+func setClusterLeader(ctx context.Context, s state.State, clusterMembers []types.ClusterMember) error {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return err
+	}
+
+	leaderCtx, cancel := context.WithTimeout(ctx, time.Second*30)
+	defer cancel()
+
+	leader, err := s.Database().Leader(leaderCtx)
+	if err != nil {
+		return err
+	}
+
+	leaderInfo, err := leader.Leader(leaderCtx)
+	if err != nil {
+		return err
+	}
+
+	// The election time is only known once heartbeats have recorded the current leader.
+	recordedAddress, electedAt := intState.InternalDatabase.LeaderAddress()
+	for i, clusterMember := range clusterMembers {
+		if clusterMember.Address.String() != leaderInfo.Address {
+			continue
+		}
+
+		clusterMembers[i].Leader = true
+		if recordedAddress == leaderInfo.Address {
+			clusterMembers[i].LeaderElectedAt = &electedAt
+		}
+	}
+
+	return nil
+}
+
+// clusterMemberUpdate sets the labels and failure domain of a cluster member.
+// The request is forwarded to the cluster member itself, so that it can record its failure domain locally for the next
+// time its database starts.
+func clusterMemberUpdate(s state.State, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := types.ClusterMemberPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = validateMemberPut(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if name != s.Name() {
+		remote, ok := s.Remotes().RemotesByName()[name]
+		if !ok {
+			return response.NotFound(fmt.Errorf("No remote exists with the given name %q", name))
+		}
+
+		publicKey, err := s.ClusterCert().PublicKeyX509()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		url := api.NewURL().Scheme("https").Host(remote.Address.String())
+		c, err := internalClient.New(*url, s.ServerCert(), publicKey, false, s.AlternateClusterCerts()...)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = c.UpdateClusterMember(r.Context(), name, req)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed to update cluster member %q: %w", name, err))
+		}
+
+		return response.EmptySyncResponse
+	}
+
+	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		member, err := cluster.GetCoreClusterMember(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
+		member.Labels = req.Labels
+		member.FailureDomain = req.FailureDomain
+
+		return cluster.UpdateCoreClusterMember(ctx, tx, name, *member)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	localConfig := intState.LocalConfig()
+	if localConfig.GetFailureDomain() != req.FailureDomain {
+		localConfig.SetFailureDomain(req.FailureDomain)
+		err = localConfig.Write()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		logger.Info("Failure domain updated; it will apply the next time the database starts", logger.Ctx{"failureDomain": req.FailureDomain})
+	}
+
+	return response.EmptySyncResponse
+}
+
+// dqliteRolesConfig is the target number of voters and stand-bys that dqlite maintains when it adjusts roles. It
+// matches the go-dqlite defaults, which are not overridden.
+var dqliteRolesConfig = dqlite.RolesConfig{Voters: 3, StandBys: 3}
+
+// clusterMemberPatch assigns a dqlite role to a cluster member. The request is forwarded to the leader, which refuses
+// role changes that would leave the voters without a quorum of online cluster members.
+// Dqlite periodically adjusts roles to keep its target number of voters and stand-bys. Demoted cluster members are
+// given the pinned weight so that dqlite promotes other cluster members in their place, and role changes that the next
+// adjustment would still revert are refused rather than silently undone.
+func clusterMemberPatch(s state.State, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := types.ClusterMemberPatch{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	role, err := parseDqliteRole(req.Role)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	remote, ok := s.Remotes().RemotesByName()[name]
+	if !ok {
+		return response.NotFound(fmt.Errorf("No remote exists with the given name %q", name))
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), time.Second*30)
+	defer cancel()
+
+	leader, err := s.Database().Leader(ctx)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	leaderInfo, err := leader.Leader(ctx)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Forward the request to the leader.
+	if leaderInfo.Address != s.Address().URL.Host {
+		client, err := s.Leader()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = client.SetClusterMemberRole(ctx, name, req.Role)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.EmptySyncResponse
+	}
+
+	nodes, err := leader.Cluster(ctx)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var target *dqliteClient.NodeInfo
+	for i, node := range nodes {
+		if node.Address == remote.Address.String() {
+			target = &nodes[i]
+			break
+		}
+	}
+
+	if target == nil {
+		return response.SmartError(api.StatusErrorf(http.StatusConflict, "Cluster member %q is not a dqlite cluster member", name))
+	}
+
+	if target.Role != role {
+		online := onlineDqliteMembers(ctx, s, nodes)
+		err = checkRoleChangeQuorum(nodes, *target, role, online)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// The leader can't give up its vote, so hand leadership over to another online voter and let it assign the role.
+		if target.Address == leaderInfo.Address && role != dqliteClient.Voter {
+			otherVoters := []uint64{}
+			for _, node := range nodes {
+				if node.ID != target.ID && node.Role == dqliteClient.Voter && online[node.Address] {
+					otherVoters = append(otherVoters, node.ID)
+				}
+			}
+
+			if len(otherVoters) == 0 {
+				return response.SmartError(api.StatusErrorf(http.StatusConflict, "Found no online voters to transfer leadership to"))
+			}
+
+			err = leader.Transfer(ctx, otherVoters[rand.Intn(len(otherVoters))])
+			if err != nil {
+				return response.SmartError(fmt.Errorf("Failed to transfer leadership: %w", err))
+			}
+
+			client, err := s.Leader()
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			err = client.SetClusterMemberRole(ctx, name, req.Role)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			return response.EmptySyncResponse
+		}
+
+		var members []cluster.CoreClusterMember
+		err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			members, err = cluster.GetCoreClusterMembers(ctx, tx)
+
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		metadata := dqliteMetadata(members, online)
+		targetMetadata := metadata[target.Address]
+		if targetMetadata != nil {
+			// Dqlite roles are ordered from voter to spare, so a greater role is a demotion.
+			targetMetadata.Weight = targetMetadata.Weight &^ db.PinnedWeight
+			if role > target.Role {
+				targetMetadata.Weight |= db.PinnedWeight
+			}
+		}
+
+		if roleAssignmentReverted(nodes, *target, role, leaderInfo.ID, metadata) {
+			return response.SmartError(api.StatusErrorf(http.StatusConflict, "Dqlite would revert role %q of cluster member %q to keep %d voters and %d stand-bys", req.Role, name, dqliteRolesConfig.Voters, dqliteRolesConfig.StandBys))
+		}
+
+		// Offline cluster members are never promoted by dqlite, so their weight doesn't matter.
+		if targetMetadata != nil {
+			intState, err := internalState.ToInternal(s)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			err = intState.InternalDatabase.SetNodeWeight(ctx, target.Address, targetMetadata.Weight)
+			if err != nil {
+				return response.SmartError(err)
+			}
+		}
+
+		err = leader.Assign(ctx, target.ID, role)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed to assign role %q to cluster member %q: %w", req.Role, name, err))
+		}
+
+		logger.Info("Assigned dqlite role to cluster member", logger.Ctx{"name": name, "oldRole": target.Role.String(), "newRole": req.Role})
+	}
+
+	var roleChanged bool
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		member, err := cluster.GetCoreClusterMember(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
+		roleChanged = member.Role != cluster.Role(req.Role)
+		member.Role = cluster.Role(req.Role)
+
+		return cluster.UpdateCoreClusterMember(ctx, tx, name, *member)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if roleChanged {
+		err = s.SendEvent(types.EventRoleChanged, types.EventMember{Name: name, Address: remote.Address.String(), Role: req.Role})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	return response.EmptySyncResponse
+}
+
+// parseDqliteRole returns the dqlite role with the given name.
+func parseDqliteRole(name string) (dqliteClient.NodeRole, error) {
+	for _, role := range []dqliteClient.NodeRole{dqliteClient.Voter, dqliteClient.StandBy, dqliteClient.Spare} {
+		if role.String() == name {
+			return role, nil
+		}
+	}
+
+	return -1, fmt.Errorf("Invalid role %q: must be one of %q, %q or %q", name, dqliteClient.Voter, dqliteClient.StandBy, dqliteClient.Spare)
+}
+
+// onlineDqliteMembers returns the addresses of the dqlite cluster members whose daemon is reachable.
+func onlineDqliteMembers(ctx context.Context, s state.State, nodes []dqliteClient.NodeInfo) map[string]bool {
+	online := make(map[string]bool, len(nodes))
+	online[s.Address().URL.Host] = true
+
+	publicKey, err := s.ClusterCert().PublicKeyX509()
+	if err != nil {
+		logger.Warn("Failed to parse cluster certificate", logger.Ctx{"error": err})
+		return online
+	}
+
+	for _, node := range nodes {
+		if online[node.Address] {
+			continue
+		}
+
+		addr := api.NewURL().Scheme("https").Host(node.Address)
+		c, err := internalClient.New(*addr, s.ServerCert(), publicKey, false, s.AlternateClusterCerts()...)
+		if err != nil {
+			continue
+		}
+
+		online[node.Address] = c.CheckReady(ctx) == nil
+	}
+
+	return online
+}
+
+// checkRoleChangeQuorum returns a 409 error if assigning the role to the target would leave the dqlite voters without
+// a quorum of online cluster members, or if an offline cluster member would be promoted to voter.
+func checkRoleChangeQuorum(nodes []dqliteClient.NodeInfo, target dqliteClient.NodeInfo, role dqliteClient.NodeRole, online map[string]bool) error {
+	if role == dqliteClient.Voter && !online[target.Address] {
+		return api.StatusErrorf(http.StatusConflict, "Cannot promote offline cluster member with address %q to voter", target.Address)
+	}
+
+	voters := 0
+	onlineVoters := 0
+	for _, node := range nodes {
+		nodeRole := node.Role
+		if node.ID == target.ID {
+			nodeRole = role
+		}
+
+		if nodeRole != dqliteClient.Voter {
+			continue
+		}
+
+		voters++
+		if online[node.Address] {
+			onlineVoters++
+		}
+	}
+
+	if voters == 0 {
+		return api.StatusErrorf(http.StatusConflict, "At least one voter is required")
+	}
+
+	if onlineVoters <= voters/2 {
+		return api.StatusErrorf(http.StatusConflict, "Role change would leave %d of %d voters online, which is not a quorum", onlineVoters, voters)
+	}
+
+	return nil
+}
+
+// dqliteMetadata returns the dqlite metadata of the online cluster members by address, as dqlite sees it when adjusting
+// roles.
+func dqliteMetadata(members []cluster.CoreClusterMember, online map[string]bool) map[string]*dqliteClient.NodeMetadata {
+	metadata := make(map[string]*dqliteClient.NodeMetadata, len(members))
+	for _, member := range members {
+		if !online[member.Address] {
+			continue
+		}
+
+		weight := uint64(0)
+		if member.Maintenance {
+			weight = db.MaintenanceWeight
+		}
+
+		metadata[member.Address] = &dqliteClient.NodeMetadata{FailureDomain: db.FailureDomainCode(member.FailureDomain), Weight: weight}
+	}
+
+	return metadata
+}
+
+// roleAssignmentReverted returns whether the role adjustments that dqlite runs on the leader could change the role of
+// the target again after it is assigned the given role. Offline nodes have no metadata.
+// Dqlite picks between candidates with the same failure domain and weight at random, so the target counts as picked
+// if it ties with the first candidate.
+func roleAssignmentReverted(nodes []dqliteClient.NodeInfo, target dqliteClient.NodeInfo, role dqliteClient.NodeRole, leaderID uint64, metadata map[string]*dqliteClient.NodeMetadata) bool {
+	nodes = slices.Clone(nodes)
+	for i := range nodes {
+		if nodes[i].ID == target.ID {
+			nodes[i].Role = role
+		}
+	}
+
+	// Each adjustment changes the role of one node, so the roles settle within a few adjustments per node.
+	for range 2 * len(nodes) {
+		changes := dqlite.RolesChanges{Config: dqliteRolesConfig, State: make(map[dqliteClient.NodeInfo]*dqliteClient.NodeMetadata, len(nodes))}
+		for _, node := range nodes {
+			changes.State[node] = metadata[node.Address]
+		}
+
+		newRole, candidates := changes.Adjust(leaderID)
+		if newRole == -1 || len(candidates) == 0 {
+			return false
+		}
+
+		for _, candidate := range candidates {
+			if candidate.ID != target.ID {
+				continue
+			}
+
+			first, candidateMetadata := metadata[candidates[0].Address], metadata[candidate.Address]
+			if candidate.ID == candidates[0].ID || first == candidateMetadata || (first != nil && candidateMetadata != nil && *first == *candidateMetadata) {
+				return true
+			}
+		}
+
+		for i := range nodes {
+			if nodes[i].ID == candidates[0].ID {
+				nodes[i].Role = newRole
+			}
+		}
+	}
+
+	return false
+}
+
+// validateMemberPut checks that the labels and failure domain of a cluster member are well formed.
+func validateMemberPut(put types.ClusterMemberPut) error {
+	for key := range put.Labels {
+		if !memberLabelRegex.MatchString(key) {
+			return fmt.Errorf("Invalid label key %q", key)
+		}
+	}
+
+	if put.FailureDomain != "" && !memberLabelRegex.MatchString(put.FailureDomain) {
+		return fmt.Errorf("Invalid failure domain %q", put.FailureDomain)
+	}
+
+	return nil
+}
+
 // clusterDisableMu is used to prevent the daemon process from being replaced/stopped during removal from the
 // cluster until such time as the request that initiated the removal has finished. This allows for self removal
 // from the cluster when not the leader.
@@ -344,8 +870,10 @@ func clusterMemberPut(s state.State, r *http.Request) response.Response {
 	})
 }
 
-// resetClusterMember clears the daemon state, closing the database and stopping all listeners.
-// Returns a function that can be used to re-exec the daemon, forcibly reloading its state.
+// resetClusterMember closes the database and stops the network listeners.
+// Returns a function that can be used to re-exec the daemon, forcibly reloading its state. Once the given context is
+// done and no self removal is in progress, it clears the state directory, including the control socket, before
+// re-executing the daemon, so that local clients can collect the result of their request until then.
 func resetClusterMember(ctx context.Context, s state.State, force bool) (reExec func(), err error) {
 	intState, err := internalState.ToInternal(s)
 	if err != nil {
@@ -362,11 +890,7 @@ func resetClusterMember(ctx context.Context, s state.State, force bool) (reExec
 		return nil, fmt.Errorf("Failed shutting down listeners: %w", err)
 	}
 
-	err = os.RemoveAll(s.FileSystem().StateDir)
-	if err != nil && !force {
-		return nil, fmt.Errorf("Failed to remove the s directory: %w", err)
-	}
-
+	stateDir := s.FileSystem().StateDir
 	reExec = func() {
 		<-ctx.Done() // Wait until request has finished.
 
@@ -374,6 +898,12 @@ func resetClusterMember(ctx context.Context, s state.State, force bool) (reExec
 		// replace/stop the LXD daemon until that request has finished.
 		clusterDisableMu.Lock()
 		defer clusterDisableMu.Unlock()
+
+		err := os.RemoveAll(stateDir)
+		if err != nil {
+			logger.Error("Failed to remove the state directory", logger.Ctx{"path": stateDir, "error": err})
+		}
+
 		execPath, err := os.Readlink("/proc/self/exe")
 		if err != nil {
 			execPath = "bad-exec-path"
@@ -392,7 +922,7 @@ func resetClusterMember(ctx context.Context, s state.State, force bool) (reExec
 	return reExec, nil
 }
 
-// clusterMemberDelete Removes a cluster member from dqlite and re-execs its daemon.
+// clusterMemberDelete starts an operation that removes a cluster member from dqlite and re-execs its daemon.
 func clusterMemberDelete(s state.State, r *http.Request) response.Response {
 	force := r.URL.Query().Get("force") == "1"
 	name, err := url.PathUnescape(mux.Vars(r)["name"])
@@ -400,23 +930,56 @@ func clusterMemberDelete(s state.State, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	_, ok := s.Remotes().RemotesByName()[name]
+	if !ok {
+		return response.SmartError(fmt.Errorf("No remote exists with the given name %q", name))
+	}
+
+	op, err := s.Operations().Create(fmt.Sprintf("Removing cluster member %q", name), false, func(ctx context.Context, op *operations.Operation) error {
+		return removeClusterMember(ctx, op, s, name, force)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// lockSelfRemoval acquires the clusterDisableMu lock until the result of the operation has been collected,
+// so that the daemon is not re-executed before the client has learned of the outcome of its own removal.
+func lockSelfRemoval(op *operations.Operation, name string) {
+	clusterDisableMu.Lock()
+	logger.Info("Acquired cluster self removal lock", logger.Ctx{"member": name})
+
+	go func() {
+		<-op.DeliveredContext().Done() // Wait until the operation result is collected.
+
+		logger.Info("Releasing cluster self removal lock", logger.Ctx{"member": name})
+		clusterDisableMu.Unlock()
+	}()
+}
+
+// removeClusterMember removes the named cluster member. If we are not the leader, the removal is forwarded to the leader.
+func removeClusterMember(ctx context.Context, op *operations.Operation, s state.State, name string, force bool) error {
 	allRemotes := s.Remotes().RemotesByName()
 	remote, ok := allRemotes[name]
 	if !ok {
-		return response.SmartError(fmt.Errorf("No remote exists with the given name %q", name))
+		return fmt.Errorf("No remote exists with the given name %q", name)
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), time.Second*30)
+	op.UpdateMetadata(map[string]any{"name": name, "address": remote.Address.String(), "force": force})
+
+	leaderCtx, cancel := context.WithTimeout(ctx, time.Second*30)
 	defer cancel()
 
-	leader, err := s.Database().Leader(ctx)
+	leader, err := s.Database().Leader(leaderCtx)
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
-	leaderInfo, err := leader.Leader(ctx)
+	leaderInfo, err := leader.Leader(leaderCtx)
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
 	// If we are not the leader, just forward the request.
@@ -425,48 +988,23 @@ func clusterMemberDelete(s state.State, r *http.Request) response.Response {
 			// If the member being removed is ourselves and we are not the leader, then lock the
 			// clusterPutDisableMu before we forward the request to the leader, so that when the leader
 			// goes on to request clusterPutDisable back to ourselves it won't be actioned until we
-			// have returned this request back to the original client.
-			clusterDisableMu.Lock()
-			logger.Info("Acquired cluster self removal lock", logger.Ctx{"member": name})
-
-			go func() {
-				<-r.Context().Done() // Wait until request is finished.
-
-				logger.Info("Releasing cluster self removal lock", logger.Ctx{"member": name})
-				clusterDisableMu.Unlock()
-			}()
+			// have returned the result of this operation back to the original client.
+			lockSelfRemoval(op, name)
 		}
 
-		client, err := s.Leader()
-		if err != nil {
-			return response.SmartError(err)
-		}
+		op.SetProgress("Forwarding removal to the leader", 10)
 
-		err = client.DeleteClusterMember(r.Context(), name, force)
+		client, err := s.Leader()
 		if err != nil {
-			return response.SmartError(err)
+			return err
 		}
 
-		return response.ManualResponse(func(w http.ResponseWriter) error {
-			err := response.EmptySyncResponse.Render(w)
-			if err != nil {
-				return err
-			}
-
-			// Send the response before replacing the LXD daemon process.
-			f, ok := w.(http.Flusher)
-			if !ok {
-				return fmt.Errorf("ResponseWriter is not type http.Flusher")
-			}
-
-			f.Flush()
-			return nil
-		})
+		return client.DeleteClusterMember(ctx, name, force)
 	}
 
-	info, err := leader.Cluster(r.Context())
+	info, err := leader.Cluster(ctx)
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
 	index := -1
@@ -483,14 +1021,14 @@ func clusterMemberDelete(s state.State, r *http.Request) response.Response {
 	}
 
 	var clusterMembers []cluster.CoreClusterMember
-	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
 		var err error
 		clusterMembers, err = cluster.GetCoreClusterMembers(ctx, tx)
 
 		return err
 	})
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
 	numPending := 0
@@ -501,29 +1039,29 @@ func clusterMemberDelete(s state.State, r *http.Request) response.Response {
 	}
 
 	if len(clusterMembers)-numPending < 1 {
-		return response.SmartError(fmt.Errorf("Cannot remove cluster members, there are no remaining non-pending members"))
+		return fmt.Errorf("Cannot remove cluster members, there are no remaining non-pending members")
 	}
 
 	if len(info) < 2 {
-		return response.SmartError(fmt.Errorf("Cannot leave a cluster with %d members", len(info)))
+		return fmt.Errorf("Cannot leave a cluster with %d members", len(info))
 	}
 
 	// If we are removing the leader of a 2-node cluster, ensure the remaining node is a voter.
 	if len(info) == 2 && allRemotes[name].Address.String() == leaderInfo.Address {
 		for _, node := range info {
 			if node.Address != leaderInfo.Address && node.Role != dqliteClient.Voter {
-				err = leader.Assign(ctx, node.ID, dqliteClient.Voter)
+				err = leader.Assign(leaderCtx, node.ID, dqliteClient.Voter)
 				if err != nil {
-					return response.SmartError(err)
+					return err
 				}
 			}
 		}
 	}
 
 	// Refresh members information since we may have changed roles.
-	info, err = leader.Cluster(r.Context())
+	info, err = leader.Cluster(ctx)
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
 	// If we are the leader and removing ourselves, reassign the leader role and perform the removal from there.
@@ -536,126 +1074,116 @@ func clusterMemberDelete(s state.State, r *http.Request) response.Response {
 		}
 
 		if len(otherNodes) == 0 {
-			return response.SmartError(fmt.Errorf("Found no voters to transfer leadership to"))
+			return fmt.Errorf("Found no voters to transfer leadership to")
 		}
 
+		op.SetProgress("Transferring leadership", 10)
+
 		randomID := otherNodes[rand.Intn(len(otherNodes))]
-		err = leader.Transfer(ctx, randomID)
+		err = leader.Transfer(leaderCtx, randomID)
 		if err != nil {
-			return response.SmartError(err)
+			return err
 		}
 
 		client, err := s.Leader()
 		if err != nil {
-			return response.SmartError(err)
-		}
-
-		clusterDisableMu.Lock()
-		logger.Info("Acquired cluster self removal lock", logger.Ctx{"member": name})
-
-		go func() {
-			<-r.Context().Done() // Wait until request is finished.
-
-			logger.Info("Releasing cluster self removal lock", logger.Ctx{"member": name})
-			clusterDisableMu.Unlock()
-		}()
-
-		err = client.DeleteClusterMember(r.Context(), name, force)
-		if err != nil {
-			return response.SmartError(err)
+			return err
 		}
 
-		return response.ManualResponse(func(w http.ResponseWriter) error {
-			err := response.EmptySyncResponse.Render(w)
-			if err != nil {
-				return err
-			}
+		lockSelfRemoval(op, name)
 
-			// Send the response before replacing the LXD daemon process.
-			f, ok := w.(http.Flusher)
-			if !ok {
-				return fmt.Errorf("ResponseWriter is not type http.Flusher")
-			}
+		op.SetProgress("Forwarding removal to the new leader", 20)
 
-			f.Flush()
-			return nil
-		})
+		return client.DeleteClusterMember(ctx, name, force)
 	}
 
 	publicKey, err := s.ClusterCert().PublicKeyX509()
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
 	// Set the forwarded flag so that the the system to be removed knows the removal is in progress.
-	c, err := internalClient.New(remote.URL(), s.ServerCert(), publicKey, true)
+	c, err := internalClient.New(remote.URL(), s.ServerCert(), publicKey, true, s.AlternateClusterCerts()...)
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
+	op.SetProgress("Running pre-remove hook", 30)
+
 	// Tell the cluster member to run its PreRemove hook and return.
-	err = internalClient.RunPreRemoveHook(ctx, c.UseTarget(name), internalTypes.HookRemoveMemberOptions{Force: force})
+	err = internalClient.RunPreRemoveHook(leaderCtx, c.UseTarget(name), internalTypes.HookRemoveMemberOptions{Force: force})
 	if err != nil && !force {
-		return response.SmartError(err)
+		return err
 	}
 
-	// Remove the cluster member from the database.
-	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+	op.SetProgress("Removing from database", 50)
+
+	// Remove the cluster member and its config from the database.
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		err := cluster.DeleteCoreMemberConfig(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
 		return cluster.DeleteCoreClusterMember(ctx, tx, remote.Address.String())
 	})
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
 	// Remove the node from dqlite, if it has a record there.
 	if index >= 0 {
-		err = leader.Remove(r.Context(), info[index].ID)
+		err = leader.Remove(ctx, info[index].ID)
 		if err != nil {
-			return response.SmartError(err)
+			return err
 		}
 	}
 
 	localClient, err := internalClient.New(s.FileSystem().ControlSocket(), nil, nil, false)
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
-	err = internalClient.DeleteTrustStoreEntry(ctx, localClient, name)
+	err = internalClient.DeleteTrustStoreEntry(leaderCtx, localClient, name)
 	if err != nil && !force {
-		return response.SmartError(err)
+		return err
 	}
 
-	c, err = internalClient.New(remote.URL(), s.ServerCert(), publicKey, false)
+	op.SetProgress("Resetting removed cluster member", 70)
+
+	c, err = internalClient.New(remote.URL(), s.ServerCert(), publicKey, false, s.AlternateClusterCerts()...)
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
-	err = internalClient.ResetClusterMember(r.Context(), c, name, force)
+	err = internalClient.ResetClusterMember(ctx, c, name, force)
 	if err != nil && !force {
-		return response.SmartError(err)
+		return err
 	}
 
-	cluster, err := s.Cluster(false)
+	cluster, err := s.ClusterWithMaintenance(false)
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
 	intState, err := internalState.ToInternal(s)
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
+	op.SetProgress("Running post-remove hooks", 90)
+
 	// Run the PostRemove hook locally.
-	hookCtx, hookCancel := context.WithCancel(r.Context())
+	hookCtx, hookCancel := context.WithCancel(ctx)
 	err = intState.Hooks.PostRemove(hookCtx, s, force)
 	hookCancel()
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
 	// Run the PostRemove hook on all other members.
 	remotes := s.Remotes()
-	err = cluster.Query(r.Context(), true, func(ctx context.Context, c *client.Client) error {
+	err = cluster.Query(ctx, true, func(ctx context.Context, c *client.Client) error {
 		c.SetClusterNotification()
 		addrPort, err := types.ParseAddrPort(c.URL().URL.Host)
 		if err != nil {
@@ -670,8 +1198,15 @@ func clusterMemberDelete(s state.State, r *http.Request) response.Response {
 		return internalClient.RunPostRemoveHook(ctx, c.Client.UseTarget(remote.Name), internalTypes.HookRemoveMemberOptions{Force: force})
 	})
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
-	return response.EmptySyncResponse
+	err = s.SendEvent(types.EventMemberRemoved, types.EventMember{Name: remote.Name, Address: remote.Address.String()})
+	if err != nil {
+		return err
+	}
+
+	op.SetProgress("Removed cluster member", 100)
+
+	return nil
 }
@@ -0,0 +1,309 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+
+	dqliteClient "github.com/canonical/go-dqlite/client"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/operations"
+	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+var clusterMemberStateCmd = rest.Endpoint{
+	Path: "cluster/{name}/state",
+
+	Post: rest.EndpointAction{Handler: clusterMemberStatePost, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementClusterWrite, Summary: "Change the maintenance state of a cluster member", Request: types.ClusterMemberStatePost{}, Response: types.Operation{}},
+}
+
+// clusterMemberStatePost starts an operation that evacuates a cluster member, putting it into maintenance, or restores
+// an evacuated cluster member.
+func clusterMemberStatePost(s state.State, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := types.ClusterMemberStatePost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	_, ok := s.Remotes().RemotesByName()[name]
+	if !ok {
+		return response.NotFound(fmt.Errorf("No remote exists with the given name %q", name))
+	}
+
+	var op *operations.Operation
+	switch req.Action {
+	case types.ClusterMemberEvacuate:
+		op, err = s.Operations().Create(fmt.Sprintf("Evacuating cluster member %q", name), false, func(ctx context.Context, op *operations.Operation) error {
+			return evacuateClusterMember(ctx, op, s, name)
+		})
+	case types.ClusterMemberRestore:
+		op, err = s.Operations().Create(fmt.Sprintf("Restoring cluster member %q", name), false, func(ctx context.Context, op *operations.Operation) error {
+			return restoreClusterMember(ctx, op, s, name)
+		})
+	default:
+		return response.BadRequest(fmt.Errorf("Invalid action %q: must be one of %q or %q", req.Action, types.ClusterMemberEvacuate, types.ClusterMemberRestore))
+	}
+
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// evacuateClusterMember puts the named cluster member into maintenance. Dqlite leadership is transferred away from it,
+// its PreEvacuate hook is run, and it is demoted to a spare. If we are not the leader, the evacuation is forwarded to the leader.
+// Dqlite may still promote a cluster member in maintenance if no other cluster member can take on the role.
+func evacuateClusterMember(ctx context.Context, op *operations.Operation, s state.State, name string) error {
+	remote, ok := s.Remotes().RemotesByName()[name]
+	if !ok {
+		return api.StatusErrorf(http.StatusNotFound, "No remote exists with the given name %q", name)
+	}
+
+	op.UpdateMetadata(map[string]any{"name": name, "address": remote.Address.String()})
+
+	leaderCtx, cancel := context.WithTimeout(ctx, time.Second*30)
+	defer cancel()
+
+	leader, err := s.Database().Leader(leaderCtx)
+	if err != nil {
+		return err
+	}
+
+	leaderInfo, err := leader.Leader(leaderCtx)
+	if err != nil {
+		return err
+	}
+
+	// If we are not the leader, just forward the request.
+	if leaderInfo.Address != s.Address().URL.Host {
+		op.SetProgress("Forwarding evacuation to the leader", 10)
+
+		client, err := s.Leader()
+		if err != nil {
+			return err
+		}
+
+		return client.EvacuateClusterMember(ctx, name)
+	}
+
+	var member *cluster.CoreClusterMember
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		member, err = cluster.GetCoreClusterMember(ctx, tx, name)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if member.Maintenance {
+		return api.StatusErrorf(http.StatusConflict, "Cluster member %q is already in maintenance", name)
+	}
+
+	nodes, err := leader.Cluster(leaderCtx)
+	if err != nil {
+		return err
+	}
+
+	var target *dqliteClient.NodeInfo
+	for i, node := range nodes {
+		if node.Address == remote.Address.String() {
+			target = &nodes[i]
+			break
+		}
+	}
+
+	if target == nil {
+		return api.StatusErrorf(http.StatusConflict, "Cluster member %q is not a dqlite cluster member", name)
+	}
+
+	online := onlineDqliteMembers(leaderCtx, s, nodes)
+	err = checkRoleChangeQuorum(nodes, *target, dqliteClient.Spare, online)
+	if err != nil {
+		return err
+	}
+
+	// If we are the leader and evacuating ourselves, hand leadership over to another online voter and evacuate from there.
+	if target.Address == leaderInfo.Address {
+		otherVoters := []uint64{}
+		for _, node := range nodes {
+			if node.ID != target.ID && node.Role == dqliteClient.Voter && online[node.Address] {
+				otherVoters = append(otherVoters, node.ID)
+			}
+		}
+
+		if len(otherVoters) == 0 {
+			return api.StatusErrorf(http.StatusConflict, "Found no online voters to transfer leadership to")
+		}
+
+		op.SetProgress("Transferring leadership", 10)
+
+		err = leader.Transfer(leaderCtx, otherVoters[rand.Intn(len(otherVoters))])
+		if err != nil {
+			return fmt.Errorf("Failed to transfer leadership: %w", err)
+		}
+
+		client, err := s.Leader()
+		if err != nil {
+			return err
+		}
+
+		op.SetProgress("Forwarding evacuation to the new leader", 20)
+
+		return client.EvacuateClusterMember(ctx, name)
+	}
+
+	publicKey, err := s.ClusterCert().PublicKeyX509()
+	if err != nil {
+		return err
+	}
+
+	c, err := internalClient.New(remote.URL(), s.ServerCert(), publicKey, false, s.AlternateClusterCerts()...)
+	if err != nil {
+		return err
+	}
+
+	op.SetProgress("Running pre-evacuate hook", 30)
+
+	err = internalClient.RunPreEvacuateHook(leaderCtx, c.UseTarget(name))
+	if err != nil {
+		return err
+	}
+
+	if target.Role != dqliteClient.Spare {
+		op.SetProgress("Demoting to spare", 60)
+
+		err = leader.Assign(leaderCtx, target.ID, dqliteClient.Spare)
+		if err != nil {
+			return fmt.Errorf("Failed to assign role %q to cluster member %q: %w", dqliteClient.Spare, name, err)
+		}
+
+		logger.Info("Assigned dqlite role to cluster member", logger.Ctx{"name": name, "oldRole": target.Role.String(), "newRole": dqliteClient.Spare.String()})
+	}
+
+	op.SetProgress("Marking as in maintenance", 80)
+
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		member, err := cluster.GetCoreClusterMember(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
+		member.Maintenance = true
+		member.Role = cluster.Role(dqliteClient.Spare.String())
+
+		return cluster.UpdateCoreClusterMember(ctx, tx, name, *member)
+	})
+	if err != nil {
+		return err
+	}
+
+	if target.Role != dqliteClient.Spare {
+		err = s.SendEvent(types.EventRoleChanged, types.EventMember{Name: name, Address: remote.Address.String(), Role: dqliteClient.Spare.String()})
+		if err != nil {
+			return err
+		}
+	}
+
+	op.SetProgress("Evacuated cluster member", 100)
+
+	return nil
+}
+
+// restoreClusterMember takes the named cluster member out of maintenance and runs its PostRestore hook.
+// Dqlite promotes the cluster member again as needed. If we are not the leader, the restore is forwarded to the leader.
+func restoreClusterMember(ctx context.Context, op *operations.Operation, s state.State, name string) error {
+	remote, ok := s.Remotes().RemotesByName()[name]
+	if !ok {
+		return api.StatusErrorf(http.StatusNotFound, "No remote exists with the given name %q", name)
+	}
+
+	op.UpdateMetadata(map[string]any{"name": name, "address": remote.Address.String()})
+
+	leaderCtx, cancel := context.WithTimeout(ctx, time.Second*30)
+	defer cancel()
+
+	leader, err := s.Database().Leader(leaderCtx)
+	if err != nil {
+		return err
+	}
+
+	leaderInfo, err := leader.Leader(leaderCtx)
+	if err != nil {
+		return err
+	}
+
+	// If we are not the leader, just forward the request.
+	if leaderInfo.Address != s.Address().URL.Host {
+		op.SetProgress("Forwarding restore to the leader", 10)
+
+		client, err := s.Leader()
+		if err != nil {
+			return err
+		}
+
+		return client.RestoreClusterMember(ctx, name)
+	}
+
+	op.SetProgress("Taking out of maintenance", 30)
+
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		member, err := cluster.GetCoreClusterMember(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+
+		if !member.Maintenance {
+			return api.StatusErrorf(http.StatusConflict, "Cluster member %q is not in maintenance", name)
+		}
+
+		member.Maintenance = false
+
+		return cluster.UpdateCoreClusterMember(ctx, tx, name, *member)
+	})
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := s.ClusterCert().PublicKeyX509()
+	if err != nil {
+		return err
+	}
+
+	c, err := internalClient.New(remote.URL(), s.ServerCert(), publicKey, false, s.AlternateClusterCerts()...)
+	if err != nil {
+		return err
+	}
+
+	op.SetProgress("Running post-restore hook", 60)
+
+	err = internalClient.RunPostRestoreHook(leaderCtx, c.UseTarget(name))
+	if err != nil {
+		return err
+	}
+
+	op.SetProgress("Restored cluster member", 100)
+
+	return nil
+}
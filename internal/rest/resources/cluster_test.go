@@ -0,0 +1,280 @@
+package resources
+
+import (
+	"net/http"
+	"testing"
+
+	dqliteClient "github.com/canonical/go-dqlite/client"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/db"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type clusterSuite struct {
+	suite.Suite
+}
+
+func TestClusterSuite(t *testing.T) {
+	suite.Run(t, new(clusterSuite))
+}
+
+func (t *clusterSuite) Test_memberPutFromInitConfig() {
+	initConfig := map[string]string{
+		"core.failure_domain": "rack-1",
+		"core.label.zone":     "a",
+		"core.label.os/arch":  "amd64",
+		"label.ignored":       "true",
+	}
+
+	put := types.MemberPutFromInitConfig(initConfig)
+	t.Equal("rack-1", put.FailureDomain)
+	t.Equal(map[string]string{"zone": "a", "os/arch": "amd64"}, put.Labels)
+	t.NoError(validateMemberPut(put))
+}
+
+func (t *clusterSuite) Test_validateMemberPut() {
+	tests := []struct {
+		name  string
+		put   types.ClusterMemberPut
+		valid bool
+	}{
+		{
+			name:  "No labels or failure domain",
+			put:   types.ClusterMemberPut{},
+			valid: true,
+		},
+		{
+			name:  "Valid labels and failure domain",
+			put:   types.ClusterMemberPut{Labels: map[string]string{"zone": "", "example.com/rack": "r1 r2"}, FailureDomain: "dc1.rack-2"},
+			valid: true,
+		},
+		{
+			name: "Empty label key",
+			put:  types.ClusterMemberPut{Labels: map[string]string{"": "a"}},
+		},
+		{
+			name: "Label key with spaces",
+			put:  types.ClusterMemberPut{Labels: map[string]string{"my zone": "a"}},
+		},
+		{
+			name: "Label key with trailing separator",
+			put:  types.ClusterMemberPut{Labels: map[string]string{"zone-": "a"}},
+		},
+		{
+			name: "Failure domain with spaces",
+			put:  types.ClusterMemberPut{FailureDomain: "rack 1"},
+		},
+	}
+
+	for i, test := range tests {
+		t.T().Logf("%s (case %d)", test.name, i)
+
+		err := validateMemberPut(test.put)
+		if test.valid {
+			t.NoError(err)
+		} else {
+			t.Error(err)
+		}
+	}
+}
+
+func (t *clusterSuite) Test_parseDqliteRole() {
+	for _, role := range []dqliteClient.NodeRole{dqliteClient.Voter, dqliteClient.StandBy, dqliteClient.Spare} {
+		parsed, err := parseDqliteRole(role.String())
+		t.NoError(err)
+		t.Equal(role, parsed)
+	}
+
+	_, err := parseDqliteRole("leader")
+	t.Error(err)
+}
+
+func (t *clusterSuite) Test_checkRoleChangeQuorum() {
+	nodes := []dqliteClient.NodeInfo{
+		{ID: 1, Address: "10.0.0.1:8443", Role: dqliteClient.Voter},
+		{ID: 2, Address: "10.0.0.2:8443", Role: dqliteClient.Voter},
+		{ID: 3, Address: "10.0.0.3:8443", Role: dqliteClient.Voter},
+		{ID: 4, Address: "10.0.0.4:8443", Role: dqliteClient.StandBy},
+		{ID: 5, Address: "10.0.0.5:8443", Role: dqliteClient.Spare},
+	}
+
+	tests := []struct {
+		name    string
+		target  int
+		role    dqliteClient.NodeRole
+		offline []int
+		allowed bool
+	}{
+		{
+			name:    "Demote a voter with all members online",
+			target:  2,
+			role:    dqliteClient.Spare,
+			allowed: true,
+		},
+		{
+			name:    "Demote an offline voter",
+			target:  2,
+			role:    dqliteClient.StandBy,
+			offline: []int{2},
+			allowed: true,
+		},
+		{
+			name:    "Demote an online voter while another voter is offline",
+			target:  1,
+			role:    dqliteClient.StandBy,
+			offline: []int{2},
+		},
+		{
+			name:    "Promote an online stand-by",
+			target:  3,
+			role:    dqliteClient.Voter,
+			allowed: true,
+		},
+		{
+			name:    "Promote an offline spare",
+			target:  4,
+			role:    dqliteClient.Voter,
+			offline: []int{4},
+		},
+		{
+			name:    "Promote an online spare while two voters are offline",
+			target:  4,
+			role:    dqliteClient.Voter,
+			offline: []int{0, 1},
+		},
+	}
+
+	for i, test := range tests {
+		t.T().Logf("%s (case %d)", test.name, i)
+
+		online := map[string]bool{}
+		for _, node := range nodes {
+			online[node.Address] = true
+		}
+
+		for _, j := range test.offline {
+			online[nodes[j].Address] = false
+		}
+
+		err := checkRoleChangeQuorum(nodes, nodes[test.target], test.role, online)
+		if test.allowed {
+			t.NoError(err)
+		} else {
+			t.True(api.StatusErrorCheck(err, http.StatusConflict))
+		}
+	}
+
+	// The last voter can't be demoted.
+	single := []dqliteClient.NodeInfo{{ID: 1, Address: "10.0.0.1:8443", Role: dqliteClient.Voter}}
+	err := checkRoleChangeQuorum(single, single[0], dqliteClient.Spare, map[string]bool{"10.0.0.1:8443": true})
+	t.True(api.StatusErrorCheck(err, http.StatusConflict))
+}
+
+func (t *clusterSuite) Test_dqliteMetadata() {
+	members := []cluster.CoreClusterMember{
+		{Name: "member1", Address: "10.0.0.1:8443", FailureDomain: "rack1"},
+		{Name: "member2", Address: "10.0.0.2:8443", Maintenance: true},
+		{Name: "member3", Address: "10.0.0.3:8443"},
+	}
+
+	metadata := dqliteMetadata(members, map[string]bool{"10.0.0.1:8443": true, "10.0.0.2:8443": true})
+	t.Equal(map[string]*dqliteClient.NodeMetadata{
+		"10.0.0.1:8443": {FailureDomain: db.FailureDomainCode("rack1")},
+		"10.0.0.2:8443": {Weight: db.MaintenanceWeight},
+	}, metadata)
+}
+
+func (t *clusterSuite) Test_roleAssignmentReverted() {
+	nodes := []dqliteClient.NodeInfo{
+		{ID: 1, Address: "10.0.0.1:8443", Role: dqliteClient.Voter},
+		{ID: 2, Address: "10.0.0.2:8443", Role: dqliteClient.Voter},
+		{ID: 3, Address: "10.0.0.3:8443", Role: dqliteClient.Voter},
+		{ID: 4, Address: "10.0.0.4:8443", Role: dqliteClient.StandBy},
+		{ID: 5, Address: "10.0.0.5:8443", Role: dqliteClient.StandBy},
+	}
+
+	tests := []struct {
+		name     string
+		nodes    []dqliteClient.NodeInfo
+		target   int
+		role     dqliteClient.NodeRole
+		pinned   bool
+		offline  []int
+		reverted bool
+	}{
+		{
+			name:   "Demote a pinned voter to stand-by",
+			nodes:  nodes,
+			target: 2,
+			role:   dqliteClient.StandBy,
+			pinned: true,
+		},
+		{
+			name:     "Demote a voter to stand-by without pinning it",
+			nodes:    nodes,
+			target:   2,
+			role:     dqliteClient.StandBy,
+			reverted: true,
+		},
+		{
+			name:     "Demote a pinned voter to spare while dqlite lacks stand-bys",
+			nodes:    nodes,
+			target:   2,
+			role:     dqliteClient.Spare,
+			pinned:   true,
+			reverted: true,
+		},
+		{
+			name:     "Promote a stand-by beyond the target number of voters",
+			nodes:    nodes,
+			target:   3,
+			role:     dqliteClient.Voter,
+			reverted: true,
+		},
+		{
+			name:    "Demote an offline voter",
+			nodes:   nodes,
+			target:  2,
+			role:    dqliteClient.Spare,
+			offline: []int{2},
+		},
+		{
+			name: "Promote a spare while dqlite lacks voters",
+			nodes: []dqliteClient.NodeInfo{
+				{ID: 1, Address: "10.0.0.1:8443", Role: dqliteClient.Voter},
+				{ID: 2, Address: "10.0.0.2:8443", Role: dqliteClient.Spare},
+				{ID: 3, Address: "10.0.0.3:8443", Role: dqliteClient.Spare},
+			},
+			target: 1,
+			role:   dqliteClient.Voter,
+		},
+	}
+
+	for i, test := range tests {
+		t.T().Logf("%s (case %d)", test.name, i)
+
+		metadata := map[string]*dqliteClient.NodeMetadata{}
+		for _, node := range test.nodes {
+			metadata[node.Address] = &dqliteClient.NodeMetadata{}
+		}
+
+		for _, j := range test.offline {
+			delete(metadata, test.nodes[j].Address)
+		}
+
+		if test.pinned {
+			metadata[test.nodes[test.target].Address].Weight = db.PinnedWeight
+		}
+
+		// Dqlite picks between tied candidates at random, so check each case a few times.
+		for range 10 {
+			t.Equal(test.reverted, roleAssignmentReverted(test.nodes, test.nodes[test.target], test.role, 1, metadata))
+		}
+
+		// The given nodes are left untouched.
+		t.NotEqual(test.role, test.nodes[test.target].Role)
+	}
+}
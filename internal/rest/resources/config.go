@@ -0,0 +1,165 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/logger"
+
+	"github.com/canonical/microcluster/v2/client"
+	"github.com/canonical/microcluster/v2/cluster"
+	internalConfig "github.com/canonical/microcluster/v2/internal/config"
+	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+var configCmd = rest.Endpoint{
+	Path: "config",
+
+	Get:   rest.EndpointAction{Handler: configGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementConfigRead, Summary: "Get the cluster config", Response: types.ClusterConfig{}},
+	Put:   rest.EndpointAction{Handler: configPut, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementConfigWrite, Summary: "Replace the cluster config", Request: types.ClusterConfig{}},
+	Patch: rest.EndpointAction{Handler: configPatch, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementConfigWrite, Summary: "Partially update the cluster config", Request: types.ClusterConfig{}},
+}
+
+// configStore returns the cluster config as seen by the cluster member named in the ?target= query parameter, or by the
+// local cluster member if none is given. As the config is stored in the database, the target doesn't need to be reachable.
+func configStore(s state.State, r *http.Request) (*internalConfig.Store, error) {
+	target := r.URL.Query().Get("target")
+	if target == "" || target == s.Name() {
+		return s.Config(), nil
+	}
+
+	err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		_, err := cluster.GetCoreClusterMember(ctx, tx, target)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get cluster member %q: %w", target, err)
+	}
+
+	return s.Config().ForMember(target), nil
+}
+
+// configGet returns the cluster config keys that are set. Keys scoped to cluster members are those of the local
+// cluster member, or of the cluster member given with ?target=.
+func configGet(s state.State, r *http.Request) response.Response {
+	store, err := configStore(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	config, err := store.GetAll(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, types.ClusterConfig{Config: config}, config)
+}
+
+// configPut replaces the cluster config, unsetting any key not in the request.
+func configPut(s state.State, r *http.Request) response.Response {
+	return configUpdate(s, r, true)
+}
+
+// configPatch sets the cluster config keys in the request, leaving other keys unchanged.
+func configPatch(s state.State, r *http.Request) response.Response {
+	return configUpdate(s, r, false)
+}
+
+// configUpdate applies the cluster config in the request, as long as the ETag in the If-Match header, if any, matches
+// the current config. Keys scoped to cluster members are set for the cluster member given with ?target=, if any.
+// The OnConfigChange hook is then run on every cluster member.
+func configUpdate(s state.State, r *http.Request, replace bool) response.Response {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	store, err := configStore(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := types.ClusterConfig{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Config == nil {
+		req.Config = map[string]string{}
+	}
+
+	changes, err := store.Update(r.Context(), req.Config, replace, func(current map[string]string) error {
+		err := util.EtagCheck(r, current)
+		if err != nil {
+			return err
+		}
+
+		updated := make(map[string]string, len(current)+len(req.Config))
+		if !replace {
+			for key, value := range current {
+				updated[key] = value
+			}
+		}
+
+		for key, value := range req.Config {
+			updated[key] = value
+		}
+
+		return checkHeartbeatConfig(intState, updated)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if len(changes) > 0 {
+		go runConfigChangeHooks(intState.Context, s, changes)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// runConfigChangeHooks runs the OnConfigChange hook locally and on every other cluster member. Errors are logged rather than returned, so that every member gets to run its hook.
+func runConfigChangeHooks(ctx context.Context, s state.State, changes []types.ConfigChange) {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		logger.Error("Failed to run config change hooks", logger.Ctx{"error": err})
+		return
+	}
+
+	logger.Info("Cluster config changed", logger.Ctx{"changes": changes})
+
+	hookCtx, hookCancel := context.WithCancel(ctx)
+	err = intState.Hooks.OnConfigChange(hookCtx, s, changes)
+	hookCancel()
+	if err != nil {
+		logger.Error("Failed to run config change hook", logger.Ctx{"error": err})
+	}
+
+	clusterClients, err := s.ClusterWithMaintenance(false)
+	if err != nil {
+		logger.Error("Failed to get a client for every cluster member", logger.Ctx{"error": err})
+		return
+	}
+
+	_ = clusterClients.Query(ctx, true, func(ctx context.Context, c *client.Client) error {
+		err := internalClient.RunConfigChangeHook(ctx, &c.Client, internalTypes.HookConfigChangeOptions{Changes: changes})
+		if err != nil {
+			logger.Error("Failed to run config change hook", logger.Ctx{"address": c.URL().URL.Host, "error": err})
+		}
+
+		return nil
+	})
+}
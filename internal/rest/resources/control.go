@@ -15,6 +15,7 @@ import (
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/revert"
 
+	"github.com/canonical/microcluster/v2/internal/operations"
 	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
 	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
 	internalState "github.com/canonical/microcluster/v2/internal/state"
@@ -29,7 +30,7 @@ import (
 var controlCmd = rest.Endpoint{
 	AllowedBeforeInit: true,
 
-	Post: rest.EndpointAction{Handler: controlPost, AccessHandler: access.AllowAuthenticated},
+	Post: rest.EndpointAction{Handler: controlPost, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementDaemonWrite, Summary: "Bootstrap or join a cluster", Request: internalTypes.Control{}, Response: types.Operation{}},
 }
 
 func controlPost(state state.State, r *http.Request) response.Response {
@@ -54,17 +55,47 @@ func controlPost(state state.State, r *http.Request) response.Response {
 		return response.SmartError(fmt.Errorf("Invalid cluster member name %q: %w", req.Name, err))
 	}
 
+	err = validateMemberPut(types.MemberPutFromInitConfig(req.InitConfig))
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	intState, err := internalState.ToInternal(state)
 	if err != nil {
 		return response.SmartError(err)
 	}
 
+	description := fmt.Sprintf("Bootstrapping cluster member %q", req.Name)
+	if req.JoinToken != "" {
+		description = fmt.Sprintf("Joining cluster as %q", req.Name)
+	}
+
+	op, err := intState.InternalOperations.Create(description, false, func(ctx context.Context, op *operations.Operation) error {
+		return initClusterMember(ctx, op, state, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// initClusterMember bootstraps a new cluster, or joins an existing one, using the configuration in the control request.
+// On failure, the cluster member is reset and its daemon is re-executed once the operation result has been collected.
+func initClusterMember(ctx context.Context, op *operations.Operation, state state.State, req *internalTypes.Control) error {
+	intState, err := internalState.ToInternal(state)
+	if err != nil {
+		return err
+	}
+
+	op.UpdateMetadata(map[string]any{"name": req.Name, "address": req.Address.String()})
+
 	reverter := revert.New()
 	defer reverter.Fail()
 
 	serverCert, err := state.ServerCert().PublicKeyX509()
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
 	certNameMatches := shared.ValueInSlice(req.Name, serverCert.DNSNames)
@@ -77,12 +108,13 @@ func controlPost(state state.State, r *http.Request) response.Response {
 		}
 
 		// Run the pre-remove hook like we do for cluster node removals.
-		err := intState.Hooks.PreRemove(r.Context(), state, true)
+		err := intState.Hooks.PreRemove(ctx, state, true)
 		if err != nil {
 			logger.Error("Failed to run pre-remove hook on initialization error", logger.Ctx{"error": err})
 		}
 
-		reExec, err := resetClusterMember(r.Context(), state, true)
+		// Wait for the client to collect the operation result before re-executing the daemon.
+		reExec, err := resetClusterMember(op.DeliveredContext(), state, true)
 		if err != nil {
 			logger.Error("Failed to reset cluster member on bootstrap error", logger.Ctx{"error": err})
 			return
@@ -116,51 +148,67 @@ func controlPost(state state.State, r *http.Request) response.Response {
 
 	// Replace the server keypair if the cluster member name has changed upon initialization.
 	if !certNameMatches {
+		op.SetProgress("Generating server certificate", 10)
+
 		err := os.Remove(filepath.Join(state.FileSystem().StateDir, "server.crt"))
 		if err != nil {
-			return response.SmartError(err)
+			return err
 		}
 
 		err = os.Remove(filepath.Join(state.FileSystem().StateDir, "server.key"))
 		if err != nil {
-			return response.SmartError(err)
+			return err
+		}
+
+		err = os.Remove(filepath.Join(state.FileSystem().StateDir, "server.ca"))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		// Generate a new keypair with the new subject name, or have it issued if there is a certificate issuer.
+		if intState.CertificateIssuer != nil {
+			err = internalState.WriteIssuedKeyPair(ctx, intState.CertificateIssuer, state.FileSystem().StateDir, types.ServerCertificateName, req.Name)
+		} else {
+			_, err = shared.KeyPairAndCA(state.FileSystem().StateDir, string(types.ServerCertificateName), shared.CertServer, shared.CertOptions{AddHosts: true, SubjectName: req.Name})
 		}
 
-		// Generate a new keypair with the new subject name.
-		_, err = shared.KeyPairAndCA(state.FileSystem().StateDir, string(types.ServerCertificateName), shared.CertServer, shared.CertOptions{AddHosts: true, SubjectName: req.Name})
 		if err != nil {
-			return response.SmartError(err)
+			return err
 		}
 
 		err = intState.ReloadCert(types.ServerCertificateName)
 		if err != nil {
-			return response.SmartError(err)
+			return err
 		}
 	}
 
 	if req.JoinToken != "" {
-		joinInfo, err = joinWithToken(state, r, req)
+		joinInfo, err = joinWithToken(ctx, op, state, req)
 		if err != nil {
-			return response.SmartError(err)
+			return err
 		}
 
+		op.SetProgress("Joined cluster", 100)
 		reverter.Success()
 
-		return response.EmptySyncResponse
+		return nil
 	}
 
+	op.SetProgress("Starting API and database", 50)
+
 	daemonConfig := &trust.Location{Address: req.Address, Name: req.Name}
-	err = intState.StartAPI(r.Context(), req.Bootstrap, req.InitConfig, daemonConfig)
+	err = intState.StartAPI(ctx, req.Bootstrap, req.InitConfig, daemonConfig)
 	if err != nil {
-		return response.SmartError(err)
+		return err
 	}
 
+	op.SetProgress("Bootstrapped cluster", 100)
 	reverter.Success()
 
-	return response.EmptySyncResponse
+	return nil
 }
 
-func joinWithToken(state state.State, r *http.Request, req *internalTypes.Control) (*internalTypes.TokenResponse, error) {
+func joinWithToken(ctx context.Context, op *operations.Operation, state state.State, req *internalTypes.Control) (*internalTypes.TokenResponse, error) {
 	token, err := internalTypes.DecodeToken(req.JoinToken)
 	if err != nil {
 		return nil, err
@@ -183,6 +231,8 @@ func joinWithToken(state state.State, r *http.Request, req *internalTypes.Contro
 		Certificate: types.X509Certificate{Certificate: serverCert},
 	}
 
+	memberPut := types.MemberPutFromInitConfig(req.InitConfig)
+
 	// Prepare the cluster for the incoming dqlite request by creating a database entry.
 	internalVersion, externalVersion, _ := state.Database().SchemaVersion()
 	newClusterMember := types.ClusterMember{
@@ -193,10 +243,14 @@ func joinWithToken(state state.State, r *http.Request, req *internalTypes.Contro
 		},
 		SchemaInternalVersion: internalVersion,
 		SchemaExternalVersion: externalVersion,
+		Labels:                memberPut.Labels,
+		FailureDomain:         memberPut.FailureDomain,
 		Secret:                token.Secret,
 		Extensions:            intState.Extensions,
 	}
 
+	op.SetProgress("Requesting to join cluster", 20)
+
 	// Get a client to the target address.
 	var lastErr error
 	var joinInfo *internalTypes.TokenResponse
@@ -220,7 +274,7 @@ func joinWithToken(state state.State, r *http.Request, req *internalTypes.Contro
 			return nil, err
 		}
 
-		joinInfo, err = internalClient.AddClusterMember(context.Background(), d, newClusterMember)
+		joinInfo, err = internalClient.AddClusterMember(ctx, d, newClusterMember)
 		if err == nil {
 			break
 		}
@@ -271,8 +325,10 @@ func joinWithToken(state state.State, r *http.Request, req *internalTypes.Contro
 		return nil, err
 	}
 
+	op.SetProgress("Starting API and joining database", 50)
+
 	// Start the HTTPS listeners and join Dqlite.
-	err = intState.StartAPI(r.Context(), false, req.InitConfig, daemonConfig, joinAddrs.Strings()...)
+	err = intState.StartAPI(ctx, false, req.InitConfig, daemonConfig, joinAddrs.Strings()...)
 	if err != nil {
 		return nil, err
 	}
@@ -22,8 +22,8 @@ import (
 var daemonCmd = rest.Endpoint{
 	Path: "daemon/servers",
 
-	Get: rest.EndpointAction{Handler: daemonServersGet, AccessHandler: access.AllowAuthenticated},
-	Put: rest.EndpointAction{Handler: daemonServersPut, AccessHandler: access.AllowAuthenticated},
+	Get: rest.EndpointAction{Handler: daemonServersGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementDaemonRead, Summary: "Get the additional listeners of the cluster member", Response: map[string]types.ServerConfig{}},
+	Put: rest.EndpointAction{Handler: daemonServersPut, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementDaemonWrite, Summary: "Update the additional listeners of the cluster member", Request: map[string]types.ServerConfig{}},
 }
 
 func daemonServersGet(s state.State, r *http.Request) response.Response {
@@ -91,7 +91,7 @@ func daemonServersPut(s state.State, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	cluster, err := s.Cluster(false)
+	cluster, err := s.ClusterWithMaintenance(false)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -116,5 +116,10 @@ func daemonServersPut(s state.State, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	err = s.SendEvent(types.EventDaemonConfigUpdated, daemonConfig.Dump())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	return response.EmptySyncResponse
 }
@@ -1,22 +1,40 @@
 package resources
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/logger"
 
+	"github.com/canonical/microcluster/v2/internal/recover"
 	"github.com/canonical/microcluster/v2/internal/state"
 	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
 )
 
 var databaseCmd = rest.Endpoint{
 	AllowedBeforeInit: true,
 	Path:              "database",
 
-	Post:  rest.EndpointAction{Handler: databasePost},
-	Patch: rest.EndpointAction{Handler: databasePatch},
+	Post:  rest.EndpointAction{Handler: databasePost, AccessHandler: access.AllowClusterMembers, Summary: "Connect to the dqlite database"},
+	Patch: rest.EndpointAction{Handler: databasePatch, AccessHandler: access.AllowClusterMembers, Summary: "Negotiate the dqlite version"},
+}
+
+var databaseBackupCmd = rest.Endpoint{
+	Path: "database/backup",
+
+	Post: rest.EndpointAction{Handler: databaseBackupPost, AccessHandler: access.AllowClusterMembers, Entitlement: types.EntitlementDatabaseRead, Summary: "Download a backup of the database"},
+}
+
+var databaseRestoreCmd = rest.Endpoint{
+	Path: "database/restore",
+
+	Post: rest.EndpointAction{Handler: databaseRestorePost, AccessHandler: access.AllowClusterMembers, Entitlement: types.EntitlementDatabaseWrite, Summary: "Restore the database from a backup"},
 }
 
 func databasePost(state state.State, r *http.Request) response.Response {
@@ -63,3 +81,49 @@ func databasePatch(s state.State, r *http.Request) response.Response {
 
 	return response.EmptySyncResponse
 }
+
+// databaseBackupPost takes a consistent snapshot of the running database and streams it as a gzip-compressed tarball.
+// The tarball is written straight from the transaction, which is held open until the client has received it.
+func databaseBackupPost(s state.State, r *http.Request) response.Response {
+	schemaInternal, schemaExternal, apiExtensions := s.Database().SchemaVersion()
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		return s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+			backup, err := recover.CreateOnlineDatabaseBackup(ctx, tx, s.Name(), schemaInternal, schemaExternal, apiExtensions)
+			if err != nil {
+				return err
+			}
+
+			w.Header().Set("Content-Type", "application/gzip")
+			w.WriteHeader(http.StatusOK)
+
+			return recover.WriteDatabaseBackup(ctx, tx, w, *backup)
+		})
+	})
+}
+
+// databaseRestorePost replaces the contents of the database with those of a backup tarball created by databaseBackupPost.
+// The backup must have been taken with the same schema version as the running database.
+func databaseRestorePost(s state.State, r *http.Request) response.Response {
+	backup, dump, err := recover.ReadDatabaseBackup(r.Body)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	schemaInternal, schemaExternal, _ := s.Database().SchemaVersion()
+	err = recover.ValidateDatabaseBackup(*backup, schemaInternal, schemaExternal)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		return recover.RestoreDatabaseDump(ctx, tx, dump)
+	})
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed to restore database backup: %w", err))
+	}
+
+	logger.Warn("Restored database from backup", logger.Ctx{"created": backup.CreatedAt, "location": backup.Location})
+
+	return response.EmptySyncResponse
+}
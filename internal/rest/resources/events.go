@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/ws"
+
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+var eventsCmd = rest.Endpoint{
+	Path: "events",
+
+	Get: rest.EndpointAction{Handler: eventsGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementDaemonRead, Summary: "Listen for events over a websocket"},
+}
+
+var eventsInternalCmd = rest.Endpoint{
+	Path: "events",
+
+	Post: rest.EndpointAction{Handler: eventsPost, AccessHandler: access.AllowClusterMembers, Summary: "Send an event to the local event listeners", Request: types.Event{}},
+}
+
+// eventsGet upgrades the connection to a websocket and streams events of the requested types to it.
+func eventsGet(s state.State, r *http.Request) response.Response {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var eventTypes []types.EventType
+	typeParam := r.URL.Query().Get("type")
+	if typeParam != "" {
+		for _, eventType := range strings.Split(typeParam, ",") {
+			eventTypes = append(eventTypes, types.EventType(eventType))
+		}
+	}
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		conn, err := ws.Upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to upgrade events connection: %w", err)
+		}
+
+		listener := intState.Events.AddListener(conn, eventTypes)
+		listener.Wait(intState.Context)
+
+		return nil
+	})
+}
+
+// eventsPost receives an event forwarded from another cluster member and sends it to the local event listeners.
+func eventsPost(s state.State, r *http.Request) response.Response {
+	var event types.Event
+	err := json.NewDecoder(r.Body).Decode(&event)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	intState.Events.Send(event)
+
+	return response.EmptySyncResponse
+}
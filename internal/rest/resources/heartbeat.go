@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
@@ -14,17 +15,33 @@ import (
 
 	"github.com/canonical/microcluster/v2/client"
 	"github.com/canonical/microcluster/v2/cluster"
+	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
 	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
 	internalState "github.com/canonical/microcluster/v2/internal/state"
 	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
 	"github.com/canonical/microcluster/v2/rest/types"
 	"github.com/canonical/microcluster/v2/state"
 )
 
+// maxHeartbeatDataSize is the maximum size of the application data a cluster member can contribute to a heartbeat.
+const maxHeartbeatDataSize = 64 * 1024
+
 var heartbeatCmd = rest.Endpoint{
 	Path: "heartbeat",
 
-	Post: rest.EndpointAction{Handler: heartbeatPost, AllowUntrusted: true},
+	Get:  rest.EndpointAction{Handler: heartbeatGet, AccessHandler: access.AllowClusterMembers, Summary: "Get the heartbeat configuration in effect on the cluster member", Response: types.HeartbeatConfig{}},
+	Post: rest.EndpointAction{Handler: heartbeatPost, AllowUntrusted: true, Summary: "Answer a heartbeat from the leader", Request: internalTypes.HeartbeatInfo{}, Response: internalTypes.HeartbeatResponse{}},
+}
+
+// heartbeatGet returns the heartbeat configuration in effect on this cluster member.
+func heartbeatGet(s state.State, r *http.Request) response.Response {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, intState.InternalDatabase.HeartbeatConfig())
 }
 
 func heartbeatPost(s state.State, r *http.Request) response.Response {
@@ -77,6 +94,38 @@ func heartbeatPost(s state.State, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	if hbInfo.LeaderAddress != "" {
+		intState.InternalDatabase.SetLeaderAddress(hbInfo.LeaderAddress)
+	}
+
+	// Older leaders don't distribute the heartbeat configuration.
+	if hbInfo.HeartbeatConfig != nil {
+		err = applyHeartbeatConfig(s, *hbInfo.HeartbeatConfig)
+		if err != nil {
+			logger.Error("Failed to apply heartbeat configuration", logger.Ctx{"error": err})
+		}
+	}
+
+	// Older leaders don't run the failure detector.
+	if hbInfo.MemberHealth != nil {
+		intState.FailureDetector.Load(hbInfo.MemberHealth)
+	}
+
+	// Older leaders don't aggregate heartbeat data.
+	if hbInfo.MemberData != nil {
+		intState.InternalDatabase.SetHeartbeatData(hbInfo.MemberData)
+
+		err = intState.Hooks.OnHeartbeatData(r.Context(), s, hbInfo.MemberData)
+		if err != nil {
+			logger.Error("Failed to run heartbeat data hook", logger.Ctx{"error": err})
+		}
+	}
+
+	// Older leaders don't aggregate certificate expiry.
+	if hbInfo.MemberCertificates != nil {
+		intState.InternalDatabase.SetMemberCertificates(hbInfo.MemberCertificates)
+	}
+
 	if internalSchemaVersion != hbInfo.MaxSchemaInternal || externalSchemaVersion != hbInfo.MaxSchemaExternal {
 		err := intState.InternalDatabase.Update()
 		if err != nil {
@@ -86,7 +135,65 @@ func heartbeatPost(s state.State, r *http.Request) response.Response {
 
 	// TODO: If our schema version is behind, we should try to update here.
 
-	return response.EmptySyncResponse
+	// Still answer the heartbeat if the data can't be gathered, so that we aren't considered offline.
+	data, err := heartbeatData(r.Context(), s)
+	if err != nil {
+		logger.Error("Failed to get local heartbeat data", logger.Ctx{"error": err})
+	}
+
+	certificates, err := localCertificateExpiries(s)
+	if err != nil {
+		logger.Error("Failed to get local certificate expiry", logger.Ctx{"error": err})
+	}
+
+	// Renewing the server certificate involves the other cluster members, so don't hold up the heartbeat. The keypairs are
+	// only checked about once an hour.
+	go checkCertificateExpiry(intState.Context, s)
+
+	heartbeatConfig := intState.InternalDatabase.HeartbeatConfig()
+
+	return response.SyncResponse(true, internalTypes.HeartbeatResponse{Data: data, HeartbeatConfig: &heartbeatConfig, Certificates: certificates})
+}
+
+// heartbeatData runs the HeartbeatData hook, returning the application data to contribute to the heartbeat.
+func heartbeatData(ctx context.Context, s state.State) (json.RawMessage, error) {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := intState.Hooks.HeartbeatData(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to run heartbeat data hook: %w", err)
+	}
+
+	if len(data) > maxHeartbeatDataSize {
+		return nil, fmt.Errorf("Heartbeat data of %d bytes exceeds the limit of %d bytes", len(data), maxHeartbeatDataSize)
+	}
+
+	if len(data) > 0 && !json.Valid(data) {
+		return nil, fmt.Errorf("Heartbeat data is not valid JSON")
+	}
+
+	return data, nil
+}
+
+// aggregateHeartbeatData returns the heartbeat data of each of the named cluster members, taken from the updates if
+// present, and from the previous heartbeat round otherwise. A nil update removes the cluster member's data.
+func aggregateHeartbeatData[S ~[]E, E any](previous map[string]S, names []string, updates map[string]S) map[string]S {
+	aggregated := make(map[string]S, len(names))
+	for _, name := range names {
+		data, ok := updates[name]
+		if !ok {
+			data = previous[name]
+		}
+
+		if len(data) > 0 {
+			aggregated[name] = data
+		}
+	}
+
+	return aggregated
 }
 
 // beginHeartbeat initiates a heartbeat from the leader node to all other cluster members, if we haven't sent one out
@@ -96,9 +203,16 @@ func beginHeartbeat(ctx context.Context, s state.State, hbReq internalTypes.Hear
 		return response.SmartError(fmt.Errorf("Attempt to initiate heartbeat from non-leader"))
 	}
 
-	// Get the database record of cluster members.
+	// Get the database record of cluster members and the cluster-wide config.
 	var clusterMembers []types.ClusterMember
+	var coreConfig map[string]string
 	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		coreConfig, err = cluster.GetCoreConfig(ctx, tx)
+		if err != nil {
+			return err
+		}
+
 		dbClusterMembers, err := cluster.GetCoreClusterMembers(ctx, tx)
 		if err != nil {
 			return err
@@ -151,6 +265,17 @@ func beginHeartbeat(ctx context.Context, s state.State, hbReq internalTypes.Hear
 		return response.SmartError(err)
 	}
 
+	// Pick up any change to the cluster-wide heartbeat configuration before deciding whether a heartbeat is due.
+	heartbeatConfig, err := intState.InternalDatabase.ClusterHeartbeatConfig(coreConfig)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = applyHeartbeatConfig(s, heartbeatConfig)
+	if err != nil {
+		logger.Error("Failed to apply heartbeat configuration", logger.Ctx{"error": err})
+	}
+
 	leaderEntry := clusterMap[s.Address().URL.Host]
 	heartbeatInterval := time.Duration(intState.InternalDatabase.GetHeartbeatInterval())
 	timeSinceLast := time.Since(leaderEntry.LastHeartbeat)
@@ -162,6 +287,25 @@ func beginHeartbeat(ctx context.Context, s state.State, hbReq internalTypes.Hear
 
 	logger.Debug("Beginning new heartbeat round", logger.Ctx{"address": s.Address().URL.Host})
 
+	// Record ourselves as the leader, and notify event listeners if the leader has changed since the last heartbeat.
+	oldLeader := intState.InternalDatabase.SetLeaderAddress(s.Address().URL.Host)
+	if oldLeader != s.Address().URL.Host {
+		oldLeaderName := ""
+		for _, clusterMember := range clusterMembers {
+			if clusterMember.Address.String() == oldLeader {
+				oldLeaderName = clusterMember.Name
+			}
+		}
+
+		err = s.SendEvent(types.EventLeaderChanged, types.EventLeader{OldLeader: oldLeaderName, NewLeader: s.Name()})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Only track transitions observed while we are the leader.
+		memberStatus.reset(time.Now())
+	}
+
 	// Update local record of cluster members from the database, including any pending nodes for authentication.
 	err = s.Remotes().Replace(s.FileSystem().TrustDir, clusterMembers...)
 	if err != nil {
@@ -172,8 +316,41 @@ func beginHeartbeat(ctx context.Context, s state.State, hbReq internalTypes.Hear
 	leaderEntry.LastHeartbeat = time.Now()
 	clusterMap[s.Address().URL.Host] = leaderEntry
 
+	// Contribute our own heartbeat data, and redistribute that of the other cluster members from the previous round.
+	names := make([]string, 0, len(clusterMap))
+	for _, clusterMember := range clusterMap {
+		names = append(names, clusterMember.Name)
+	}
+
+	dataUpdates := map[string]json.RawMessage{}
+	dataUpdates[s.Name()], err = heartbeatData(ctx, s)
+	if err != nil {
+		logger.Error("Failed to get local heartbeat data", logger.Ctx{"error": err})
+	}
+
+	memberData := aggregateHeartbeatData(intState.InternalDatabase.HeartbeatData(), names, dataUpdates)
+
+	certificateUpdates := map[string][]types.CertificateExpiry{}
+	certificateUpdates[s.Name()], err = localCertificateExpiries(s)
+	if err != nil {
+		logger.Error("Failed to get local certificate expiry", logger.Ctx{"error": err})
+	}
+
+	memberCertificates := aggregateHeartbeatData(intState.InternalDatabase.MemberCertificates(), names, certificateUpdates)
+
+	// Forget about cluster members that have been removed, and record that we are alive for the other cluster members.
+	intState.FailureDetector.Retain(names)
+	intState.FailureDetector.Heartbeat(s.Name(), time.Now(), 0)
+
 	// Record the maximum schema version discovered.
-	hbInfo := internalTypes.HeartbeatInfo{ClusterMembers: clusterMap}
+	hbInfo := internalTypes.HeartbeatInfo{
+		ClusterMembers:     clusterMap,
+		LeaderAddress:      s.Address().URL.Host,
+		MemberData:         memberData,
+		MemberHealth:       intState.FailureDetector.Snapshot(),
+		HeartbeatConfig:    &heartbeatConfig,
+		MemberCertificates: memberCertificates,
+	}
 	for _, node := range clusterMembers {
 		if node.SchemaInternalVersion > hbInfo.MaxSchemaInternal {
 			hbInfo.MaxSchemaInternal = node.SchemaInternalVersion
@@ -184,13 +361,14 @@ func beginHeartbeat(ctx context.Context, s state.State, hbReq internalTypes.Hear
 		}
 	}
 
-	clusterClients, err := s.Cluster(false)
+	clusterClients, err := s.ClusterWithMaintenance(false)
 	if err != nil {
 		return response.SmartError(err)
 	}
 
-	// Use a lock to handle concurrent access to hbInfo.
+	// Use a lock to handle concurrent access to hbInfo and statusChanges.
 	mapLock := sync.RWMutex{}
+	statusChanges := []memberStatusChange{}
 	// Send heartbeat to non-leader members, updating their local member cache and updating the node.
 	// If we sent a heartbeat to this node within double the request timeout, then we can skip the node this round.
 	err = clusterClients.Query(ctx, true, func(ctx context.Context, c *client.Client) error {
@@ -210,16 +388,51 @@ func beginHeartbeat(ctx context.Context, s state.State, hbReq internalTypes.Hear
 			return nil
 		}
 
-		err := intState.InternalDatabase.SendHeartbeat(ctx, &c.Client, hbInfo)
+		sentAt := time.Now()
+		hbResp, err := intState.InternalDatabase.SendHeartbeat(ctx, &c.Client, hbInfo)
 		if err != nil {
 			logger.Error("Received error sending heartbeat to cluster member", logger.Ctx{"target": addr, "error": err})
+
+			intState.FailureDetector.Missed(currentMember.Name)
+
+			eventErr := s.SendEvent(types.EventHeartbeatMissed, types.EventMember{Name: currentMember.Name, Address: addr, Role: currentMember.Role, Error: err.Error()})
+			if eventErr != nil {
+				logger.Warn("Failed to send heartbeat event", logger.Ctx{"target": addr, "error": eventErr})
+			}
+
+			mapLock.Lock()
+			// Don't redistribute stale data from unreachable cluster members.
+			dataUpdates[currentMember.Name] = nil
+			if memberStatus.update(currentMember.Name, false) {
+				statusChanges = append(statusChanges, memberStatusChange{member: currentMember.ClusterMemberLocal, online: false})
+			}
+
+			mapLock.Unlock()
+
 			return nil
 		}
 
+		// Older cluster members don't report their heartbeat configuration.
+		if hbResp.HeartbeatConfig != nil && hbResp.HeartbeatConfig.Interval != heartbeatConfig.Interval {
+			logger.Warn("Cluster member heartbeat interval differs from the cluster-wide configuration", logger.Ctx{"target": addr, "interval": hbResp.HeartbeatConfig.Interval, "expected": heartbeatConfig.Interval})
+		}
+
+		if len(hbResp.Data) > maxHeartbeatDataSize {
+			logger.Warn("Ignoring oversized heartbeat data", logger.Ctx{"target": addr, "size": len(hbResp.Data)})
+			hbResp.Data = nil
+		}
+
 		currentMember.LastHeartbeat = time.Now()
+		intState.FailureDetector.Heartbeat(currentMember.Name, currentMember.LastHeartbeat, currentMember.LastHeartbeat.Sub(sentAt))
 
 		mapLock.Lock()
 		hbInfo.ClusterMembers[addr] = currentMember
+		dataUpdates[currentMember.Name] = hbResp.Data
+		certificateUpdates[currentMember.Name] = hbResp.Certificates
+		if memberStatus.update(currentMember.Name, true) {
+			statusChanges = append(statusChanges, memberStatusChange{member: currentMember.ClusterMemberLocal, online: true})
+		}
+
 		mapLock.Unlock()
 
 		return nil
@@ -228,8 +441,35 @@ func beginHeartbeat(ctx context.Context, s state.State, hbReq internalTypes.Hear
 		return response.SmartError(err)
 	}
 
+	// Forget about cluster members that have been removed.
+	memberStatus.retain(names)
+
+	// Record the heartbeat data collected this round, to be redistributed with the next heartbeat.
+	memberData = aggregateHeartbeatData(memberData, names, dataUpdates)
+	intState.InternalDatabase.SetHeartbeatData(memberData)
+
+	// Unreachable cluster members keep the certificate expiry they last reported.
+	memberCertificates = aggregateHeartbeatData(memberCertificates, names, certificateUpdates)
+	intState.InternalDatabase.SetMemberCertificates(memberCertificates)
+
+	err = intState.Hooks.OnHeartbeatData(ctx, s, memberData)
+	if err != nil {
+		logger.Error("Failed to run heartbeat data hook", logger.Ctx{"error": err})
+	}
+
+	// Run the hooks in the background so that slow hooks don't hold up the heartbeat.
+	if len(statusChanges) > 0 {
+		go func() {
+			for _, change := range statusChanges {
+				runMemberStatusHooks(intState.Context, s, change)
+			}
+		}()
+	}
+
 	// Having sent a heartbeat to each valid cluster member, update the database record of members.
+	var roleChanges []types.EventMember
 	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		roleChanges = []types.EventMember{}
 		dbClusterMembers, err := cluster.GetCoreClusterMembers(ctx, tx)
 		if err != nil {
 			return err
@@ -241,6 +481,10 @@ func beginHeartbeat(ctx context.Context, s state.State, hbReq internalTypes.Hear
 				continue
 			}
 
+			if clusterMember.Role != cluster.Role(heartbeatInfo.Role) {
+				roleChanges = append(roleChanges, types.EventMember{Name: clusterMember.Name, Address: clusterMember.Address, Role: heartbeatInfo.Role})
+			}
+
 			clusterMember.Heartbeat = heartbeatInfo.LastHeartbeat
 			clusterMember.Role = cluster.Role(heartbeatInfo.Role)
 			err = cluster.UpdateCoreClusterMember(ctx, tx, clusterMember.Name, clusterMember)
@@ -255,6 +499,26 @@ func beginHeartbeat(ctx context.Context, s state.State, hbReq internalTypes.Hear
 		return response.SmartError(err)
 	}
 
+	for _, roleChange := range roleChanges {
+		err = s.SendEvent(types.EventRoleChanged, roleChange)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = maybeAutoRemoveClusterMember(ctx, s, intState.AutoRemove)
+	if err != nil {
+		logger.Error("Failed to automatically remove unreachable cluster member", logger.Ctx{"error": err})
+	}
+
+	// Applying a phase of a certificate rotation involves every cluster member, so don't hold up the heartbeat.
+	err = startCertificateRotationAdvance(s)
+	if err != nil {
+		logger.Error("Failed to advance cluster certificate rotation", logger.Ctx{"error": err})
+	}
+
+	go checkCertificateExpiry(intState.Context, s)
+
 	hookCtx, hookCancel := context.WithCancel(ctx)
 	err = intState.Hooks.OnHeartbeat(hookCtx, s)
 	hookCancel()
@@ -264,3 +528,122 @@ func beginHeartbeat(ctx context.Context, s state.State, hbReq internalTypes.Hear
 
 	return response.EmptySyncResponse
 }
+
+// memberStatus records the cluster members that failed to answer the last heartbeat sent to them by this cluster member
+// while it was the leader.
+var memberStatus = &memberStatusTracker{offline: map[string]bool{}}
+
+// memberStatusTracker keeps track of which cluster members are offline, according to heartbeats.
+type memberStatusTracker struct {
+	lock    sync.Mutex
+	offline map[string]bool
+
+	// leaderSince is when this cluster member last became the leader.
+	leaderSince time.Time
+}
+
+// memberStatusChange is a cluster member going offline or coming back online.
+type memberStatusChange struct {
+	member types.ClusterMemberLocal
+	online bool
+}
+
+// update records whether the named cluster member answered a heartbeat, and returns whether that changed its status.
+// Cluster members are considered online until they first fail to answer a heartbeat.
+func (t *memberStatusTracker) update(name string, online bool) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	wasOnline := !t.offline[name]
+	if online {
+		delete(t.offline, name)
+	} else {
+		t.offline[name] = true
+	}
+
+	return wasOnline != online
+}
+
+// isOffline returns whether the named cluster member failed to answer its last heartbeat.
+func (t *memberStatusTracker) isOffline(name string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.offline[name]
+}
+
+// retain forgets the status of all cluster members not in the given list.
+func (t *memberStatusTracker) retain(names []string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for name := range t.offline {
+		if !slices.Contains(names, name) {
+			delete(t.offline, name)
+		}
+	}
+}
+
+// reset forgets the status of all cluster members, as this cluster member became the leader at the given time.
+func (t *memberStatusTracker) reset(now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.offline = map[string]bool{}
+	t.leaderSince = now
+}
+
+// leaderStart returns when this cluster member last became the leader, or zero if it never was.
+func (t *memberStatusTracker) leaderStart() time.Time {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.leaderSince
+}
+
+// runMemberStatusHooks runs the OnMemberOffline or OnMemberOnline hook for the changed cluster member locally and on
+// every other online cluster member. Errors are logged rather than returned, so that every member gets to run its hook.
+func runMemberStatusHooks(ctx context.Context, s state.State, change memberStatusChange) {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		logger.Error("Failed to run member status hooks", logger.Ctx{"error": err})
+		return
+	}
+
+	hookType := internalTypes.OnMemberOffline
+	hook := intState.Hooks.OnMemberOffline
+	if change.online {
+		hookType = internalTypes.OnMemberOnline
+		hook = intState.Hooks.OnMemberOnline
+	}
+
+	logger.Info("Cluster member status changed", logger.Ctx{"name": change.member.Name, "online": change.online})
+
+	hookCtx, hookCancel := context.WithCancel(ctx)
+	err = hook(hookCtx, s, change.member)
+	hookCancel()
+	if err != nil {
+		logger.Error("Failed to run member status hook", logger.Ctx{"hook": hookType, "member": change.member.Name, "error": err})
+	}
+
+	publicKey, err := s.ClusterCert().PublicKeyX509()
+	if err != nil {
+		logger.Error("Failed to parse cluster certificate", logger.Ctx{"error": err})
+		return
+	}
+
+	for name, remote := range s.Remotes().RemotesByName() {
+		if name == s.Name() || name == change.member.Name || memberStatus.isOffline(name) {
+			continue
+		}
+
+		c, err := internalClient.New(remote.URL(), s.ServerCert(), publicKey, false, s.AlternateClusterCerts()...)
+		if err == nil {
+			err = internalClient.RunMemberStateHook(ctx, c.UseTarget(name), hookType, internalTypes.HookMemberStateOptions{Member: change.member})
+		}
+
+		if err != nil {
+			logger.Error("Failed to run member status hook", logger.Ctx{"hook": hookType, "target": name, "member": change.member.Name, "error": err})
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package resources
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+
+	"github.com/canonical/microcluster/v2/client"
+	"github.com/canonical/microcluster/v2/cluster"
+	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// minHeartbeatInterval is the smallest configurable interval between heartbeats or dqlite role probes.
+const minHeartbeatInterval = time.Second
+
+var heartbeatConfigCmd = rest.Endpoint{
+	Path: "heartbeat",
+
+	Get: rest.EndpointAction{Handler: heartbeatConfigGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementConfigRead, Summary: "Get the heartbeat configuration", Response: types.Heartbeat{}},
+	Put: rest.EndpointAction{Handler: heartbeatConfigPut, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementConfigWrite, Summary: "Update the heartbeat configuration", Request: types.HeartbeatConfig{}},
+}
+
+// heartbeatConfigGet returns the cluster-wide heartbeat configuration, along with the configuration in effect on each
+// reachable cluster member, so that mismatches and members pending a restart can be spotted.
+func heartbeatConfigGet(s state.State, r *http.Request) response.Response {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var coreConfig map[string]string
+	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		coreConfig, err = cluster.GetCoreConfig(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	heartbeatConfig, err := intState.InternalDatabase.ClusterHeartbeatConfig(coreConfig)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	heartbeat := types.Heartbeat{
+		HeartbeatConfig: heartbeatConfig,
+		Members:         map[string]types.HeartbeatConfig{s.Name(): intState.InternalDatabase.HeartbeatConfig()},
+		Mismatched:      []string{},
+		PendingRestart:  []string{},
+	}
+
+	names := map[string]string{}
+	for name, address := range s.Remotes().Addresses() {
+		names[address.String()] = name
+	}
+
+	clusterClients, err := s.ClusterWithMaintenance(false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lock := sync.Mutex{}
+	err = clusterClients.Query(r.Context(), true, func(ctx context.Context, c *client.Client) error {
+		memberConfig, err := internalClient.GetHeartbeatConfig(ctx, &c.Client)
+		if err != nil {
+			logger.Warn("Failed to get heartbeat configuration of cluster member", logger.Ctx{"address": c.URL().URL.Host, "error": err})
+
+			return nil
+		}
+
+		lock.Lock()
+		heartbeat.Members[names[c.URL().URL.Host]] = *memberConfig
+		lock.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for name, memberConfig := range heartbeat.Members {
+		if memberConfig.Interval != heartbeatConfig.Interval {
+			heartbeat.Mismatched = append(heartbeat.Mismatched, name)
+		}
+
+		// The role probe interval can't change while dqlite is running, so it is applied when the database next starts.
+		if memberConfig.RoleProbeInterval != heartbeatConfig.RoleProbeInterval {
+			heartbeat.PendingRestart = append(heartbeat.PendingRestart, name)
+		}
+	}
+
+	sort.Strings(heartbeat.Mismatched)
+	sort.Strings(heartbeat.PendingRestart)
+
+	return response.SyncResponse(true, heartbeat)
+}
+
+// heartbeatConfigPut sets the cluster-wide heartbeat configuration. Zero intervals revert to the default.
+// Cluster members pick up the new heartbeat interval with the next heartbeat.
+func heartbeatConfigPut(s state.State, r *http.Request) response.Response {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := types.HeartbeatConfig{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	for name, interval := range map[string]time.Duration{"Heartbeat interval": req.Interval, "Role probe interval": req.RoleProbeInterval} {
+		if interval != 0 && interval < minHeartbeatInterval {
+			return response.BadRequest(fmt.Errorf("%s %q must be at least %q", name, interval, minHeartbeatInterval))
+		}
+	}
+
+	coreConfig := map[string]string{cluster.HeartbeatIntervalKey: "", cluster.RoleProbeIntervalKey: ""}
+	if req.Interval != 0 {
+		coreConfig[cluster.HeartbeatIntervalKey] = req.Interval.String()
+	}
+
+	if req.RoleProbeInterval != 0 {
+		coreConfig[cluster.RoleProbeIntervalKey] = req.RoleProbeInterval.String()
+	}
+
+	err = checkHeartbeatConfig(intState, coreConfig)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	changes, err := s.Config().Update(r.Context(), coreConfig, false, nil)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if len(changes) > 0 {
+		go runConfigChangeHooks(intState.Context, s, changes)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// checkHeartbeatConfig checks that the heartbeat interval in the given core config leaves unreachable cluster members
+// a few missed heartbeats to recover before they can be automatically removed.
+func checkHeartbeatConfig(s *internalState.InternalState, coreConfig map[string]string) error {
+	heartbeatConfig, err := s.InternalDatabase.ClusterHeartbeatConfig(coreConfig)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "%v", err)
+	}
+
+	autoRemove := s.AutoRemove.After
+	if autoRemove != 0 && autoRemove < 3*heartbeatConfig.Interval {
+		return api.StatusErrorf(http.StatusBadRequest, "Heartbeat interval %q must be at most a third of the automatic removal delay %q", heartbeatConfig.Interval, autoRemove)
+	}
+
+	return nil
+}
+
+// applyHeartbeatConfig applies the cluster-wide heartbeat configuration to the local cluster member. The heartbeat
+// interval takes effect immediately, while the role probe interval is recorded locally for when the database next starts.
+func applyHeartbeatConfig(s state.State, heartbeatConfig types.HeartbeatConfig) error {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return err
+	}
+
+	oldInterval := intState.InternalDatabase.GetHeartbeatInterval()
+	if oldInterval != heartbeatConfig.Interval {
+		logger.Info("Changing heartbeat interval", logger.Ctx{"oldInterval": oldInterval, "newInterval": heartbeatConfig.Interval})
+		intState.InternalDatabase.SetHeartbeatInterval(heartbeatConfig.Interval)
+	}
+
+	localConfig := intState.LocalConfig()
+	if localConfig.GetRoleProbeInterval() != heartbeatConfig.RoleProbeInterval {
+		localConfig.SetRoleProbeInterval(heartbeatConfig.RoleProbeInterval)
+		err = localConfig.Write()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package resources
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type heartbeatSuite struct {
+	suite.Suite
+}
+
+func TestHeartbeatSuite(t *testing.T) {
+	suite.Run(t, new(heartbeatSuite))
+}
+
+func (t *heartbeatSuite) Test_memberStatusTracker() {
+	tracker := &memberStatusTracker{offline: map[string]bool{}}
+
+	// Members start out online.
+	t.False(tracker.update("n1", true))
+	t.False(tracker.isOffline("n1"))
+
+	// Only the first missed heartbeat is a change.
+	t.True(tracker.update("n1", false))
+	t.False(tracker.update("n1", false))
+	t.True(tracker.isOffline("n1"))
+
+	t.True(tracker.update("n1", true))
+	t.False(tracker.isOffline("n1"))
+
+	// A member that misses its first heartbeat goes offline.
+	t.True(tracker.update("n2", false))
+	t.True(tracker.update("n3", false))
+
+	tracker.retain([]string{"n1", "n3"})
+	t.False(tracker.isOffline("n2"))
+	t.True(tracker.isOffline("n3"))
+
+	t.True(tracker.leaderStart().IsZero())
+	now := time.Now()
+	tracker.reset(now)
+	t.False(tracker.isOffline("n3"))
+	t.Equal(now, tracker.leaderStart())
+}
+
+func (t *heartbeatSuite) Test_aggregateHeartbeatData() {
+	previous := map[string]json.RawMessage{
+		"n1":      json.RawMessage(`{"load":1}`),
+		"n2":      json.RawMessage(`{"load":2}`),
+		"n3":      json.RawMessage(`{"load":3}`),
+		"removed": json.RawMessage(`{"load":4}`),
+	}
+
+	updates := map[string]json.RawMessage{
+		"n1": json.RawMessage(`{"load":5}`),
+		"n2": nil,
+		"n4": json.RawMessage(`{"load":6}`),
+	}
+
+	aggregated := aggregateHeartbeatData(previous, []string{"n1", "n2", "n3", "n4", "n5"}, updates)
+
+	// Updated data replaces the previous round, unreachable and removed members are dropped, and the rest is kept.
+	t.Equal(map[string]json.RawMessage{
+		"n1": json.RawMessage(`{"load":5}`),
+		"n3": json.RawMessage(`{"load":3}`),
+		"n4": json.RawMessage(`{"load":6}`),
+	}, aggregated)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/gorilla/mux"
 
+	"github.com/canonical/microcluster/v2/internal/db"
 	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
 	internalState "github.com/canonical/microcluster/v2/internal/state"
 	"github.com/canonical/microcluster/v2/rest"
@@ -21,7 +22,7 @@ import (
 var hooksCmd = rest.Endpoint{
 	Path: "hooks/{hookType}",
 
-	Post: rest.EndpointAction{Handler: hooksPost, AccessHandler: access.AllowAuthenticated, ProxyTarget: true},
+	Post: rest.EndpointAction{Handler: hooksPost, AccessHandler: access.AllowClusterMembers, ProxyTarget: true, Summary: "Run a hook on the cluster member"},
 }
 
 func hooksPost(s state.State, r *http.Request) response.Response {
@@ -61,7 +62,27 @@ func hooksPost(s state.State, r *http.Request) response.Response {
 		if err != nil {
 			return response.SmartError(fmt.Errorf("Failed to execute post-remove hook on cluster member %q: %w", s.Name(), err))
 		}
+	case internalTypes.PreEvacuate:
+		err = intState.Hooks.PreEvacuate(ctx, s)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed to execute pre-evacuate hook on cluster member %q: %w", s.Name(), err))
+		}
+
+		// Make dqlite prefer other cluster members when it next needs to promote one.
+		err = intState.InternalDatabase.SetWeight(ctx, db.MaintenanceWeight)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	case internalTypes.PostRestore:
+		err = intState.InternalDatabase.SetWeight(ctx, 0)
+		if err != nil {
+			return response.SmartError(err)
+		}
 
+		err = intState.Hooks.PostRestore(ctx, s)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed to execute post-restore hook on cluster member %q: %w", s.Name(), err))
+		}
 	case internalTypes.OnNewMember:
 		var req internalTypes.HookNewMemberOptions
 		err = json.NewDecoder(r.Body).Decode(&req)
@@ -77,6 +98,26 @@ func hooksPost(s state.State, r *http.Request) response.Response {
 		if err != nil {
 			return response.SmartError(fmt.Errorf("Failed to run hook after system %q has joined the cluster: %w", req.NewMember.Name, err))
 		}
+	case internalTypes.OnMemberOffline, internalTypes.OnMemberOnline:
+		var req internalTypes.HookMemberStateOptions
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		if req.Member == (types.ClusterMemberLocal{}) {
+			return response.SmartError(fmt.Errorf("No member given for %q hook execution", hookTypeStr))
+		}
+
+		hook := intState.Hooks.OnMemberOffline
+		if internalTypes.HookType(hookTypeStr) == internalTypes.OnMemberOnline {
+			hook = intState.Hooks.OnMemberOnline
+		}
+
+		err = hook(ctx, s, req.Member)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed to run %q hook for cluster member %q: %w", hookTypeStr, req.Member.Name, err))
+		}
 	case internalTypes.OnDaemonConfigUpdate:
 		var req types.DaemonConfig
 		err = json.NewDecoder(r.Body).Decode(&req)
@@ -88,6 +129,17 @@ func hooksPost(s state.State, r *http.Request) response.Response {
 		if err != nil {
 			return response.SmartError(fmt.Errorf("Failed to run hook on %q after daemon received local config update: %w", s.Name(), err))
 		}
+	case internalTypes.OnConfigChange:
+		var req internalTypes.HookConfigChangeOptions
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		err = intState.Hooks.OnConfigChange(ctx, s, req.Changes)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed to run hook on %q after cluster config change: %w", s.Name(), err))
+		}
 	default:
 		return response.SmartError(fmt.Errorf("No valid hook found for the given type"))
 	}
@@ -0,0 +1,65 @@
+package resources
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+var leasesCmd = rest.Endpoint{
+	Path: "leases",
+
+	Get: rest.EndpointAction{Handler: leasesGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementLeasesRead, Summary: "List the leases", Response: []types.Lease{}},
+}
+
+var leaseCmd = rest.Endpoint{
+	Path: "leases/{name}",
+
+	Get:    rest.EndpointAction{Handler: leaseGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementLeasesRead, Summary: "Get a lease", Response: types.Lease{}},
+	Delete: rest.EndpointAction{Handler: leaseDelete, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementLeasesWrite, Summary: "Break a lease"},
+}
+
+func leasesGet(s state.State, r *http.Request) response.Response {
+	leases, err := s.Leases().List(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, leases)
+}
+
+func leaseGet(s state.State, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lease, err := s.Leases().Get(r.Context(), name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, lease)
+}
+
+// leaseDelete forcibly breaks the lease, so that it can be acquired by another cluster member.
+func leaseDelete(s state.State, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.Leases().Break(r.Context(), name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
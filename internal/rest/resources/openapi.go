@@ -0,0 +1,74 @@
+package resources
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/response"
+
+	"github.com/canonical/microcluster/v2/internal/endpoints"
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/openapi"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+var openAPICmd = rest.Endpoint{
+	AllowedBeforeInit: true,
+	Path:              "openapi",
+
+	Get: rest.EndpointAction{Handler: openAPIGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementDaemonRead, Summary: "Get the OpenAPI document of a listener", Response: openapi.Document{}},
+}
+
+// openAPIGet returns the OpenAPI document describing the resources served by the listener given with ?listener=,
+// or by the core listener if none is given.
+func openAPIGet(s state.State, r *http.Request) response.Response {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	listener := r.URL.Query().Get("listener")
+	if listener == "" {
+		listener = endpoints.EndpointsCore
+	}
+
+	doc, err := intState.OpenAPI(listener)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, doc)
+}
+
+// OpenAPIDocumentEndpoint returns the endpoint serving the OpenAPI document of the named listener at the well-known
+// /openapi.json path of that listener. Requests are authenticated like those to the openapi endpoint.
+func OpenAPIDocumentEndpoint(listener string) rest.Endpoint {
+	return rest.Endpoint{
+		AllowedBeforeInit: true,
+		Path:              "openapi.json",
+
+		Get: rest.EndpointAction{
+			Handler: func(s state.State, r *http.Request) response.Response {
+				intState, err := internalState.ToInternal(s)
+				if err != nil {
+					return response.SmartError(err)
+				}
+
+				doc, err := intState.OpenAPI(listener)
+				if err != nil {
+					return response.SmartError(err)
+				}
+
+				// Serve the document itself rather than wrapped in a sync response, as expected by OpenAPI tooling.
+				return response.ManualResponse(func(w http.ResponseWriter) error {
+					return json.NewEncoder(w).Encode(doc)
+				})
+			},
+			AccessHandler: access.AllowAuthenticated,
+			Entitlement:   types.EntitlementDaemonRead,
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package resources
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/openapi"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type openAPISuite struct {
+	suite.Suite
+}
+
+func TestOpenAPISuite(t *testing.T) {
+	suite.Run(t, new(openAPISuite))
+}
+
+func (t *openAPISuite) Test_coreEndpoints() {
+	doc := rest.OpenAPI(openapi.Info{Title: "microcluster", Version: "test"}, UnixEndpoints, InternalEndpoints, PublicEndpoints)
+
+	// Every core endpoint action is described.
+	for urlPath, item := range doc.Paths {
+		for _, op := range []*openapi.Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+			if op != nil {
+				t.NotEmpty(op.Summary, "Operation %q on %q has no summary", op.OperationID, urlPath)
+			}
+		}
+	}
+
+	// Every trusted action of the public API and control socket declares the entitlement it requires.
+	doc = rest.OpenAPI(openapi.Info{Title: "microcluster", Version: "test"}, UnixEndpoints, PublicEndpoints)
+	for urlPath, item := range doc.Paths {
+		for _, op := range []*openapi.Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+			if op != nil && op.Security == nil {
+				t.NotContains([]string{string(types.EntitlementAPIRead), string(types.EntitlementAPIWrite)}, op.Entitlement, "Operation %q on %q has no entitlement", op.OperationID, urlPath)
+			}
+		}
+	}
+
+	members := doc.Paths["/core/1.0/cluster"]
+	t.Require().NotNil(members)
+	t.Equal("#/components/schemas/ClusterMember", members.Get.Responses["200"].Content["application/json"].Schema.AllOf[1].Properties["metadata"].Items.Ref)
+
+	_, err := json.Marshal(doc)
+	t.NoError(err)
+}
+
+func (t *openAPISuite) Test_OpenAPIDocumentEndpoint() {
+	e := OpenAPIDocumentEndpoint("core")
+	t.Equal("openapi.json", e.Path)
+
+	// The document is only served to trusted clients with the same entitlement as the openapi endpoint.
+	t.False(e.Get.AllowUntrusted)
+	t.NotNil(e.Get.AccessHandler)
+	t.Equal(openAPICmd.Get.Entitlement, e.Get.Entitlement)
+}
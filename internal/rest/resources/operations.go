@@ -0,0 +1,145 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/microcluster/v2/internal/operations"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+var operationsCmd = rest.Endpoint{
+	Path:              "operations",
+	AllowedBeforeInit: true,
+
+	Get: rest.EndpointAction{Handler: operationsGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementOperationsRead, ProxyTarget: true, Summary: "List the operations", Response: []types.Operation{}},
+}
+
+var operationCmd = rest.Endpoint{
+	Path:              "operations/{id}",
+	AllowedBeforeInit: true,
+
+	Get:    rest.EndpointAction{Handler: operationGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementOperationsRead, ProxyTarget: true, Summary: "Get an operation", Response: types.Operation{}},
+	Delete: rest.EndpointAction{Handler: operationDelete, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementOperationsWrite, ProxyTarget: true, Summary: "Cancel an operation"},
+}
+
+var operationWaitCmd = rest.Endpoint{
+	Path:              "operations/{id}/wait",
+	AllowedBeforeInit: true,
+
+	Get: rest.EndpointAction{Handler: operationWaitGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementOperationsRead, ProxyTarget: true, Summary: "Wait for an operation to finish", Response: types.Operation{}},
+}
+
+func operationsGet(s state.State, r *http.Request) response.Response {
+	ops := s.Operations().List()
+	apiOps := make([]types.Operation, 0, len(ops))
+	for _, op := range ops {
+		apiOps = append(apiOps, op.ToAPI())
+	}
+
+	return response.SyncResponse(true, apiOps)
+}
+
+func operationGet(s state.State, r *http.Request) response.Response {
+	op, err := operationFromRequest(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return operationResultResponse(op)
+}
+
+func operationDelete(s state.State, r *http.Request) response.Response {
+	op, err := operationFromRequest(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = op.Cancel()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// operationWaitGet blocks until the operation finishes, or until the number of seconds in the "timeout" query parameter
+// has passed. A negative or missing timeout waits until the operation finishes or the client disconnects.
+func operationWaitGet(s state.State, r *http.Request) response.Response {
+	op, err := operationFromRequest(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	timeout := -1
+	timeoutParam := r.URL.Query().Get("timeout")
+	if timeoutParam != "" {
+		timeout, err = strconv.Atoi(timeoutParam)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid timeout %q: %w", timeoutParam, err))
+		}
+	}
+
+	ctx := r.Context()
+	if timeout >= 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+
+	err = op.Wait(ctx)
+	if err != nil && r.Context().Err() != nil {
+		return response.SmartError(err)
+	}
+
+	return operationResultResponse(op)
+}
+
+// operationFromRequest returns the operation matching the ID in the request path.
+func operationFromRequest(s state.State, r *http.Request) (*operations.Operation, error) {
+	id, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Operations().Get(id)
+}
+
+// operationResultResponse returns the current state of the operation. If the operation has finished,
+// it is marked as delivered once the response has been sent.
+func operationResultResponse(op *operations.Operation) response.Response {
+	apiOp := op.ToAPI()
+	if !apiOp.Status.IsFinal() {
+		return response.SyncResponse(true, apiOp)
+	}
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		err := response.SyncResponse(true, apiOp).Render(w)
+		if err != nil {
+			return err
+		}
+
+		f, ok := w.(http.Flusher)
+		if !ok {
+			return fmt.Errorf("ResponseWriter is not type http.Flusher")
+		}
+
+		f.Flush()
+
+		// The final status is recorded just before the operation is marked as done.
+		<-op.Done()
+		op.MarkDelivered()
+
+		return nil
+	})
+}
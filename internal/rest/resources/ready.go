@@ -9,6 +9,7 @@ import (
 	internalState "github.com/canonical/microcluster/v2/internal/state"
 	"github.com/canonical/microcluster/v2/rest"
 	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
 	"github.com/canonical/microcluster/v2/state"
 )
 
@@ -16,7 +17,7 @@ var readyCmd = rest.Endpoint{
 	AllowedBeforeInit: true,
 	Path:              "ready",
 
-	Get: rest.EndpointAction{Handler: getWaitReady, AccessHandler: access.AllowAuthenticated},
+	Get: rest.EndpointAction{Handler: getWaitReady, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementDaemonRead, Summary: "Wait for the daemon to be ready"},
 }
 
 func getWaitReady(state state.State, r *http.Request) response.Response {
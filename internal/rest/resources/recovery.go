@@ -0,0 +1,38 @@
+package resources
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcluster/v2/internal/recover"
+	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
+	"github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+)
+
+// recoveryCmd accepts the quorum-loss recovery tarball from another cluster member.
+// It is available while the database is offline, as a cluster that has lost quorum cannot open it.
+var recoveryCmd = rest.Endpoint{
+	AllowedBeforeInit: true,
+	Path:              "recovery",
+
+	Post: rest.EndpointAction{Handler: recoveryPost, AccessHandler: access.AllowClusterMembers, Summary: "Receive a recovery tarball", Response: internalTypes.RecoveryTarballReceipt{}},
+}
+
+func recoveryPost(s state.State, r *http.Request) response.Response {
+	// Refuse to replace a working database.
+	if s.Database().IsOpen(r.Context()) == nil {
+		return response.SmartError(api.StatusErrorf(http.StatusConflict, "Cannot accept a recovery tarball while the database is online"))
+	}
+
+	dqliteID, err := recover.StageRecoveryTarball(s.FileSystem(), s.Name(), r.Body)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed to stage recovery tarball: %w", err))
+	}
+
+	return response.SyncResponse(true, internalTypes.RecoveryTarballReceipt{Name: s.Name(), DqliteID: dqliteID})
+}
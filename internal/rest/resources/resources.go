@@ -17,6 +17,7 @@ var UnixEndpoints = rest.Resources{
 		controlCmd,
 		shutdownCmd,
 		tokensCmd,
+		openAPICmd,
 	},
 }
 
@@ -26,9 +27,23 @@ var PublicEndpoints = rest.Resources{
 	Endpoints: []rest.Endpoint{
 		api10Cmd,
 		clusterCertificatesCmd,
+		certificateRotationCmd,
+		clientCertificatesCmd,
+		clientCertificateCmd,
 		clusterCmd,
 		clusterMemberCmd,
+		clusterMemberStateCmd,
 		daemonCmd,
+		heartbeatConfigCmd,
+		configCmd,
+		authPolicyCmd,
+		leasesCmd,
+		leaseCmd,
+		eventsCmd,
+		operationsCmd,
+		operationCmd,
+		operationWaitCmd,
+		tasksCmd,
 		tokenCmd,
 		readyCmd,
 	},
@@ -41,11 +56,17 @@ var InternalEndpoints = rest.Resources{
 		clusterInternalCmd,
 		clusterMemberInternalCmd,
 		databaseCmd,
+		databaseBackupCmd,
+		databaseRestoreCmd,
+		recoveryCmd,
 		sqlCmd,
 		heartbeatCmd,
+		eventsInternalCmd,
+		taskInternalCmd,
 		trustCmd,
 		trustEntryCmd,
 		hooksCmd,
+		certificateRotationInternalCmd,
 	},
 }
 
@@ -0,0 +1,119 @@
+package resources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/internal/db"
+	internalAccess "github.com/canonical/microcluster/v2/internal/rest/access"
+	"github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type resourcesSuite struct {
+	suite.Suite
+}
+
+func TestResourcesSuite(t *testing.T) {
+	suite.Run(t, new(resourcesSuite))
+}
+
+// readyDB is a database that always reports itself as ready.
+type readyDB struct {
+	db.DB
+}
+
+func (readyDB) Status() types.DatabaseStatus {
+	return types.DatabaseReady
+}
+
+// readyState is a state whose database is ready.
+type readyState struct {
+	*state.InternalState
+}
+
+func (readyState) Database() db.DB {
+	return readyDB{}
+}
+
+func (t *resourcesSuite) Test_internalEndpointsClusterMembersOnly() {
+	allowClusterMembers := reflect.ValueOf(access.AllowClusterMembers).Pointer()
+	for _, e := range InternalEndpoints.Endpoints {
+		for method, action := range map[string]struct {
+			allowUntrusted bool
+			handler        any
+			accessHandler  any
+		}{
+			http.MethodGet:    {e.Get.AllowUntrusted, e.Get.Handler, e.Get.AccessHandler},
+			http.MethodPut:    {e.Put.AllowUntrusted, e.Put.Handler, e.Put.AccessHandler},
+			http.MethodPost:   {e.Post.AllowUntrusted, e.Post.Handler, e.Post.AccessHandler},
+			http.MethodPatch:  {e.Patch.AllowUntrusted, e.Patch.Handler, e.Patch.AccessHandler},
+			http.MethodDelete: {e.Delete.AllowUntrusted, e.Delete.Handler, e.Delete.AccessHandler},
+		} {
+			if reflect.ValueOf(action.handler).IsNil() || action.allowUntrusted {
+				continue
+			}
+
+			t.Require().False(reflect.ValueOf(action.accessHandler).IsNil(), "%s %q has no access handler", method, e.Path)
+			t.Equal(allowClusterMembers, reflect.ValueOf(action.accessHandler).Pointer(), "%s %q is not restricted to cluster members", method, e.Path)
+		}
+	}
+}
+
+func (t *resourcesSuite) Test_AllowClusterMembers() {
+	tests := []struct {
+		name     string
+		trusted  bool
+		identity *types.Identity
+		allowed  bool
+	}{
+		{
+			name:     "Cluster member",
+			trusted:  true,
+			identity: &types.Identity{Type: types.IdentityTypeClusterMember, Name: "n1"},
+			allowed:  true,
+		},
+		{
+			name:     "Unix socket",
+			trusted:  true,
+			identity: &types.Identity{Type: types.IdentityTypeUnix, Name: "uid:1000", UID: 1000},
+			allowed:  true,
+		},
+		{
+			name:     "Trusted client certificate",
+			trusted:  true,
+			identity: &types.Identity{Type: types.IdentityTypeCertificate, Name: "abcd"},
+			allowed:  false,
+		},
+		{
+			name:     "Untrusted cluster member",
+			trusted:  false,
+			identity: &types.Identity{Type: types.IdentityTypeClusterMember, Name: "n1"},
+			allowed:  false,
+		},
+	}
+
+	for i, c := range tests {
+		t.T().Logf("%s (case %d)", c.name, i)
+
+		r := internalAccess.SetRequestIdentity(httptest.NewRequest(http.MethodGet, "/core/internal/sql", nil), c.trusted, c.identity)
+		allowed, resp := access.AllowClusterMembers(readyState{InternalState: &state.InternalState{}}, r)
+		t.Equal(c.allowed, allowed)
+		if c.allowed {
+			t.Nil(resp)
+		} else {
+			t.NotNil(resp)
+		}
+	}
+
+	// Before the daemon is part of a cluster, every authenticated client is allowed.
+	r := internalAccess.SetRequestIdentity(httptest.NewRequest(http.MethodGet, "/core/internal/sql", nil), true, &types.Identity{Type: types.IdentityTypeCertificate, Name: "abcd"})
+	allowed, resp := access.AllowClusterMembers(&state.InternalState{}, r)
+	t.True(allowed)
+	t.Nil(resp)
+}
@@ -17,7 +17,7 @@ var shutdownCmd = rest.Endpoint{
 	AllowedBeforeInit: true,
 	Path:              "shutdown",
 
-	Post: rest.EndpointAction{Handler: shutdownPost, AccessHandler: access.AllowAuthenticated},
+	Post: rest.EndpointAction{Handler: shutdownPost, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementDaemonWrite, Summary: "Shut down the daemon"},
 }
 
 func shutdownPost(state state.State, r *http.Request) response.Response {
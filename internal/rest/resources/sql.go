@@ -10,21 +10,22 @@ import (
 	"strings"
 	"time"
 
-	"github.com/canonical/lxd/lxd/db/query"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/shared/logger"
 
+	"github.com/canonical/microcluster/v2/internal/recover"
 	"github.com/canonical/microcluster/v2/internal/rest/types"
 	"github.com/canonical/microcluster/v2/internal/state"
 	"github.com/canonical/microcluster/v2/rest"
 	"github.com/canonical/microcluster/v2/rest/access"
+	apiTypes "github.com/canonical/microcluster/v2/rest/types"
 )
 
 var sqlCmd = rest.Endpoint{
 	Path: "sql",
 
-	Get:  rest.EndpointAction{Handler: sqlGet, AccessHandler: access.AllowAuthenticated},
-	Post: rest.EndpointAction{Handler: sqlPost, AccessHandler: access.AllowAuthenticated},
+	Get:  rest.EndpointAction{Handler: sqlGet, AccessHandler: access.AllowClusterMembers, Entitlement: apiTypes.EntitlementDatabaseRead, Summary: "Dump the database", Response: types.SQLDump{}},
+	Post: rest.EndpointAction{Handler: sqlPost, AccessHandler: access.AllowClusterMembers, Entitlement: apiTypes.EntitlementDatabaseWrite, Summary: "Run SQL queries against the database", Request: types.SQLQuery{}, Response: types.SQLBatch{}},
 }
 
 // Perform a database dump.
@@ -37,9 +38,10 @@ func sqlGet(state state.State, r *http.Request) response.Response {
 		schemaOnly = 0
 	}
 
-	var dump string
+	// Secrets, like join tokens and the private key of a cluster certificate rotation, are left out of the dump.
+	var dump strings.Builder
 	err = state.Database().Transaction(parentCtx, func(ctx context.Context, tx *sql.Tx) error {
-		dump, err = query.Dump(ctx, tx, schemaOnly == 1)
+		err := recover.DumpDatabase(ctx, tx, &dump, schemaOnly == 1)
 		if err != nil {
 			return fmt.Errorf("Failed dump database: %w", err)
 		}
@@ -50,7 +52,7 @@ func sqlGet(state state.State, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	return response.SyncResponse(true, types.SQLDump{Text: dump})
+	return response.SyncResponse(true, types.SQLDump{Text: dump.String()})
 }
 
 // Execute queries.
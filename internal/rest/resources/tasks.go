@@ -0,0 +1,57 @@
+package resources
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/gorilla/mux"
+
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+var tasksCmd = rest.Endpoint{
+	Path: "tasks",
+
+	Get: rest.EndpointAction{Handler: tasksGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementOperationsRead, ProxyTarget: true, Summary: "List the scheduled tasks", Response: []types.TaskStatus{}},
+}
+
+var taskInternalCmd = rest.Endpoint{
+	Path: "tasks/{name}",
+
+	Post: rest.EndpointAction{Handler: taskPost, AccessHandler: access.AllowClusterMembers, Summary: "Run a scheduled task on the cluster member"},
+}
+
+// tasksGet returns the status of the scheduled tasks on this cluster member.
+func tasksGet(s state.State, r *http.Request) response.Response {
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, intState.TaskStatus())
+}
+
+// taskPost runs the named task on this cluster member when selected by the leader, and responds once the run has finished.
+func taskPost(s state.State, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = intState.RunTask(r.Context(), name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
@@ -24,14 +24,14 @@ import (
 var tokensCmd = rest.Endpoint{
 	Path: "tokens",
 
-	Post: rest.EndpointAction{Handler: tokensPost, AccessHandler: access.AllowAuthenticated},
-	Get:  rest.EndpointAction{Handler: tokensGet, AccessHandler: access.AllowAuthenticated},
+	Post: rest.EndpointAction{Handler: tokensPost, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementTokensWrite, Summary: "Create a join token", Request: internalTypes.TokenRequest{}, Response: ""},
+	Get:  rest.EndpointAction{Handler: tokensGet, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementTokensRead, Summary: "List the join tokens", Response: []internalTypes.TokenRecord{}},
 }
 
 var tokenCmd = rest.Endpoint{
 	Path: "tokens/{name}",
 
-	Delete: rest.EndpointAction{Handler: tokenDelete, AccessHandler: access.AllowAuthenticated},
+	Delete: rest.EndpointAction{Handler: tokenDelete, AccessHandler: access.AllowAuthenticated, Entitlement: types.EntitlementTokensWrite, Summary: "Revoke a join token"},
 }
 
 func tokensPost(state state.State, r *http.Request) response.Response {
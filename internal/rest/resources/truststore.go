@@ -24,14 +24,15 @@ var trustCmd = rest.Endpoint{
 	Path:              "truststore",
 	AllowedBeforeInit: true,
 
-	Post: rest.EndpointAction{Handler: trustPost, AccessHandler: access.AllowAuthenticated},
+	Post: rest.EndpointAction{Handler: trustPost, AccessHandler: access.AllowClusterMembers, Summary: "Add a cluster member to the truststore", Request: types.ClusterMemberLocal{}},
 }
 
 var trustEntryCmd = rest.Endpoint{
 	Path:              "truststore/{name}",
 	AllowedBeforeInit: true,
 
-	Delete: rest.EndpointAction{Handler: trustDelete, AccessHandler: access.AllowAuthenticated},
+	Put:    rest.EndpointAction{Handler: trustPut, AccessHandler: access.AllowClusterMembers, Summary: "Replace the certificate of a cluster member in the truststore", Request: types.ClusterMemberLocal{}},
+	Delete: rest.EndpointAction{Handler: trustDelete, AccessHandler: access.AllowClusterMembers, Summary: "Remove a cluster member from the truststore"},
 }
 
 func trustPost(s state.State, r *http.Request) response.Response {
@@ -52,7 +53,7 @@ func trustPost(s state.State, r *http.Request) response.Response {
 	defer cancel()
 
 	if !client.IsNotification(r) {
-		cluster, err := s.Cluster(true)
+		cluster, err := s.ClusterWithMaintenance(true)
 		if err != nil {
 			return response.SmartError(err)
 		}
@@ -83,6 +84,106 @@ func trustPost(s state.State, r *http.Request) response.Response {
 	return response.EmptySyncResponse
 }
 
+func trustPut(s state.State, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := types.ClusterMemberLocal{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Name != name {
+		return response.BadRequest(fmt.Errorf("Truststore entry name %q does not match %q", req.Name, name))
+	}
+
+	if req.Certificate.Certificate == nil {
+		return response.BadRequest(fmt.Errorf("Truststore entry for node with name %q has no certificate", name))
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	remotes := s.Remotes()
+	remotesMap := remotes.RemotesByName()
+	nodeToUpdate, ok := remotesMap[name]
+	if !ok {
+		return response.SmartError(fmt.Errorf("No truststore entry found for node with name %q", name))
+	}
+
+	if !client.IsNotification(r) {
+		cluster, err := s.ClusterWithMaintenance(true)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = cluster.Query(ctx, true, func(ctx context.Context, c *client.Client) error {
+			// No need to send a request to ourselves, or to the node we are updating.
+			if s.Address().URL.Host == c.URL().URL.Host || nodeToUpdate.URL().URL.Host == c.URL().URL.Host {
+				return nil
+			}
+
+			return internalClient.UpdateTrustStoreEntry(ctx, &c.Client, req)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	newRemotes := make([]types.ClusterMember, 0, len(remotesMap))
+	for _, remote := range remotesMap {
+		newRemote := types.ClusterMember{
+			ClusterMemberLocal: types.ClusterMemberLocal{
+				Name:        remote.Name,
+				Address:     remote.Address,
+				Certificate: remote.Certificate,
+			},
+		}
+
+		if remote.Name == name {
+			newRemote.Certificate = req.Certificate
+		}
+
+		newRemotes = append(newRemotes, newRemote)
+	}
+
+	err = remotes.Replace(s.FileSystem().TrustDir, newRemotes...)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed to update truststore entry for node with name %q: %w", name, err))
+	}
+
+	// The previous certificate of the cluster member is trusted until it authenticates with the new one.
+	if sentWithCertificate(s, r, name, req.Certificate) {
+		err = remotes.ForgetPreviousCertificate(s.FileSystem().TrustDir, name)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed to update truststore entry for node with name %q: %w", name, err))
+		}
+	}
+
+	return response.EmptySyncResponse
+}
+
+// sentWithCertificate returns whether the request was sent by the named cluster member, authenticated with the given
+// certificate.
+func sentWithCertificate(s state.State, r *http.Request, name string, cert types.X509Certificate) bool {
+	if r.RemoteAddr == "@" {
+		if name != s.Name() {
+			return false
+		}
+
+		serverCert, err := s.ServerCert().PublicKeyX509()
+
+		return err == nil && serverCert.Equal(cert.Certificate)
+	}
+
+	return r.TLS != nil && len(r.TLS.PeerCertificates) > 0 && r.TLS.PeerCertificates[0].Equal(cert.Certificate)
+}
+
 func trustDelete(s state.State, r *http.Request) response.Response {
 	name, err := url.PathUnescape(mux.Vars(r)["name"])
 	if err != nil {
@@ -99,7 +200,7 @@ func trustDelete(s state.State, r *http.Request) response.Response {
 	}
 
 	if !client.IsNotification(r) {
-		cluster, err := s.Cluster(true)
+		cluster, err := s.ClusterWithMaintenance(true)
 		if err != nil {
 			return response.SmartError(err)
 		}
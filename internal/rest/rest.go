@@ -3,7 +3,6 @@ package rest
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -16,11 +15,13 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/operations"
 	internalAccess "github.com/canonical/microcluster/v2/internal/rest/access"
 	"github.com/canonical/microcluster/v2/internal/rest/client"
 	internalState "github.com/canonical/microcluster/v2/internal/state"
 	"github.com/canonical/microcluster/v2/rest"
 	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/rest/types"
 	"github.com/canonical/microcluster/v2/state"
 )
 
@@ -41,6 +42,14 @@ func handleAPIRequest(action rest.EndpointAction, state state.State, w http.Resp
 		}
 	}
 
+	// Clients must be granted the entitlement required by the action, unless it is allowed for untrusted clients.
+	if !action.AllowUntrusted {
+		err := access.CheckPermission(state, r, action.RequiredEntitlement(r.Method))
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	// Run the custom access handler if set.
 	if action.AccessHandler != nil {
 		trusted, resp := action.AccessHandler(state, r)
@@ -106,7 +115,7 @@ func proxyTarget(action rest.EndpointAction, s state.State, r *http.Request) res
 		return response.InternalError(fmt.Errorf("Failed to parse cluster certificate for request: %w", err))
 	}
 
-	client, err := client.New(*targetURL, s.ServerCert(), clusterCert, false)
+	client, err := client.New(*targetURL, s.ServerCert(), clusterCert, false, s.AlternateClusterCerts()...)
 	if err != nil {
 		return response.InternalError(fmt.Errorf("Failed to get a client for the target %q at address %q: %w", target, targetURL.String(), err))
 	}
@@ -123,6 +132,17 @@ func proxyTarget(action rest.EndpointAction, s state.State, r *http.Request) res
 		return response.SmartError(fmt.Errorf("Failed to send request to target %q: %w", target, err))
 	}
 
+	// If the target started an operation, point the client at it through this cluster member.
+	if resp.Type == api.AsyncResponse {
+		op := types.Operation{}
+		err = resp.MetadataAsStruct(&op)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed to parse operation from target %q: %w", target, err))
+		}
+
+		return operations.ForwardedOperationResponse(target, op)
+	}
+
 	return response.SyncResponse(true, resp.Metadata)
 }
 
@@ -141,6 +161,18 @@ func handleDatabaseRequest(action rest.EndpointAction, state state.State, w http
 		return response.Forbidden(nil)
 	}
 
+	// Run the custom access handler if set.
+	if action.AccessHandler != nil {
+		trusted, resp := action.AccessHandler(state, r)
+		if !trusted {
+			if resp == nil {
+				return response.Forbidden(nil)
+			}
+
+			return resp
+		}
+	}
+
 	if action.Handler == nil {
 		return response.NotImplemented(nil)
 	}
@@ -168,88 +200,65 @@ func handleDatabaseRequest(action rest.EndpointAction, state state.State, w http
 	return action.Handler(state, r)
 }
 
-// HandleEndpoint adds the endpoint to the mux router. A function variable is used to implement common logic
-// before calling the endpoint action handler associated with the request method, if it exists.
+// dispatchEndpoint returns the innermost handler of the middleware chain of the endpoint, which passes the request to the
+// endpoint action associated with the request method. The dispatched flag is set once the handler is called.
+func dispatchEndpoint(e rest.Endpoint, handleRequest func(rest.EndpointAction, state.State, http.ResponseWriter, *http.Request) response.Response, w http.ResponseWriter, dispatched *bool) rest.Handler {
+	return func(s state.State, r *http.Request) response.Response {
+		*dispatched = true
+
+		switch r.Method {
+		case "GET":
+			return handleRequest(e.Get, s, w, r)
+		case "PUT":
+			return handleRequest(e.Put, s, w, r)
+		case "POST":
+			return handleRequest(e.Post, s, w, r)
+		case "DELETE":
+			return handleRequest(e.Delete, s, w, r)
+		case "PATCH":
+			return handleRequest(e.Patch, s, w, r)
+		default:
+			return response.NotFound(fmt.Errorf("Method '%s' not found", r.Method))
+		}
+	}
+}
+
+// HandleEndpoint adds the endpoint to the mux router. Requests are passed through the built-in middlewares and those
+// of the endpoint before calling the endpoint action handler associated with the request method, if it exists.
 func HandleEndpoint(state state.State, mux *mux.Router, version string, e rest.Endpoint) {
 	url := "/" + version
 	if e.Path != "" {
 		url = filepath.Join(url, e.Path)
 	}
 
+	// If the request is a database request, the connection should be hijacked.
+	handleRequest := handleAPIRequest
+	if e.Path == "database" {
+		handleRequest = handleDatabaseRequest
+	}
+
 	route := mux.HandleFunc(url, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
-		// Actually process the request.
-		var resp response.Response
+		// Whether the request reached the endpoint action, past all middlewares.
+		var dispatched bool
+		dispatch := dispatchEndpoint(e, handleRequest, w, &dispatched)
 
-		intState, err := internalState.ToInternal(state)
-		if err != nil {
-			err := response.BadRequest(err).Render(w)
-			if err != nil {
-				logger.Error("Failed to write HTTP response", logger.Ctx{"url": r.URL, "err": err})
-			}
-
-			return
-		}
-
-		// Return Unavailable Error (503) if daemon is shutting down, except for endpoints with AllowedDuringShutdown.
-		if intState.Context.Err() == context.Canceled && !e.AllowedDuringShutdown {
-			err := response.Unavailable(fmt.Errorf("Daemon is shutting down")).Render(w)
-			if err != nil {
-				logger.Error("Failed to write HTTP response", logger.Ctx{"url": r.URL, "err": err})
-			}
+		// Actually process the request.
+		middlewares := append(rest.DefaultMiddlewares(e), e.Middlewares...)
+		resp := rest.Chain(dispatch, middlewares...)(state, r)
 
+		// Database requests that reached the handler have had their connection hijacked.
+		if e.Path == "database" && dispatched {
 			return
 		}
 
-		if !e.AllowedBeforeInit {
-			err := state.Database().IsOpen(r.Context())
-			if err != nil {
-				err := response.SmartError(err).Render(w)
-				if err != nil {
-					logger.Error("Failed to write HTTP response", logger.Ctx{"url": r.URL, "err": err})
-				}
-
-				return
-			}
-		}
-
-		// If the request is a database request, the connection should be hijacked.
-		handleRequest := handleAPIRequest
-		if e.Path == "database" {
-			handleRequest = handleDatabaseRequest
-		}
-
-		trusted, err := access.Authenticate(state, r, state.Address().URL.Host, state.Remotes().CertificatesNative())
-		if err != nil && !errors.As(err, &access.ErrInvalidHost{}) {
-			resp = response.Forbidden(fmt.Errorf("Failed to authenticate request: %w", err))
-		} else {
-			r = internalAccess.SetRequestAuthentication(r, trusted)
-
-			switch r.Method {
-			case "GET":
-				resp = handleRequest(e.Get, state, w, r)
-			case "PUT":
-				resp = handleRequest(e.Put, state, w, r)
-			case "POST":
-				resp = handleRequest(e.Post, state, w, r)
-			case "DELETE":
-				resp = handleRequest(e.Delete, state, w, r)
-			case "PATCH":
-				resp = handleRequest(e.Patch, state, w, r)
-			default:
-				resp = response.NotFound(fmt.Errorf("Method '%s' not found", r.Method))
-			}
-		}
-
 		// Handle errors.
-		if e.Path != "database" {
-			err := resp.Render(w)
+		err := resp.Render(w)
+		if err != nil {
+			err := response.InternalError(err).Render(w)
 			if err != nil {
-				err := response.InternalError(err).Render(w)
-				if err != nil {
-					logger.Error("Failed writing error for HTTP response", logger.Ctx{"url": url, "error": err})
-				}
+				logger.Error("Failed writing error for HTTP response", logger.Ctx{"url": url, "error": err})
 			}
 		}
 	})
@@ -0,0 +1,18 @@
+package types
+
+import (
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// CertificateRotationPut asks a cluster member to apply a phase of a rotation of the cluster certificate, along with
+// the phases before it.
+type CertificateRotationPut struct {
+	// Phase is the phase to apply.
+	Phase types.CertificateRotationPhase `json:"phase" yaml:"phase"`
+
+	// Cert is the PEM encoded new cluster certificate.
+	Cert string `json:"cert" yaml:"cert"`
+
+	// Key is the PEM encoded private key of the new cluster certificate, or empty once every cluster member serves it.
+	Key string `json:"key" yaml:"key"`
+}
@@ -0,0 +1,26 @@
+package types
+
+import (
+	"time"
+
+	"github.com/canonical/microcluster/v2/internal/extensions"
+)
+
+// DatabaseBackup is the metadata stored alongside the database snapshot in a backup tarball.
+type DatabaseBackup struct {
+	CreatedAt      time.Time              `json:"created_at" yaml:"created_at"`
+	Location       string                 `json:"location" yaml:"location"`
+	SchemaInternal uint64                 `json:"schema_internal" yaml:"schema_internal"`
+	SchemaExternal uint64                 `json:"schema_external" yaml:"schema_external"`
+	APIExtensions  extensions.Extensions  `json:"api_extensions" yaml:"api_extensions"`
+	ClusterMembers []DatabaseBackupMember `json:"cluster_members" yaml:"cluster_members"`
+}
+
+// DatabaseBackupMember records the versions of a cluster member at the time a database backup was taken.
+type DatabaseBackupMember struct {
+	Name           string                `json:"name" yaml:"name"`
+	Address        string                `json:"address" yaml:"address"`
+	SchemaInternal uint64                `json:"schema_internal" yaml:"schema_internal"`
+	SchemaExternal uint64                `json:"schema_external" yaml:"schema_external"`
+	APIExtensions  extensions.Extensions `json:"api_extensions" yaml:"api_extensions"`
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"encoding/json"
+
+	"github.com/canonical/microcluster/v2/internal/health"
 	"github.com/canonical/microcluster/v2/rest/types"
 )
 
@@ -13,4 +16,22 @@ type HeartbeatInfo struct {
 	ClusterMembers    map[string]types.ClusterMember `json:"cluster_members"     yaml:"cluster_members"`
 	LeaderAddress     string                         `json:"leader_address"      yaml:"leader_address"`
 	DqliteRoles       map[string]string              `json:"dqlite_roles"        yaml:"dqlite_roles"`
+	MemberData        map[string]json.RawMessage     `json:"member_data"         yaml:"member_data"`
+	MemberHealth      map[string]health.Member       `json:"member_health"       yaml:"member_health"`
+	HeartbeatConfig   *types.HeartbeatConfig         `json:"heartbeat_config"    yaml:"heartbeat_config"`
+
+	// MemberCertificates lists the keypairs managed by each cluster member and when they expire, keyed by name.
+	MemberCertificates map[string][]types.CertificateExpiry `json:"member_certificates" yaml:"member_certificates"`
+}
+
+// HeartbeatResponse is returned by a cluster member when it answers a heartbeat sent by the leader.
+type HeartbeatResponse struct {
+	// Data is the application data contributed by the cluster member through its HeartbeatData hook.
+	Data json.RawMessage `json:"data,omitempty" yaml:"data,omitempty"`
+
+	// HeartbeatConfig is the heartbeat configuration in effect on the cluster member.
+	HeartbeatConfig *types.HeartbeatConfig `json:"heartbeat_config,omitempty" yaml:"heartbeat_config,omitempty"`
+
+	// Certificates lists the keypairs managed by the cluster member and when they expire.
+	Certificates []types.CertificateExpiry `json:"certificates,omitempty" yaml:"certificates,omitempty"`
 }
@@ -29,14 +29,29 @@ const (
 	// PostRemove is run on all other peers after one is removed from the cluster.
 	PostRemove HookType = "post-remove"
 
+	// PreEvacuate is run on a cluster member before it is put into maintenance.
+	PreEvacuate HookType = "pre-evacuate"
+
+	// PostRestore is run on a cluster member after it has been taken out of maintenance.
+	PostRestore HookType = "post-restore"
+
 	// OnNewMember is run on each peer after a new cluster member has joined and executed their 'PreJoin' hook.
 	OnNewMember HookType = "on-new-member"
 
 	// OnHeartbeat is run after a successful heartbeat round.
 	OnHeartbeat HookType = "on-heartbeat"
 
+	// OnMemberOffline is run on each online cluster member after another cluster member stops answering heartbeats.
+	OnMemberOffline HookType = "on-member-offline"
+
+	// OnMemberOnline is run on each online cluster member after another cluster member answers heartbeats again.
+	OnMemberOnline HookType = "on-member-online"
+
 	// OnDaemonConfigUpdate is run after the local daemon received a config update.
 	OnDaemonConfigUpdate HookType = "on-daemon-config-update"
+
+	// OnConfigChange is run on each cluster member after cluster config keys have changed.
+	OnConfigChange HookType = "on-config-change"
 )
 
 // HookRemoveMemberOptions holds configuration pertaining to the PreRemove and PostRemove hooks.
@@ -50,3 +65,15 @@ type HookNewMemberOptions struct {
 	// Name is the name of the new cluster member that joined the cluster, triggering this hook.
 	NewMember types.ClusterMemberLocal `json:"new_member" yaml:"new_member"`
 }
+
+// HookMemberStateOptions holds configuration pertaining to the OnMemberOffline and OnMemberOnline hooks.
+type HookMemberStateOptions struct {
+	// Member is the cluster member whose online status changed, triggering this hook.
+	Member types.ClusterMemberLocal `json:"member" yaml:"member"`
+}
+
+// HookConfigChangeOptions holds configuration pertaining to the OnConfigChange hook.
+type HookConfigChangeOptions struct {
+	// Changes are the cluster config keys that changed, triggering this hook.
+	Changes []types.ConfigChange `json:"changes" yaml:"changes"`
+}
@@ -0,0 +1,7 @@
+package types
+
+// RecoveryTarballReceipt is returned by a cluster member that has staged a pushed recovery tarball.
+type RecoveryTarballReceipt struct {
+	Name     string `json:"name" yaml:"name"`
+	DqliteID uint64 `json:"dqlite_id" yaml:"dqlite_id"`
+}
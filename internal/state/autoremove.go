@@ -0,0 +1,16 @@
+package state
+
+import (
+	"time"
+)
+
+// AutoRemovePolicy configures the automatic removal of cluster members that have stopped responding to heartbeats.
+type AutoRemovePolicy struct {
+	// After is how long a cluster member must miss heartbeats for before the leader forcibly removes it from the cluster.
+	// Only heartbeats sent by the current leader count, so nothing is removed until it has been the leader for as long.
+	// Automatic removal is disabled if zero.
+	After time.Duration
+
+	// MinMembers is the smallest number of cluster members to keep. Automatic removal never takes the cluster below it.
+	MinMembers int
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/canonical/microcluster/v2/rest/types"
 )
@@ -32,12 +33,42 @@ type Hooks struct {
 	// PostRemove is run on all other peers after one is removed from the cluster.
 	PostRemove func(ctx context.Context, s State, force bool) error
 
+	// PreEvacuate is run on a cluster member before it is put into maintenance, after dqlite leadership has been
+	// transferred away from it. If it fails, the cluster member is not evacuated.
+	PreEvacuate func(ctx context.Context, s State) error
+
+	// PostRestore is run on a cluster member after it has been taken out of maintenance.
+	PostRestore func(ctx context.Context, s State) error
+
 	// OnHeartbeat is run after a successful heartbeat round.
 	OnHeartbeat func(ctx context.Context, s State) error
 
 	// OnNewMember is run on each peer after a new cluster member has joined and executed their 'PreJoin' hook.
 	OnNewMember func(ctx context.Context, s State, newMember types.ClusterMemberLocal) error
 
+	// OnMemberOffline is run on each online cluster member after the leader fails to send a heartbeat to a cluster member
+	// that answered the previous one.
+	OnMemberOffline func(ctx context.Context, s State, member types.ClusterMemberLocal) error
+
+	// OnMemberOnline is run on each online cluster member after the leader sends a heartbeat to a cluster member that
+	// was previously offline.
+	OnMemberOnline func(ctx context.Context, s State, member types.ClusterMemberLocal) error
+
+	// OnLeaderChange is run on each cluster member when it learns through heartbeats that the dqlite leader has changed.
+	// The names of the old and new leader are given, and the old leader is empty the first time the leader is learned.
+	OnLeaderChange func(ctx context.Context, s State, oldLeader string, newLeader string) error
+
+	// HeartbeatData is run on each cluster member when it answers a heartbeat, and on the leader when it begins one.
+	// The returned JSON is aggregated by the leader and redistributed to all cluster members with the next heartbeat.
+	// It should be fast and return a small amount of data, as it delays the heartbeat.
+	HeartbeatData func(ctx context.Context, s State) (json.RawMessage, error)
+
+	// OnHeartbeatData is run on each cluster member after it receives the aggregated heartbeat data of all cluster members, keyed by name.
+	OnHeartbeatData func(ctx context.Context, s State, data map[string]json.RawMessage) error
+
+	// OnConfigChange is run on each cluster member after cluster config keys have changed.
+	OnConfigChange func(ctx context.Context, s State, changes []types.ConfigChange) error
+
 	// OnDaemonConfigUpdate is a post-action hook that is run on all cluster members when any cluster member receives a local configuration update.
 	OnDaemonConfigUpdate func(ctx context.Context, s State, config types.DaemonConfig) error
 }
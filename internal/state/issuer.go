@@ -0,0 +1,139 @@
+package state
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/canonical/lxd/lxd/util"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// CertificateRequest describes a keypair for a CertificateIssuer to issue.
+type CertificateRequest struct {
+	// Name of the keypair, which is either the server or cluster certificate, or an additional listener with a
+	// dedicated certificate.
+	Name types.CertificateName
+
+	// CSR is the certificate signing request for the keypair. Its common name and only DNS name is the subject name
+	// that cluster members verify the certificate against.
+	CSR *x509.CertificateRequest
+}
+
+// CertificateIssuer issues the keypairs managed by microcluster in place of self-signed certificates, for example
+// through a local CA or an external PKI. The private keys never leave the cluster member.
+type CertificateIssuer interface {
+	// IssueCertificate signs the certificate signing request of the given keypair. It returns the PEM encoded
+	// certificate followed by any intermediate CA certificates, and the PEM encoded CA certificate at the root of the
+	// chain.
+	IssueCertificate(ctx context.Context, req CertificateRequest) (chain []byte, ca []byte, err error)
+}
+
+// IssueKeyPair generates a private key for the named keypair, and has the issuer sign a certificate for it with the
+// given subject name. The returned certificate chain is checked to belong to the private key and to lead to the CA.
+func IssueKeyPair(ctx context.Context, issuer CertificateIssuer, name types.CertificateName, subjectName string) (cert []byte, key []byte, ca []byte, err error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed to generate key for %q certificate: %w", name, err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed to encode key for %q certificate: %w", name, err)
+	}
+
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: subjectName}, DNSNames: []string{subjectName}}, privateKey)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed to create certificate signing request for %q certificate: %w", name, err)
+	}
+
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed to parse certificate signing request for %q certificate: %w", name, err)
+	}
+
+	cert, ca, err = issuer.IssueCertificate(ctx, CertificateRequest{Name: name, CSR: csr})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed to issue %q certificate: %w", name, err)
+	}
+
+	key = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	err = verifyIssuedKeyPair(cert, key, ca, subjectName)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Issued %q certificate is invalid: %w", name, err)
+	}
+
+	return cert, key, ca, nil
+}
+
+// WriteIssuedKeyPair has the issuer issue the named keypair with the given subject name, and writes it to the given
+// directory as <name>.crt, <name>.key and <name>.ca.
+func WriteIssuedKeyPair(ctx context.Context, issuer CertificateIssuer, dir string, name types.CertificateName, subjectName string) error {
+	cert, key, ca, err := IssueKeyPair(ctx, issuer, name, subjectName)
+	if err != nil {
+		return err
+	}
+
+	err = util.WriteCert(dir, string(name), cert, key, ca)
+	if err != nil {
+		return fmt.Errorf("Failed to write issued %q certificate: %w", name, err)
+	}
+
+	return nil
+}
+
+// verifyIssuedKeyPair checks that the certificate chain belongs to the private key, and that it leads to the CA and is
+// valid for the subject name.
+func verifyIssuedKeyPair(cert []byte, key []byte, ca []byte, subjectName string) error {
+	keypair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return err
+	}
+
+	leaf, err := x509.ParseCertificate(keypair.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	caBlock, _ := pem.Decode(ca)
+	if caBlock == nil {
+		return fmt.Errorf("CA certificate must be PEM encoded")
+	}
+
+	caCert, err := x509.ParseCertificate(caBlock.Bytes)
+	if err != nil {
+		return fmt.Errorf("Failed to parse CA certificate: %w", err)
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+		DNSName:       subjectName,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	opts.Roots.AddCert(caCert)
+	for _, rawCert := range keypair.Certificate[1:] {
+		intermediate, err := x509.ParseCertificate(rawCert)
+		if err != nil {
+			return err
+		}
+
+		opts.Intermediates.AddCert(intermediate)
+	}
+
+	_, err = leaf.Verify(opts)
+	if err != nil {
+		return fmt.Errorf("Certificate is not issued by the CA: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package state
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type issuerSuite struct {
+	suite.Suite
+}
+
+func TestIssuerSuite(t *testing.T) {
+	suite.Run(t, new(issuerSuite))
+}
+
+// testCA is a local CA standing in for an external PKI.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+
+	// subjectName overrides the DNS name of the issued certificates if set.
+	subjectName string
+
+	requests []CertificateRequest
+}
+
+// newTestCA returns a new self-signed local CA.
+func (t *issuerSuite) newTestCA() *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	t.Require().NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	t.Require().NoError(err)
+
+	cert, err := x509.ParseCertificate(certDER)
+	t.Require().NoError(err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+// IssueCertificate implements CertificateIssuer.
+func (ca *testCA) IssueCertificate(ctx context.Context, req CertificateRequest) ([]byte, []byte, error) {
+	ca.requests = append(ca.requests, req)
+
+	dnsNames := req.CSR.DNSNames
+	if ca.subjectName != "" {
+		dnsNames = []string{ca.subjectName}
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(len(ca.requests) + 1)),
+		Subject:      req.CSR.Subject,
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca.cert, req.CSR.PublicKey, ca.key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}), nil
+}
+
+func (t *issuerSuite) Test_IssueKeyPair() {
+	ca := t.newTestCA()
+
+	cert, key, caPEM, err := IssueKeyPair(context.Background(), ca, types.ServerCertificateName, "member1")
+	t.Require().NoError(err)
+	t.Require().Len(ca.requests, 1)
+	t.Equal(types.ServerCertificateName, ca.requests[0].Name)
+	t.Equal("member1", ca.requests[0].CSR.Subject.CommonName)
+
+	keypair, err := shared.KeyPairFromRaw(cert, key)
+	t.Require().NoError(err)
+
+	leaf, err := keypair.PublicKeyX509()
+	t.Require().NoError(err)
+	t.Equal([]string{"member1"}, leaf.DNSNames)
+	t.NoError(leaf.CheckSignatureFrom(ca.cert))
+
+	block, _ := pem.Decode(caPEM)
+	t.Require().NotNil(block)
+	t.Equal(ca.cert.Raw, block.Bytes)
+
+	// Certificates that don't match the subject name are rejected.
+	ca.subjectName = "member2"
+	_, _, _, err = IssueKeyPair(context.Background(), ca, types.ServerCertificateName, "member1")
+	t.Error(err)
+
+	// Certificates must lead to the returned CA.
+	other := t.newTestCA()
+	issuer := &mismatchedCA{issuer: other, ca: ca.cert}
+	_, _, _, err = IssueKeyPair(context.Background(), issuer, types.ClusterCertificateName, "member1")
+	t.Error(err)
+}
+
+// mismatchedCA issues certificates through one CA, but returns another as the root of the chain.
+type mismatchedCA struct {
+	issuer *testCA
+	ca     *x509.Certificate
+}
+
+// IssueCertificate implements CertificateIssuer.
+func (m *mismatchedCA) IssueCertificate(ctx context.Context, req CertificateRequest) ([]byte, []byte, error) {
+	cert, _, err := m.issuer.IssueCertificate(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: m.ca.Raw}), nil
+}
@@ -2,20 +2,29 @@ package state
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
 
 	"github.com/canonical/microcluster/v2/client"
+	"github.com/canonical/microcluster/v2/internal/auth"
 	internalConfig "github.com/canonical/microcluster/v2/internal/config"
 	"github.com/canonical/microcluster/v2/internal/db"
 	"github.com/canonical/microcluster/v2/internal/endpoints"
+	"github.com/canonical/microcluster/v2/internal/events"
 	"github.com/canonical/microcluster/v2/internal/extensions"
+	"github.com/canonical/microcluster/v2/internal/health"
+	"github.com/canonical/microcluster/v2/internal/leases"
+	"github.com/canonical/microcluster/v2/internal/operations"
 	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
 	"github.com/canonical/microcluster/v2/internal/sys"
 	"github.com/canonical/microcluster/v2/internal/trust"
+	"github.com/canonical/microcluster/v2/rest/openapi"
 	"github.com/canonical/microcluster/v2/rest/types"
 )
 
@@ -39,15 +48,21 @@ type State interface {
 	// Cluster certificate is used for downstream connections within a cluster.
 	ClusterCert() *shared.CertInfo
 
+	// AlternateClusterCerts are trusted alongside the cluster certificate while it is rotated.
+	AlternateClusterCerts() []*x509.Certificate
+
 	// Database.
 	Database() db.DB
 
 	// Local truststore access.
 	Remotes() *trust.Remotes
 
-	// Cluster returns a client to every cluster member according to dqlite.
+	// Cluster returns a client to every cluster member according to dqlite, except members in maintenance.
 	Cluster(isNotification bool) (client.Cluster, error)
 
+	// ClusterWithMaintenance returns a client to every cluster member according to dqlite, including members in maintenance.
+	ClusterWithMaintenance(isNotification bool) (client.Cluster, error)
+
 	// Leader returns a client to the dqlite cluster leader.
 	Leader() (*client.Client, error)
 
@@ -56,6 +71,21 @@ type State interface {
 
 	// ExtensionServers returns an immutable list of the daemon's additional listeners.
 	ExtensionServers() []string
+
+	// SendEvent sends an event to the event listeners of all cluster members.
+	SendEvent(eventType types.EventType, metadata any) error
+
+	// Operations returns the manager of long-running operations on the local cluster member.
+	Operations() *operations.Manager
+
+	// Leases returns the manager of cluster-wide leases held by the local cluster member.
+	Leases() *leases.Manager
+
+	// HeartbeatData returns the application data contributed by each cluster member through heartbeats, keyed by name.
+	HeartbeatData() map[string]json.RawMessage
+
+	// Config returns the cluster config keys, as seen by the local cluster member.
+	Config() *internalConfig.Store
 }
 
 // InternalState is a gateway to the stateful components of the microcluster daemon.
@@ -84,7 +114,7 @@ type InternalState struct {
 	// ReloadCert reloads the given keypair from the state directory.
 	ReloadCert func(name types.CertificateName) error
 
-	// StopListeners stops the network listeners and the fsnotify listener.
+	// StopListeners stops the network listeners and the fsnotify listener. The control socket is left up.
 	StopListeners func() error
 
 	// Stop fully stops the daemon, its database, and all listeners.
@@ -96,15 +126,43 @@ type InternalState struct {
 	// Hooks contain external implementations that are triggered by specific cluster actions.
 	Hooks *Hooks
 
-	InternalFileSystem       func() *sys.OS
-	InternalAddress          func() *api.URL
-	InternalName             func() string
-	InternalVersion          func() string
-	InternalServerCert       func() *shared.CertInfo
-	InternalClusterCert      func() *shared.CertInfo
-	InternalDatabase         *db.DqliteDB
-	InternalRemotes          func() *trust.Remotes
-	InternalExtensionServers func() []string
+	// AutoRemove configures the automatic removal of unreachable cluster members by the leader.
+	AutoRemove AutoRemovePolicy
+
+	// CertificateIssuer issues the keypairs of this cluster member, which are self-signed if it is nil.
+	CertificateIssuer CertificateIssuer
+
+	// Events dispatches events to the local event listeners.
+	Events *events.Server
+
+	// FailureDetector estimates how likely cluster members are to have failed, based on heartbeats.
+	FailureDetector *health.Detector
+
+	// AuthPolicy caches the authorization policy, which remains available while the database is not.
+	AuthPolicy *auth.PolicyCache
+
+	// TaskStatus returns the status of the scheduled tasks on this cluster member.
+	TaskStatus func() []types.TaskStatus
+
+	// RunTask runs the named scheduled task on this cluster member.
+	RunTask func(ctx context.Context, name string) error
+
+	// OpenAPI returns the OpenAPI document describing the resources served by the named listener.
+	OpenAPI func(listener string) (*openapi.Document, error)
+
+	InternalFileSystem            func() *sys.OS
+	InternalAddress               func() *api.URL
+	InternalName                  func() string
+	InternalVersion               func() string
+	InternalServerCert            func() *shared.CertInfo
+	InternalClusterCert           func() *shared.CertInfo
+	InternalAlternateClusterCerts func() []*x509.Certificate
+	InternalDatabase              *db.DqliteDB
+	InternalRemotes               func() *trust.Remotes
+	InternalExtensionServers      func() []string
+	InternalOperations            *operations.Manager
+	InternalLeases                *leases.Manager
+	InternalConfig                *internalConfig.Store
 }
 
 // FileSystem can be used to inspect the microcluster filesystem.
@@ -138,6 +196,12 @@ func (s *InternalState) ClusterCert() *shared.CertInfo {
 	return s.InternalClusterCert()
 }
 
+// AlternateClusterCerts returns the next and previous cluster certificates, which are trusted alongside the cluster
+// certificate while it is rotated.
+func (s *InternalState) AlternateClusterCerts() []*x509.Certificate {
+	return s.InternalAlternateClusterCerts()
+}
+
 // Database allows access to the dqlite database.
 func (s *InternalState) Database() db.DB {
 	return s.InternalDatabase
@@ -153,16 +217,108 @@ func (s *InternalState) ExtensionServers() []string {
 	return s.InternalExtensionServers()
 }
 
+// Operations returns the manager of long-running operations on the local cluster member.
+func (s *InternalState) Operations() *operations.Manager {
+	return s.InternalOperations
+}
+
+// Leases returns the manager of cluster-wide leases held by the local cluster member.
+func (s *InternalState) Leases() *leases.Manager {
+	return s.InternalLeases
+}
+
+// HeartbeatData returns the application data contributed by each cluster member through heartbeats, keyed by name.
+// Cluster members other than the leader see the data as of the previous heartbeat round.
+func (s *InternalState) HeartbeatData() map[string]json.RawMessage {
+	return s.InternalDatabase.HeartbeatData()
+}
+
+// Config returns the cluster config keys, as seen by the local cluster member.
+func (s *InternalState) Config() *internalConfig.Store {
+	return s.InternalConfig
+}
+
 // HasExtension returns whether the given API extension is supported.
 func (s *InternalState) HasExtension(ext string) bool {
 	return s.Extensions.HasExtension(ext)
 }
 
+// SendEvent sends an event of the given type to the local event listeners, and forwards it to all other cluster members.
+// The metadata is marshalled to JSON and included with the event.
+func (s *InternalState) SendEvent(eventType types.EventType, metadata any) error {
+	event := types.Event{
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		Location:  s.Name(),
+	}
+
+	if metadata != nil {
+		var err error
+		event.Metadata, err = json.Marshal(metadata)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal %q event metadata: %w", eventType, err)
+		}
+	}
+
+	s.Events.Send(event)
+
+	go s.forwardEvent(event)
+
+	return nil
+}
+
+// forwardEvent sends the event to all other cluster members in the local truststore.
+func (s *InternalState) forwardEvent(event types.Event) {
+	remotes := s.Remotes()
+	if remotes == nil {
+		return
+	}
+
+	var publicKey *x509.Certificate
+	for name, remote := range remotes.RemotesByName() {
+		if name == s.Name() {
+			continue
+		}
+
+		if publicKey == nil {
+			var err error
+			publicKey, err = s.ClusterCert().PublicKeyX509()
+			if err != nil {
+				logger.Warn("Failed to parse cluster certificate for event forwarding", logger.Ctx{"error": err})
+				return
+			}
+		}
+
+		c, err := internalClient.New(remote.URL(), s.ServerCert(), publicKey, true, s.AlternateClusterCerts()...)
+		if err != nil {
+			logger.Warn("Failed to create client for event forwarding", logger.Ctx{"name": name, "error": err})
+			continue
+		}
+
+		err = internalClient.SendEvent(s.Context, c, event)
+		if err != nil {
+			logger.Debug("Failed to forward event to cluster member", logger.Ctx{"name": name, "type": event.Type, "error": err})
+		}
+	}
+}
+
 // Cluster returns a client for every member of a cluster, except
-// this one.
+// this one and those in maintenance.
 // All requests made by the client will have the UserAgentNotifier header set
 // if isNotification is true.
 func (s *InternalState) Cluster(isNotification bool) (client.Cluster, error) {
+	return s.cluster(isNotification, false)
+}
+
+// ClusterWithMaintenance returns a client for every member of a cluster, except
+// this one, including those in maintenance.
+// All requests made by the client will have the UserAgentNotifier header set
+// if isNotification is true.
+func (s *InternalState) ClusterWithMaintenance(isNotification bool) (client.Cluster, error) {
+	return s.cluster(isNotification, true)
+}
+
+func (s *InternalState) cluster(isNotification bool, includeMaintenance bool) (client.Cluster, error) {
 	c, err := s.Leader()
 	if err != nil {
 		return nil, err
@@ -179,13 +335,17 @@ func (s *InternalState) Cluster(isNotification bool) (client.Cluster, error) {
 			continue
 		}
 
+		if !includeMaintenance && clusterMember.Status == types.MemberMaintenance {
+			continue
+		}
+
 		publicKey, err := s.ClusterCert().PublicKeyX509()
 		if err != nil {
 			return nil, err
 		}
 
 		url := api.NewURL().Scheme("https").Host(clusterMember.Address.String())
-		c, err := internalClient.New(*url, s.ServerCert(), publicKey, isNotification)
+		c, err := internalClient.New(*url, s.ServerCert(), publicKey, isNotification, s.AlternateClusterCerts()...)
 		if err != nil {
 			return nil, err
 		}
@@ -217,7 +377,7 @@ func (s *InternalState) Leader() (*client.Client, error) {
 	}
 
 	url := api.NewURL().Scheme("https").Host(leaderInfo.Address)
-	c, err := internalClient.New(*url, s.ServerCert(), publicKey, false)
+	c, err := internalClient.New(*url, s.ServerCert(), publicKey, false, s.AlternateClusterCerts()...)
 	if err != nil {
 		return nil, err
 	}
@@ -1,8 +1,11 @@
 package sys
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -12,6 +15,20 @@ import (
 	"github.com/canonical/microcluster/v2/rest/types"
 )
 
+const (
+	// NextClusterCertName is the name of the keypair in the state directory that replaces the cluster certificate
+	// once a rotation switches to it.
+	NextClusterCertName = "cluster.next"
+
+	// PreviousClusterCertName is the name of the keypair in the state directory that a rotation replaced, until it
+	// is retired, or until it replaces the cluster certificate again if the rotation is aborted.
+	PreviousClusterCertName = "cluster.previous"
+
+	// NextServerCertName is the name of the keypair in the state directory that replaces the server certificate once
+	// the cluster members trust it.
+	NextServerCertName = "server.next"
+)
+
 // OS contains fields and methods for interacting with the state directory.
 type OS struct {
 	StateDir        string
@@ -117,6 +134,11 @@ func (s *OS) DatabasePath() string {
 
 // ServerCert gets the local server certificate from the state directory.
 func (s *OS) ServerCert() (*shared.CertInfo, error) {
+	err := CompleteKeyPairSwitch(s.StateDir, "server", NextServerCertName)
+	if err != nil {
+		return nil, err
+	}
+
 	if !shared.PathExists(filepath.Join(s.StateDir, "server.crt")) {
 		return nil, fmt.Errorf("Failed to get server.crt from directory %q", s.StateDir)
 	}
@@ -131,6 +153,17 @@ func (s *OS) ServerCert() (*shared.CertInfo, error) {
 
 // ClusterCert gets the local cluster certificate from the state directory.
 func (s *OS) ClusterCert() (*shared.CertInfo, error) {
+	err := CompleteKeyPairSwitch(s.StateDir, string(types.ClusterCertificateName), NextClusterCertName)
+	if err != nil {
+		return nil, err
+	}
+
+	// An aborted rotation switches back to the previous keypair, once the next one has been removed.
+	err = CompleteKeyPairSwitch(s.StateDir, string(types.ClusterCertificateName), PreviousClusterCertName)
+	if err != nil {
+		return nil, err
+	}
+
 	if !shared.PathExists(filepath.Join(s.StateDir, fmt.Sprintf("%s.crt", types.ClusterCertificateName))) {
 		return nil, fmt.Errorf("Failed to get %s.crt from directory %q", types.ClusterCertificateName, s.StateDir)
 	}
@@ -142,3 +175,74 @@ func (s *OS) ClusterCert() (*shared.CertInfo, error) {
 
 	return cert, nil
 }
+
+// SwitchKeyPair replaces the named keypair in the given directory with the next one, along with its CA if it has one.
+// Each file is replaced with a single rename, and the certificate is replaced after the key, so that a switch that is
+// interrupted part way through can be completed with CompleteKeyPairSwitch.
+func SwitchKeyPair(dir string, name string, nextName string) error {
+	path := func(name string, ext string) string {
+		return filepath.Join(dir, fmt.Sprintf("%s.%s", name, ext))
+	}
+
+	// The CA of the replaced keypair does not apply to the next one.
+	if !shared.PathExists(path(nextName, "ca")) {
+		err := os.Remove(path(name, "ca"))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("Failed to remove %s.ca: %w", name, err)
+		}
+	}
+
+	for _, ext := range []string{"key", "crt", "ca"} {
+		err := os.Rename(path(nextName, ext), path(name, ext))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("Failed to replace %s.%s: %w", name, ext, err)
+		}
+	}
+
+	return nil
+}
+
+// CompleteKeyPairSwitch completes a switch of the named keypair in the given directory to the next one, if one was
+// interrupted part way through. Nothing is done if the named keypair is valid and the next one is intact, as it may
+// not be ready to replace it yet.
+func CompleteKeyPairSwitch(dir string, name string, nextName string) error {
+	path := func(name string, ext string) string {
+		return filepath.Join(dir, fmt.Sprintf("%s.%s", name, ext))
+	}
+
+	if shared.PathExists(path(nextName, "crt")) {
+		_, err := tls.LoadX509KeyPair(path(name, "crt"), path(name, "key"))
+		if err == nil {
+			return nil
+		}
+	} else if !shared.PathExists(path(nextName, "ca")) {
+		return nil
+	}
+
+	return SwitchKeyPair(dir, name, nextName)
+}
+
+// AlternateClusterCerts gets the next and previous cluster certificates from the state directory, which are trusted
+// alongside the cluster certificate while it is rotated.
+func (s *OS) AlternateClusterCerts() ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for _, name := range []string{NextClusterCertName, PreviousClusterCertName} {
+		certPEM, err := os.ReadFile(filepath.Join(s.StateDir, fmt.Sprintf("%s.crt", name)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read %s.crt: %w", name, err)
+		}
+
+		cert, err := types.ParseX509Certificate(string(certPEM))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %s.crt: %w", name, err)
+		}
+
+		certs = append(certs, cert.Certificate)
+	}
+
+	return certs, nil
+}
@@ -0,0 +1,399 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/robfig/cron/v3"
+
+	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
+	"github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/internal/trust"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+// Task is a function that is run periodically by the daemon once its database is ready.
+type Task struct {
+	// Name uniquely identifies the task.
+	Name string
+
+	// Interval is the time between the end of one run and the start of the next.
+	// Exactly one of Interval and Schedule must be set.
+	Interval time.Duration
+
+	// Schedule is a cron expression in the standard five field format, e.g. "30 2 * * *".
+	// It is evaluated in the local time zone unless prefixed with "CRON_TZ=<zone>".
+	Schedule string
+
+	// RunMode determines which cluster members run the task. Defaults to types.TaskRunLeader.
+	RunMode types.TaskRunMode
+
+	// Jitter is the maximum random delay added before each run.
+	Jitter time.Duration
+
+	// Timeout is the maximum duration of a single run. If zero, runs are only cancelled on shutdown.
+	Timeout time.Duration
+
+	// Run is the function executed by the task.
+	Run func(ctx context.Context, s state.State) error
+}
+
+// Scheduler runs tasks according to their schedule and run mode.
+type Scheduler struct {
+	tasks map[string]*scheduledTask
+	names []string
+	state func() state.State
+
+	lock    sync.Mutex
+	cancel  context.CancelFunc
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// scheduledTask holds a task alongside its parsed schedule and the status of its runs.
+type scheduledTask struct {
+	Task
+	schedule cron.Schedule
+
+	lock   sync.Mutex
+	status types.TaskStatus
+}
+
+// intervalSchedule is a cron.Schedule that activates a fixed duration after the given time.
+type intervalSchedule time.Duration
+
+// Next returns the time of the next activation after t.
+func (i intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// NewScheduler validates the given tasks and returns a scheduler for them.
+// The state function is called to get the daemon state for each run.
+func NewScheduler(tasks []Task, stateFunc func() state.State) (*Scheduler, error) {
+	s := &Scheduler{
+		tasks: make(map[string]*scheduledTask, len(tasks)),
+		names: make([]string, 0, len(tasks)),
+		state: stateFunc,
+	}
+
+	for _, task := range tasks {
+		if task.Name == "" {
+			return nil, fmt.Errorf("Task name cannot be empty")
+		}
+
+		_, ok := s.tasks[task.Name]
+		if ok {
+			return nil, fmt.Errorf("Task %q is defined more than once", task.Name)
+		}
+
+		if task.Run == nil {
+			return nil, fmt.Errorf("Task %q has no run function", task.Name)
+		}
+
+		if task.RunMode == "" {
+			task.RunMode = types.TaskRunLeader
+		}
+
+		if !slices.Contains([]types.TaskRunMode{types.TaskRunLeader, types.TaskRunEveryMember, types.TaskRunRandomMember}, task.RunMode) {
+			return nil, fmt.Errorf("Task %q has invalid run mode %q", task.Name, task.RunMode)
+		}
+
+		if task.Jitter < 0 || task.Timeout < 0 {
+			return nil, fmt.Errorf("Task %q cannot have a negative jitter or timeout", task.Name)
+		}
+
+		t := &scheduledTask{Task: task}
+		switch {
+		case task.Interval > 0 && task.Schedule != "":
+			return nil, fmt.Errorf("Task %q cannot have both an interval and a schedule", task.Name)
+		case task.Interval > 0:
+			t.schedule = intervalSchedule(task.Interval)
+			t.status.Schedule = "every " + task.Interval.String()
+		case task.Schedule != "":
+			var err error
+			t.schedule, err = cron.ParseStandard(task.Schedule)
+			if err != nil {
+				return nil, fmt.Errorf("Task %q has invalid schedule %q: %w", task.Name, task.Schedule, err)
+			}
+
+			t.status.Schedule = task.Schedule
+		default:
+			return nil, fmt.Errorf("Task %q must have a positive interval or a schedule", task.Name)
+		}
+
+		t.status.Name = task.Name
+		t.status.RunMode = task.RunMode
+
+		s.tasks[task.Name] = t
+		s.names = append(s.names, task.Name)
+	}
+
+	return s, nil
+}
+
+// Start runs the tasks in the background until the context is cancelled or Stop is called.
+// Calls after the first, or after Stop, have no effect.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.cancel != nil || s.stopped {
+		return
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, name := range s.names {
+		t := s.tasks[name]
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, t)
+		}()
+	}
+
+	logger.Debug("Started task scheduler", logger.Ctx{"tasks": s.names})
+}
+
+// Stop cancels any running tasks and waits up to the given timeout for them to return.
+func (s *Scheduler) Stop(timeout time.Duration) error {
+	s.lock.Lock()
+	s.stopped = true
+	cancel := s.cancel
+	s.lock.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("Timed out waiting for tasks to stop")
+	}
+}
+
+// Status returns the status of all tasks on the local cluster member, in the order they were defined.
+func (s *Scheduler) Status() []types.TaskStatus {
+	statuses := make([]types.TaskStatus, 0, len(s.names))
+	for _, name := range s.names {
+		t := s.tasks[name]
+
+		t.lock.Lock()
+		statuses = append(statuses, t.status)
+		t.lock.Unlock()
+	}
+
+	return statuses
+}
+
+// RunTask runs the named task on the local cluster member, regardless of its schedule and run mode.
+func (s *Scheduler) RunTask(ctx context.Context, name string) error {
+	t, ok := s.tasks[name]
+	if !ok {
+		return api.StatusErrorf(http.StatusNotFound, "Task %q not found", name)
+	}
+
+	return s.run(ctx, s.state(), t)
+}
+
+// loop triggers the task each time its schedule activates, until the context is cancelled.
+func (s *Scheduler) loop(ctx context.Context, t *scheduledTask) {
+	for {
+		next := t.schedule.Next(time.Now())
+		if next.IsZero() {
+			logger.Warn("Task schedule has no future runs", logger.Ctx{"task": t.Name})
+			return
+		}
+
+		if t.Jitter > 0 {
+			next = next.Add(rand.N(t.Jitter))
+		}
+
+		t.lock.Lock()
+		t.status.NextRun = next.UTC()
+		t.lock.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.trigger(ctx, t)
+	}
+}
+
+// trigger runs the task on the cluster members selected by its run mode.
+func (s *Scheduler) trigger(ctx context.Context, t *scheduledTask) {
+	st := s.state()
+	err := st.Database().IsOpen(ctx)
+	if err != nil {
+		logger.Debug("Skipping task run as the database is not ready", logger.Ctx{"task": t.Name, "error": err})
+		return
+	}
+
+	if t.RunMode == types.TaskRunEveryMember {
+		_ = s.run(ctx, st, t)
+		return
+	}
+
+	leader, err := isLeader(ctx, st)
+	if err != nil {
+		logger.Warn("Failed to determine dqlite leader for task", logger.Ctx{"task": t.Name, "error": err})
+		return
+	}
+
+	if !leader {
+		return
+	}
+
+	if t.RunMode == types.TaskRunLeader {
+		_ = s.run(ctx, st, t)
+		return
+	}
+
+	var remotes map[string]trust.Remote
+	if st.Remotes() != nil {
+		remotes = st.Remotes().RemotesByName()
+	}
+
+	names := make([]string, 0, len(remotes))
+	for name := range remotes {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	if len(names) == 0 {
+		_ = s.run(ctx, st, t)
+		return
+	}
+
+	name := names[rand.IntN(len(names))]
+	if name == st.Name() {
+		_ = s.run(ctx, st, t)
+		return
+	}
+
+	if t.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	err = t.begin(name)
+	if err != nil {
+		logger.Warn("Skipping task run", logger.Ctx{"task": t.Name, "error": err})
+		return
+	}
+
+	err = runRemote(ctx, st, remotes[name], t.Name)
+	t.finish(err)
+	if err != nil {
+		logger.Warn("Task failed on cluster member", logger.Ctx{"task": t.Name, "member": name, "error": err})
+	}
+}
+
+// run runs the task on the local cluster member and records the result.
+func (s *Scheduler) run(ctx context.Context, st state.State, t *scheduledTask) error {
+	if t.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	err := t.begin(st.Name())
+	if err != nil {
+		return err
+	}
+
+	err = t.Run(ctx, st)
+	t.finish(err)
+	if err != nil {
+		logger.Warn("Task failed", logger.Ctx{"task": t.Name, "error": err})
+		return fmt.Errorf("Task %q failed: %w", t.Name, err)
+	}
+
+	return nil
+}
+
+// begin records the start of a run on the given cluster member. An error is returned if the task is already running.
+func (t *scheduledTask) begin(member string) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.status.Running {
+		return api.StatusErrorf(http.StatusConflict, "Task %q is already running", t.Name)
+	}
+
+	t.status.Running = true
+	t.status.LastRun = time.Now().UTC()
+	t.status.LastMember = member
+	t.status.LastError = ""
+
+	return nil
+}
+
+// finish records the result of a run.
+func (t *scheduledTask) finish(err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.status.Running = false
+	if err != nil {
+		t.status.LastError = err.Error()
+	}
+}
+
+// isLeader returns whether the local cluster member is the dqlite leader.
+func isLeader(ctx context.Context, s state.State) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	leaderClient, err := s.Database().Leader(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer leaderClient.Close()
+
+	leaderInfo, err := leaderClient.Leader(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return leaderInfo.Address == s.Address().URL.Host, nil
+}
+
+// runRemote asks the given cluster member to run the named task, and waits for the run to finish.
+func runRemote(ctx context.Context, s state.State, remote trust.Remote, name string) error {
+	publicKey, err := s.ClusterCert().PublicKeyX509()
+	if err != nil {
+		return err
+	}
+
+	c, err := internalClient.New(remote.URL(), s.ServerCert(), publicKey, false, s.AlternateClusterCerts()...)
+	if err != nil {
+		return err
+	}
+
+	return internalClient.RunTask(ctx, c, name)
+}
@@ -0,0 +1,163 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type tasksSuite struct {
+	suite.Suite
+}
+
+func TestTasksSuite(t *testing.T) {
+	suite.Run(t, new(tasksSuite))
+}
+
+func noop(ctx context.Context, s state.State) error { return nil }
+
+func (t *tasksSuite) Test_newScheduler() {
+	cases := []struct {
+		name         string
+		task         Task
+		expectErr    bool
+		expectStatus types.TaskStatus
+	}{
+		{
+			name:         "Interval task with default run mode",
+			task:         Task{Name: "t", Interval: time.Minute, Run: noop},
+			expectStatus: types.TaskStatus{Name: "t", Schedule: "every 1m0s", RunMode: types.TaskRunLeader},
+		},
+		{
+			name:         "Cron task",
+			task:         Task{Name: "t", Schedule: "*/5 * * * *", RunMode: types.TaskRunRandomMember, Run: noop},
+			expectStatus: types.TaskStatus{Name: "t", Schedule: "*/5 * * * *", RunMode: types.TaskRunRandomMember},
+		},
+		{
+			name:      "Missing name",
+			task:      Task{Interval: time.Minute, Run: noop},
+			expectErr: true,
+		},
+		{
+			name:      "Missing run function",
+			task:      Task{Name: "t", Interval: time.Minute},
+			expectErr: true,
+		},
+		{
+			name:      "Missing schedule",
+			task:      Task{Name: "t", Run: noop},
+			expectErr: true,
+		},
+		{
+			name:      "Interval and schedule",
+			task:      Task{Name: "t", Interval: time.Minute, Schedule: "* * * * *", Run: noop},
+			expectErr: true,
+		},
+		{
+			name:      "Invalid schedule",
+			task:      Task{Name: "t", Schedule: "every minute", Run: noop},
+			expectErr: true,
+		},
+		{
+			name:      "Invalid run mode",
+			task:      Task{Name: "t", Interval: time.Minute, RunMode: "some-members", Run: noop},
+			expectErr: true,
+		},
+		{
+			name:      "Negative jitter",
+			task:      Task{Name: "t", Interval: time.Minute, Jitter: -time.Second, Run: noop},
+			expectErr: true,
+		},
+	}
+
+	for i, c := range cases {
+		t.T().Logf("%s (case %d)", c.name, i)
+
+		s, err := NewScheduler([]Task{c.task}, nil)
+		if c.expectErr {
+			t.Error(err)
+			continue
+		}
+
+		t.Require().NoError(err)
+		t.Equal([]types.TaskStatus{c.expectStatus}, s.Status())
+	}
+
+	_, err := NewScheduler([]Task{{Name: "t", Interval: time.Minute, Run: noop}, {Name: "t", Interval: time.Minute, Run: noop}}, nil)
+	t.Error(err)
+}
+
+func (t *tasksSuite) Test_runTask() {
+	s := &state.InternalState{InternalName: func() string { return "n0" }}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	scheduler, err := NewScheduler([]Task{{
+		Name:     "t",
+		Interval: time.Hour,
+		Run: func(ctx context.Context, s state.State) error {
+			close(started)
+			<-release
+			return errors.New("Failed to do the thing")
+		},
+	}}, func() state.State { return s })
+	t.Require().NoError(err)
+
+	err = scheduler.RunTask(context.Background(), "missing")
+	t.True(api.StatusErrorCheck(err, http.StatusNotFound))
+
+	done := make(chan error)
+	go func() {
+		done <- scheduler.RunTask(context.Background(), "t")
+	}()
+
+	<-started
+	status := scheduler.Status()[0]
+	t.True(status.Running)
+	t.Equal("n0", status.LastMember)
+
+	// A task can't run more than once at a time.
+	err = scheduler.RunTask(context.Background(), "t")
+	t.True(api.StatusErrorCheck(err, http.StatusConflict))
+
+	close(release)
+	t.Error(<-done)
+
+	status = scheduler.Status()[0]
+	t.False(status.Running)
+	t.False(status.LastRun.IsZero())
+	t.Equal("Failed to do the thing", status.LastError)
+}
+
+func (t *tasksSuite) Test_stop() {
+	scheduler, err := NewScheduler([]Task{{Name: "t", Interval: time.Hour, Run: noop}}, nil)
+	t.Require().NoError(err)
+
+	// Stopping a scheduler that was never started does nothing.
+	t.NoError(scheduler.Stop(time.Second))
+
+	scheduler, err = NewScheduler([]Task{{Name: "t", Interval: time.Hour, Jitter: time.Minute, Run: noop}}, nil)
+	t.Require().NoError(err)
+
+	start := time.Now()
+	scheduler.Start(context.Background())
+	t.Eventually(func() bool { return !scheduler.Status()[0].NextRun.IsZero() }, 5*time.Second, 10*time.Millisecond)
+
+	nextRun := scheduler.Status()[0].NextRun
+	t.True(nextRun.After(start.Add(time.Hour)))
+	t.True(nextRun.Before(time.Now().Add(time.Hour + time.Minute)))
+
+	t.NoError(scheduler.Stop(5 * time.Second))
+
+	// The scheduler can't be restarted once stopped.
+	scheduler.Start(context.Background())
+	t.NoError(scheduler.Stop(time.Second))
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
@@ -21,6 +22,10 @@ import (
 	"github.com/canonical/microcluster/v2/rest/types"
 )
 
+// previousCertificateTrustPeriod is how long the previous certificate of a remote is trusted at most once it has been
+// replaced, in case the remote never authenticates with the new one.
+const previousCertificateTrustPeriod = time.Hour
+
 // Remotes is a convenient alias as we will often deal with groups of yaml files.
 type Remotes struct {
 	data     map[string]Remote
@@ -31,6 +36,17 @@ type Remotes struct {
 type Remote struct {
 	Location    `yaml:",inline"`
 	Certificate types.X509Certificate `yaml:"certificate"`
+
+	// PreviousCertificate is the certificate the remote presented before its certificate was replaced. It is
+	// trusted alongside the certificate until the remote authenticates with the new one, or until
+	// PreviousCertificateExpiry, whichever comes first.
+	PreviousCertificate       *types.X509Certificate `yaml:"previous_certificate,omitempty"`
+	PreviousCertificateExpiry time.Time              `yaml:"previous_certificate_expiry,omitempty"`
+}
+
+// trustsPreviousCertificate returns whether the previous certificate of the remote is still trusted.
+func (r Remote) trustsPreviousCertificate() bool {
+	return r.PreviousCertificate != nil && time.Now().Before(r.PreviousCertificateExpiry)
 }
 
 // Location represents configurable identifying information about a remote.
@@ -130,6 +146,8 @@ func (r *Remotes) Add(dir string, remotes ...Remote) error {
 }
 
 // Replace replaces the in-memory and locally stored remotes with the given list from the database.
+// If the certificate of a remote changes, its current certificate is kept as the previous one, which is trusted for
+// previousCertificateTrustPeriod at most.
 func (r *Remotes) Replace(dir string, newRemotes ...types.ClusterMember) error {
 	r.updateMu.Lock()
 	defer r.updateMu.Unlock()
@@ -149,6 +167,17 @@ func (r *Remotes) Replace(dir string, newRemotes ...types.ClusterMember) error {
 			return fmt.Errorf("Failed to parse local record %q. Found empty certificate", remote.Name)
 		}
 
+		existing, ok := r.data[remote.Name]
+		if ok && existing.Certificate.Certificate != nil {
+			if existing.Certificate.Equal(remote.Certificate.Certificate) {
+				newRemote.PreviousCertificate = existing.PreviousCertificate
+				newRemote.PreviousCertificateExpiry = existing.PreviousCertificateExpiry
+			} else {
+				newRemote.PreviousCertificate = &existing.Certificate
+				newRemote.PreviousCertificateExpiry = time.Now().Add(previousCertificateTrustPeriod)
+			}
+		}
+
 		bytes, err := yaml.Marshal(newRemote)
 		if err != nil {
 			return fmt.Errorf("Failed to parse remote %q to yaml: %w", remote.Name, err)
@@ -191,6 +220,34 @@ func (r *Remotes) Replace(dir string, newRemotes ...types.ClusterMember) error {
 	return nil
 }
 
+// ForgetPreviousCertificate stops trusting the previous certificate of the named remote.
+func (r *Remotes) ForgetPreviousCertificate(dir string, name string) error {
+	r.updateMu.Lock()
+	defer r.updateMu.Unlock()
+
+	remote, ok := r.data[name]
+	if !ok || remote.PreviousCertificate == nil {
+		return nil
+	}
+
+	remote.PreviousCertificate = nil
+	remote.PreviousCertificateExpiry = time.Time{}
+	bytes, err := yaml.Marshal(remote)
+	if err != nil {
+		return fmt.Errorf("Failed to parse remote %q to yaml: %w", remote.Name, err)
+	}
+
+	remotePath := filepath.Join(dir, fmt.Sprintf("%s.yaml", remote.Name))
+	err = renameio.WriteFile(remotePath, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to write %q: %w", remotePath, err)
+	}
+
+	r.data[name] = remote
+
+	return nil
+}
+
 // SelectRandom returns a random remote.
 func (r *Remotes) SelectRandom() *Remote {
 	r.updateMu.RLock()
@@ -218,11 +275,12 @@ func (r *Remotes) Addresses() map[string]types.AddrPort {
 }
 
 // Cluster returns a set of clients for every remote, which can be concurrently queried.
-func (r *Remotes) Cluster(isNotification bool, serverCert *shared.CertInfo, publicKey *x509.Certificate) (client.Cluster, error) {
+// The remotes may also present any of the alternatePublicKeys, such as the next or previous cluster certificate while it is rotated.
+func (r *Remotes) Cluster(isNotification bool, serverCert *shared.CertInfo, publicKey *x509.Certificate, alternatePublicKeys ...*x509.Certificate) (client.Cluster, error) {
 	cluster := make(client.Cluster, 0, r.Count()-1)
 	for _, addr := range r.Addresses() {
 		url := api.NewURL().Scheme("https").Host(addr.String())
-		c, err := internalClient.New(*url, serverCert, publicKey, isNotification)
+		c, err := internalClient.New(*url, serverCert, publicKey, isNotification, alternatePublicKeys...)
 		if err != nil {
 			return nil, err
 		}
@@ -247,7 +305,8 @@ func (r *Remotes) RemoteByAddress(addrPort types.AddrPort) *Remote {
 	return nil
 }
 
-// RemoteByCertificateFingerprint returns a remote whose certificate fingerprint matches the provided fingerprint.
+// RemoteByCertificateFingerprint returns a remote whose certificate, or previous certificate, fingerprint matches the
+// provided fingerprint.
 func (r *Remotes) RemoteByCertificateFingerprint(fingerprint string) *Remote {
 	r.updateMu.RLock()
 	defer r.updateMu.RUnlock()
@@ -256,6 +315,10 @@ func (r *Remotes) RemoteByCertificateFingerprint(fingerprint string) *Remote {
 		if fingerprint == shared.CertFingerprint(remote.Certificate.Certificate) {
 			return &remote
 		}
+
+		if remote.trustsPreviousCertificate() && fingerprint == shared.CertFingerprint(remote.PreviousCertificate.Certificate) {
+			return &remote
+		}
 	}
 
 	return nil
@@ -275,6 +338,7 @@ func (r *Remotes) Certificates() map[string]types.X509Certificate {
 }
 
 // CertificatesNative returns the Certificates map with values as native x509.Certificate type.
+// The previous certificates of remotes are included, as they are still trusted.
 func (r *Remotes) CertificatesNative() map[string]x509.Certificate {
 	r.updateMu.RLock()
 	defer r.updateMu.RUnlock()
@@ -282,6 +346,9 @@ func (r *Remotes) CertificatesNative() map[string]x509.Certificate {
 	certMap := map[string]x509.Certificate{}
 	for _, remote := range r.data {
 		certMap[shared.CertFingerprint(remote.Certificate.Certificate)] = *remote.Certificate.Certificate
+		if remote.trustsPreviousCertificate() {
+			certMap[shared.CertFingerprint(remote.PreviousCertificate.Certificate)] = *remote.PreviousCertificate.Certificate
+		}
 	}
 
 	return certMap
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
 	"golang.org/x/sys/unix"
@@ -23,6 +24,7 @@ import (
 	internalClient "github.com/canonical/microcluster/v2/internal/rest/client"
 	internalTypes "github.com/canonical/microcluster/v2/internal/rest/types"
 	"github.com/canonical/microcluster/v2/internal/sys"
+	"github.com/canonical/microcluster/v2/rest/openapi"
 	"github.com/canonical/microcluster/v2/rest/types"
 )
 
@@ -42,6 +44,13 @@ type Args struct {
 
 	Client *client.Client
 	Proxy  func(*http.Request) (*url.URL, error)
+
+	// ClientCert is the keypair RemoteClient authenticates with, instead of the server certificate in the state directory.
+	// This lets tools that are not cluster members connect with a trusted client certificate, see TrustClientCertificate.
+	ClientCert *shared.CertInfo
+
+	// ClusterCert is the cluster certificate RemoteClient verifies cluster members with, if the state directory has none.
+	ClusterCert *x509.Certificate
 }
 
 // App returns an instance of MicroCluster with a newly initialized filesystem if one does not exist.
@@ -219,8 +228,8 @@ func (m *MicroCluster) GetDqliteClusterMembers() ([]cluster.DqliteMember, error)
 //
 // RecoverFromQuorumLoss should be invoked _exactly once_ for the entire cluster.
 // This function creates a gz-compressed tarball and returns its path. This
-// tarball should be manually copied by the user to the state dir of all other
-// cluster members.
+// tarball should be copied to the state dir of all other cluster members,
+// either manually or with MicroCluster.PushRecoveryTarball.
 //
 // On start, Microcluster will automatically check for & load the recovery
 // tarball. A database backup will be taken before the load.
@@ -239,6 +248,57 @@ func (m *MicroCluster) RecoverFromQuorumLoss(members []cluster.DqliteMember) (st
 	return recover.RecoverFromQuorumLoss(m.FileSystem, members)
 }
 
+// PlanRecovery reports the changes that RecoverFromQuorumLoss would make with
+// the given cluster configuration, without making them. The plan includes any
+// validation error, the members that would lose their voter role, and the
+// trust store and daemon.yaml entries that would be rewritten.
+//
+// The plan also summarises the local raft log. Comparing the plans of each
+// surviving cluster member shows which of them has the most up-to-date log and
+// should run RecoverFromQuorumLoss.
+func (m *MicroCluster) PlanRecovery(members []cluster.DqliteMember) (*types.RecoveryPlan, error) {
+	return recover.PlanRecovery(m.FileSystem, members)
+}
+
+// PushRecoveryTarball sends the tarball created by RecoverFromQuorumLoss to all
+// other cluster members over the network, instead of copying it manually.
+// Each cluster member is contacted at its address in the new cluster
+// configuration, and must already be running. The local server certificate is
+// used to authenticate with each member, which verifies that it is part of the
+// new cluster configuration before staging the tarball.
+//
+// Cluster members load the tarball the next time they start, so they must be
+// restarted once the push has succeeded. A result is returned for each member;
+// pushes to other members are still attempted if one fails.
+func (m *MicroCluster) PushRecoveryTarball(ctx context.Context, tarballPath string) ([]types.RecoveryPushResult, error) {
+	return recover.PushRecoveryTarball(ctx, m.FileSystem, tarballPath)
+}
+
+// Backup writes a gzip-compressed tarball to the given writer, containing a consistent snapshot of the database
+// along with the schema versions and API extensions of each cluster member.
+// The database remains online while the snapshot is taken. Secrets, like join tokens and certificate keys, are left out.
+func (m *MicroCluster) Backup(ctx context.Context, w io.Writer) error {
+	c, err := m.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	return internalClient.BackupDatabase(ctx, &c.Client, w)
+}
+
+// Restore replaces the contents of the database with those of a tarball created by Backup.
+// The backup must have been taken with the same schema version as the running database.
+// Tables describing the live state of the cluster, like its members and join tokens, are not restored,
+// nor are the client certificates, roles and bindings that grant access to it.
+func (m *MicroCluster) Restore(ctx context.Context, r io.Reader) error {
+	c, err := m.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	return internalClient.RestoreDatabase(ctx, &c.Client, r)
+}
+
 // NewJoinToken creates and records a new join token containing all the necessary credentials for joining a cluster.
 // Join tokens are tied to the server certificate of the joining node, and will be deleted once the node has joined the
 // cluster.
@@ -286,6 +346,18 @@ func (m *MicroCluster) RevokeJoinToken(ctx context.Context, name string) error {
 	return nil
 }
 
+// OpenAPI returns the OpenAPI document describing the resources served by the named listener of the local daemon.
+// The listener is either "core" for the core API listener, "unix" for the control socket, or the name of an
+// additional listener. If no listener is given, the document of the core API listener is returned.
+func (m *MicroCluster) OpenAPI(ctx context.Context, listener string) (*openapi.Document, error) {
+	c, err := m.LocalClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetOpenAPI(ctx, listener)
+}
+
 // LocalClient returns a client connected to the local control socket.
 func (m *MicroCluster) LocalClient() (*client.Client, error) {
 	c := m.args.Client
@@ -312,26 +384,38 @@ func (m *MicroCluster) LocalClient() (*client.Client, error) {
 }
 
 // RemoteClient gets a client for the specified cluster member URL.
-// The filesystem will be parsed for the cluster and server certificates.
+// The filesystem will be parsed for the cluster and server certificates, unless ClientCert and ClusterCert are set in Args.
 func (m *MicroCluster) RemoteClient(address string) (*client.Client, error) {
 	c := m.args.Client
 	if c == nil {
-		serverCert, err := m.FileSystem.ServerCert()
-		if err != nil {
-			return nil, err
+		clientCert := m.args.ClientCert
+		if clientCert == nil {
+			serverCert, err := m.FileSystem.ServerCert()
+			if err != nil {
+				return nil, err
+			}
+
+			clientCert = serverCert
 		}
 
-		var publicKey *x509.Certificate
+		publicKey := m.args.ClusterCert
+		var alternatePublicKeys []*x509.Certificate
 		clusterCert, err := m.FileSystem.ClusterCert()
 		if err == nil {
 			publicKey, err = clusterCert.PublicKeyX509()
 			if err != nil {
 				return nil, err
 			}
+
+			// Cluster members may serve either certificate while the cluster certificate is rotated.
+			alternatePublicKeys, err = m.FileSystem.AlternateClusterCerts()
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		url := api.NewURL().Scheme("https").Host(address)
-		internalClient, err := internalClient.New(*url, serverCert, publicKey, false)
+		internalClient, err := internalClient.New(*url, clientCert, publicKey, false, alternatePublicKeys...)
 		if err != nil {
 			return nil, err
 		}
@@ -352,6 +436,55 @@ func (m *MicroCluster) RemoteClient(address string) (*client.Client, error) {
 	return c, nil
 }
 
+// TrustClientCertificate redeems a token issued by a cluster member to have ClientCert from Args trusted by the cluster.
+// The cluster members in the token are tried in turn, once their certificate is verified against the fingerprint in the
+// token. Returns the cluster certificate, which can be set as ClusterCert in Args to connect with RemoteClient.
+func (m *MicroCluster) TrustClientCertificate(ctx context.Context, token string) (*x509.Certificate, error) {
+	if m.args.ClientCert == nil {
+		return nil, fmt.Errorf("A client certificate is required to redeem a token")
+	}
+
+	decodedToken, err := internalTypes.DecodeToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid token: %w", err)
+	}
+
+	var lastErr error
+	for _, addr := range decodedToken.JoinAddresses {
+		url := api.NewURL().Scheme("https").Host(addr.String())
+		cert, err := shared.GetRemoteCertificate(url.String(), "")
+		if err != nil {
+			lastErr = fmt.Errorf("Failed to get certificate of cluster member %q: %w", addr.String(), err)
+			continue
+		}
+
+		fingerprint := shared.CertFingerprint(cert)
+		if fingerprint != decodedToken.Fingerprint {
+			lastErr = fmt.Errorf("Certificate of cluster member %q has fingerprint %q, expected %q", addr.String(), fingerprint, decodedToken.Fingerprint)
+			continue
+		}
+
+		c, err := internalClient.New(*url, m.args.ClientCert, cert, false)
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.RedeemClientCertificateToken(ctx, token)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return cert, nil
+	}
+
+	if lastErr == nil {
+		return nil, fmt.Errorf("Token has no cluster member addresses")
+	}
+
+	return nil, fmt.Errorf("Failed to redeem token: %w", lastErr)
+}
+
 // SQL performs either a GET or POST on /internal/sql with a given query. This is a useful helper for using direct SQL.
 func (m *MicroCluster) SQL(ctx context.Context, query string) (string, *internalTypes.SQLBatch, error) {
 	if query == "-" {
@@ -0,0 +1,113 @@
+package access
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/ucred"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcluster/v2/cluster"
+	"github.com/canonical/microcluster/v2/internal/auth"
+	"github.com/canonical/microcluster/v2/internal/rest/access"
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// Identify returns the identity of the client that sent the request, or nil if it did not present any credentials.
+// - Requests over the unix socket are identified by the UID and GID of the peer process.
+// - HTTP requests are identified by their TLS peer certificate, which is that of a cluster member if it is in the trust store.
+func Identify(s state.State, r *http.Request) (*types.Identity, error) {
+	if r.RemoteAddr == "@" {
+		cred, err := ucred.GetCredFromContext(r.Context())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get credentials of unix socket peer: %w", err)
+		}
+
+		return &types.Identity{Type: types.IdentityTypeUnix, Name: fmt.Sprintf("uid:%d", cred.Uid), UID: cred.Uid, GID: cred.Gid}, nil
+	}
+
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil, nil
+	}
+
+	fingerprint := shared.CertFingerprint(r.TLS.PeerCertificates[0])
+	remote := s.Remotes().RemoteByCertificateFingerprint(fingerprint)
+	if remote != nil {
+		return &types.Identity{Type: types.IdentityTypeClusterMember, Name: remote.Name}, nil
+	}
+
+	return &types.Identity{Type: types.IdentityTypeCertificate, Name: fingerprint}, nil
+}
+
+// GetIdentity returns the identity of the client that sent the request, as recorded when the request was authenticated.
+func GetIdentity(r *http.Request) (*types.Identity, error) {
+	trustedReq, err := request.GetCtxValue[access.TrustedRequest](r.Context(), request.CtxAccess)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Request has not been authenticated")
+	}
+
+	if trustedReq.Identity == nil {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Request has no identity")
+	}
+
+	return trustedReq.Identity, nil
+}
+
+// CheckPermission returns a 403 Forbidden error unless the client that sent the request is granted the entitlement.
+// - Cluster members and unix socket peers running as root are granted every entitlement.
+// - Until the daemon is part of a cluster, every authenticated client is granted every entitlement.
+// - Other clients are granted the entitlements of the roles bound to them by the authorization policy.
+//
+// The policy is cached for a short while. While the database is unavailable, the last loaded policy is used, or the
+// default policy granting every unix socket peer the admin role if it was never loaded.
+func CheckPermission(s state.State, r *http.Request, entitlement types.Entitlement) error {
+	if s.Database().Status() == types.DatabaseNotReady {
+		return nil
+	}
+
+	identity, err := GetIdentity(r)
+	if err != nil {
+		return err
+	}
+
+	if identity.Type == types.IdentityTypeClusterMember || (identity.Type == types.IdentityTypeUnix && identity.UID == 0) {
+		return nil
+	}
+
+	intState, err := internalState.ToInternal(s)
+	if err != nil {
+		return err
+	}
+
+	var policy types.AuthPolicy
+	if s.Database().IsOpen(r.Context()) != nil {
+		policy = intState.AuthPolicy.Last()
+	} else {
+		policy, err = intState.AuthPolicy.Get(func() (*types.AuthPolicy, error) {
+			var policy *types.AuthPolicy
+			err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+				var err error
+				policy, err = cluster.GetCoreAuthPolicy(ctx, tx)
+
+				return err
+			})
+
+			return policy, err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to get authorization policy: %w", err)
+		}
+	}
+
+	if !auth.Allowed(policy, *identity, entitlement) {
+		return api.StatusErrorf(http.StatusForbidden, "%s identity %q is not granted %q", identity.Type, identity.Name, entitlement)
+	}
+
+	return nil
+}
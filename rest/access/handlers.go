@@ -1,15 +1,21 @@
 package access
 
 import (
+	"context"
 	"crypto/x509"
+	"database/sql"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
 
+	"github.com/canonical/microcluster/v2/cluster"
 	"github.com/canonical/microcluster/v2/internal/endpoints"
 	"github.com/canonical/microcluster/v2/internal/rest/access"
 	internalState "github.com/canonical/microcluster/v2/internal/state"
@@ -47,9 +53,34 @@ func AllowAuthenticated(state state.State, r *http.Request) (bool, response.Resp
 	return true, nil
 }
 
+// AllowClusterMembers checks if the request is trusted and was sent by a cluster member or over the unix socket.
+// Trusted client certificates are rejected, as the internal API is only meant for communication between cluster members.
+// Until the daemon is part of a cluster, every authenticated client is allowed.
+func AllowClusterMembers(state state.State, r *http.Request) (bool, response.Response) {
+	trusted, resp := AllowAuthenticated(state, r)
+	if !trusted {
+		return false, resp
+	}
+
+	if state.Database().Status() == types.DatabaseNotReady {
+		return true, nil
+	}
+
+	identity, err := GetIdentity(r)
+	if err != nil {
+		return false, response.SmartError(err)
+	}
+
+	if identity.Type != types.IdentityTypeClusterMember && identity.Type != types.IdentityTypeUnix {
+		return false, response.Forbidden(fmt.Errorf("%s identity %q is not a cluster member", identity.Type, identity.Name))
+	}
+
+	return true, nil
+}
+
 // Authenticate ensures the request certificates are trusted against the given set of trusted certificates.
-// - Requests over the unix socket are always allowed.
-// - HTTP requests require the TLS Peer certificate to match an entry in the supplied map of certificates.
+// - Requests over the unix socket are always allowed, though they are still subject to CheckPermission.
+// - HTTP requests require the TLS Peer certificate to match the supplied certificates, or a trusted client certificate.
 func Authenticate(state state.State, r *http.Request, hostAddress string, trustedCerts map[string]x509.Certificate) (bool, error) {
 	if r.RemoteAddr == "@" {
 		return true, nil
@@ -84,10 +115,13 @@ func Authenticate(state state.State, r *http.Request, hostAddress string, truste
 				trusted, fingerprint := util.CheckMutualTLS(*cert, trustedCerts)
 				if trusted {
 					logger.Debugf("Trusting HTTP request to %q from %q with fingerprint %q", r.URL.String(), r.RemoteAddr, fingerprint)
+					forgetPreviousCertificate(state, fingerprint)
 
 					return trusted, nil
 				}
 			}
+
+			return trustedClientCertificate(state, r), nil
 		}
 	default:
 		return false, ErrInvalidHost{error: fmt.Errorf("Invalid request address %q", r.Host)}
@@ -95,3 +129,49 @@ func Authenticate(state state.State, r *http.Request, hostAddress string, truste
 
 	return false, nil
 }
+
+// forgetPreviousCertificate stops trusting the previous certificate of the cluster member whose current certificate has
+// the given fingerprint, as the cluster member no longer needs it to authenticate.
+func forgetPreviousCertificate(s state.State, fingerprint string) {
+	remote := s.Remotes().RemoteByCertificateFingerprint(fingerprint)
+	if remote == nil || remote.PreviousCertificate == nil || shared.CertFingerprint(remote.Certificate.Certificate) != fingerprint {
+		return
+	}
+
+	err := s.Remotes().ForgetPreviousCertificate(s.FileSystem().TrustDir, remote.Name)
+	if err != nil {
+		logger.Warn("Failed to forget previous certificate of cluster member", logger.Ctx{"name": remote.Name, "error": err})
+	}
+}
+
+// trustedClientCertificate returns whether the TLS peer certificate of the request is a trusted client certificate.
+// Client certificates are stored in the database, so they are not trusted while it is unavailable.
+func trustedClientCertificate(s state.State, r *http.Request) bool {
+	if s.Database().IsOpen(r.Context()) != nil {
+		return false
+	}
+
+	cert := r.TLS.PeerCertificates[0]
+	now := time.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return false
+	}
+
+	fingerprint := shared.CertFingerprint(cert)
+	err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		_, err := cluster.GetCoreClientCertificateByFingerprint(ctx, tx, fingerprint)
+
+		return err
+	})
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			logger.Warn("Failed to check client certificate", logger.Ctx{"fingerprint": fingerprint, "error": err})
+		}
+
+		return false
+	}
+
+	logger.Debugf("Trusting HTTP request to %q from %q with client certificate fingerprint %q", r.URL.String(), r.RemoteAddr, fingerprint)
+
+	return true
+}
@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/response"
+
+	internalAccess "github.com/canonical/microcluster/v2/internal/rest/access"
+	internalState "github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/rest/access"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// Handler handles a request to an API endpoint.
+type Handler func(s state.State, r *http.Request) response.Response
+
+// Middleware wraps the handling of requests to an API endpoint. It can inspect or replace the request before calling
+// next, return its own response without calling next, or inspect or replace the response returned by next.
+//
+// Middlewares run in a fixed order for each request:
+//   - The built-in middlewares returned by DefaultMiddlewares.
+//   - The middlewares of the Server serving the endpoint, in the order they are listed.
+//   - The middlewares of the Endpoint, in the order they are listed.
+//   - The access checks of the EndpointAction for the request method, including the check that the client is granted its
+//     Entitlement, and forwarding to the ?target= cluster member if ProxyTarget is set.
+//   - The Handler of the EndpointAction.
+type Middleware func(next Handler) Handler
+
+// Chain returns a handler that runs the given middlewares in order before the handler. The first middleware is the outermost.
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// DefaultMiddlewares returns the built-in middlewares run before any other middleware of the endpoint, in order:
+// CheckShutdown, CheckInit and Authenticate.
+func DefaultMiddlewares(e Endpoint) []Middleware {
+	return []Middleware{
+		CheckShutdown(e.AllowedDuringShutdown),
+		CheckInit(e.AllowedBeforeInit),
+		Authenticate(),
+	}
+}
+
+// CheckShutdown returns a middleware that rejects requests with 503 Service Unavailable while the daemon is shutting down,
+// unless allowed is true.
+func CheckShutdown(allowed bool) Middleware {
+	return func(next Handler) Handler {
+		return func(s state.State, r *http.Request) response.Response {
+			intState, err := internalState.ToInternal(s)
+			if err != nil {
+				return response.BadRequest(err)
+			}
+
+			if !allowed && intState.Context.Err() == context.Canceled {
+				return response.Unavailable(fmt.Errorf("Daemon is shutting down"))
+			}
+
+			return next(s, r)
+		}
+	}
+}
+
+// CheckInit returns a middleware that rejects requests while the database is not open, for instance before the daemon
+// has bootstrapped or joined a cluster, unless allowed is true.
+func CheckInit(allowed bool) Middleware {
+	return func(next Handler) Handler {
+		return func(s state.State, r *http.Request) response.Response {
+			if !allowed {
+				err := s.Database().IsOpen(r.Context())
+				if err != nil {
+					return response.SmartError(err)
+				}
+			}
+
+			return next(s, r)
+		}
+	}
+}
+
+// Authenticate returns a middleware that records whether the request comes from a trusted client, along with the
+// identity of the client, for use by the access checks of the endpoint action. Requests whose authentication fails
+// outright are rejected with 403 Forbidden.
+func Authenticate() Middleware {
+	return func(next Handler) Handler {
+		return func(s state.State, r *http.Request) response.Response {
+			trusted, err := access.Authenticate(s, r, s.Address().URL.Host, s.Remotes().CertificatesNative())
+			if err != nil && !errors.As(err, &access.ErrInvalidHost{}) {
+				return response.Forbidden(fmt.Errorf("Failed to authenticate request: %w", err))
+			}
+
+			identity, err := access.Identify(s, r)
+			if err != nil {
+				return response.Forbidden(fmt.Errorf("Failed to identify client: %w", err))
+			}
+
+			return next(s, internalAccess.SetRequestIdentity(r, trusted, identity))
+		}
+	}
+}
+
+// ResourcesWithMiddlewares returns the resources of the server, with the middlewares of the server added before those
+// of each endpoint.
+func (s Server) ResourcesWithMiddlewares() []Resources {
+	if len(s.Middlewares) == 0 {
+		return s.Resources
+	}
+
+	resources := make([]Resources, 0, len(s.Resources))
+	for _, res := range s.Resources {
+		endpoints := make([]Endpoint, 0, len(res.Endpoints))
+		for _, e := range res.Endpoints {
+			e.Middlewares = append(append([]Middleware{}, s.Middlewares...), e.Middlewares...)
+			endpoints = append(endpoints, e)
+		}
+
+		resources = append(resources, Resources{PathPrefix: res.PathPrefix, Endpoints: endpoints})
+	}
+
+	return resources
+}
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/canonical/lxd/lxd/response"
+
+	"github.com/canonical/microcluster/v2/state"
+)
+
+// recordMiddleware returns a middleware that records its name before and after calling the next handler.
+func recordMiddleware(name string, calls *[]string) Middleware {
+	return func(next Handler) Handler {
+		return func(s state.State, r *http.Request) response.Response {
+			*calls = append(*calls, name)
+			resp := next(s, r)
+			*calls = append(*calls, "/"+name)
+
+			return resp
+		}
+	}
+}
+
+func (s *restSuite) Test_chain() {
+	calls := []string{}
+	handler := func(state state.State, r *http.Request) response.Response {
+		calls = append(calls, "handler")
+
+		return response.EmptySyncResponse
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/1.0", nil)
+	resp := Chain(handler, recordMiddleware("a", &calls), recordMiddleware("b", &calls))(nil, req)
+	s.Equal(response.EmptySyncResponse, resp)
+	s.Equal([]string{"a", "b", "handler", "/b", "/a"}, calls)
+
+	// A middleware can return its own response without calling the rest of the chain.
+	calls = []string{}
+	reject := func(next Handler) Handler {
+		return func(s state.State, r *http.Request) response.Response {
+			return response.Forbidden(fmt.Errorf("Rejected"))
+		}
+	}
+
+	resp = Chain(handler, recordMiddleware("a", &calls), reject, recordMiddleware("b", &calls))(nil, req)
+	s.Equal(response.Forbidden(fmt.Errorf("Rejected")), resp)
+	s.Equal([]string{"a", "/a"}, calls)
+
+	// Without middlewares the handler is called directly.
+	calls = []string{}
+	Chain(handler)(nil, req)
+	s.Equal([]string{"handler"}, calls)
+}
+
+func (s *restSuite) Test_resourcesWithMiddlewares() {
+	calls := []string{}
+	handler := func(state state.State, r *http.Request) response.Response {
+		calls = append(calls, "handler")
+
+		return response.EmptySyncResponse
+	}
+
+	endpointMiddleware := recordMiddleware("endpoint", &calls)
+	server := Server{
+		Resources: []Resources{
+			{
+				PathPrefix: "1.0",
+				Endpoints: []Endpoint{
+					{Path: "a", Middlewares: []Middleware{endpointMiddleware}},
+					{Path: "b"},
+				},
+			},
+		},
+	}
+
+	// Without server middlewares the resources are unchanged.
+	resources := server.ResourcesWithMiddlewares()
+	s.Len(resources[0].Endpoints[0].Middlewares, 1)
+	s.Empty(resources[0].Endpoints[1].Middlewares)
+
+	server.Middlewares = []Middleware{recordMiddleware("server", &calls)}
+	resources = server.ResourcesWithMiddlewares()
+	s.Len(resources, 1)
+	s.Equal(server.Resources[0].PathPrefix, resources[0].PathPrefix)
+	s.Len(resources[0].Endpoints, 2)
+
+	req := httptest.NewRequest(http.MethodGet, "/1.0/a", nil)
+	Chain(handler, resources[0].Endpoints[0].Middlewares...)(nil, req)
+	s.Equal([]string{"server", "endpoint", "handler", "/endpoint", "/server"}, calls)
+
+	calls = []string{}
+	Chain(handler, resources[0].Endpoints[1].Middlewares...)(nil, req)
+	s.Equal([]string{"server", "handler", "/server"}, calls)
+
+	// The endpoints of the server are left untouched.
+	s.Len(server.Resources[0].Endpoints[0].Middlewares, 1)
+	s.Empty(server.Resources[0].Endpoints[1].Middlewares)
+}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/canonical/microcluster/v2/rest/openapi"
+)
+
+// OpenAPI returns an OpenAPI document describing the given resources, as served by a single listener.
+func OpenAPI(info openapi.Info, resources ...Resources) *openapi.Document {
+	doc := openapi.New(info)
+	for _, res := range resources {
+		for _, e := range res.Endpoints {
+			paths := []string{e.Path}
+			for _, alias := range e.Aliases {
+				paths = append(paths, alias.Path)
+			}
+
+			for _, endpointPath := range paths {
+				urlPath := "/" + string(res.PathPrefix)
+				if endpointPath != "" {
+					urlPath = path.Join(urlPath, endpointPath)
+				}
+
+				actions := []struct {
+					method string
+					action EndpointAction
+				}{
+					{method: http.MethodGet, action: e.Get},
+					{method: http.MethodPut, action: e.Put},
+					{method: http.MethodPost, action: e.Post},
+					{method: http.MethodDelete, action: e.Delete},
+					{method: http.MethodPatch, action: e.Patch},
+				}
+
+				for _, a := range actions {
+					if a.action.Handler == nil {
+						continue
+					}
+
+					doc.AddOperation(urlPath, a.method, openAPIOperation(doc, e, a.method, a.action))
+				}
+			}
+		}
+	}
+
+	return doc
+}
+
+// openAPIOperation returns the description of the endpoint action, including how requests are authenticated,
+// forwarded and gated on the state of the daemon.
+func openAPIOperation(doc *openapi.Document, e Endpoint, method string, action EndpointAction) *openapi.Operation {
+	op := &openapi.Operation{
+		Summary:               action.Summary,
+		AllowedBeforeInit:     e.AllowedBeforeInit,
+		AllowedDuringShutdown: e.AllowedDuringShutdown,
+		Responses: map[string]*openapi.Response{
+			"200":     {Description: "Success", Content: openapi.JSONContent(doc.ResponseSchema(action.Response))},
+			"default": {Description: "Error", Content: openapi.JSONContent(doc.ResponseSchema(nil))},
+		},
+	}
+
+	// Untrusted requests are still subject to the access handler, if any.
+	if action.AllowUntrusted {
+		op.Security = &[]openapi.SecurityRequirement{}
+	} else {
+		op.Entitlement = string(action.RequiredEntitlement(method))
+	}
+
+	if action.ProxyTarget {
+		op.Parameters = append(op.Parameters, openapi.Parameter{
+			Name:        "target",
+			In:          "query",
+			Description: "Name of the cluster member to forward the request to",
+			Schema:      &openapi.Schema{Type: "string"},
+		})
+	}
+
+	if action.Request != nil {
+		op.RequestBody = &openapi.RequestBody{Required: true, Content: openapi.JSONContent(doc.Schema(action.Request))}
+	}
+
+	if !e.AllowedBeforeInit {
+		op.Responses["503"] = &openapi.Response{Description: "The daemon has not bootstrapped or joined a cluster yet", Content: openapi.JSONContent(doc.ResponseSchema(nil))}
+	}
+
+	return op
+}
@@ -0,0 +1,361 @@
+// Package openapi describes APIs as OpenAPI documents, deriving schemas from Go types.
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Version is the version of the OpenAPI specification that generated documents conform to.
+const Version = "3.1.0"
+
+// MutualTLS is the name of the security scheme used by operations that require a trusted client certificate.
+const MutualTLS = "mutualTLS"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string                `json:"openapi" yaml:"openapi"`
+	Info       Info                  `json:"info" yaml:"info"`
+	Paths      map[string]*PathItem  `json:"paths" yaml:"paths"`
+	Components Components            `json:"components" yaml:"components"`
+	Security   []SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`
+
+	names map[reflect.Type]string
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title" yaml:"title"`
+	Version     string `json:"version" yaml:"version"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// PathItem describes the operations available on a path.
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty" yaml:"get,omitempty"`
+	Put    *Operation `json:"put,omitempty" yaml:"put,omitempty"`
+	Post   *Operation `json:"post,omitempty" yaml:"post,omitempty"`
+	Delete *Operation `json:"delete,omitempty" yaml:"delete,omitempty"`
+	Patch  *Operation `json:"patch,omitempty" yaml:"patch,omitempty"`
+
+	Parameters []Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	OperationID string                 `json:"operationId" yaml:"operationId"`
+	Summary     string                 `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Parameters  []Parameter            `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses" yaml:"responses"`
+	Security    *[]SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`
+
+	// AllowedBeforeInit is whether the operation is available before the daemon has joined or bootstrapped a cluster.
+	AllowedBeforeInit bool `json:"x-allowed-before-init" yaml:"x-allowed-before-init"`
+
+	// AllowedDuringShutdown is whether the operation is available while the daemon is shutting down.
+	AllowedDuringShutdown bool `json:"x-allowed-during-shutdown" yaml:"x-allowed-during-shutdown"`
+
+	// Entitlement is the entitlement clients other than cluster members must be granted to perform the operation.
+	Entitlement string `json:"x-entitlement,omitempty" yaml:"x-entitlement,omitempty"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Name        string  `json:"name" yaml:"name"`
+	In          string  `json:"in" yaml:"in"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool    `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// RequestBody describes the body of a request.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]MediaType `json:"content" yaml:"content"`
+}
+
+// Response describes a response from an operation.
+type Response struct {
+	Description string               `json:"description" yaml:"description"`
+	Content     map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// MediaType describes the content of a request or response body.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// Components holds the reusable schemas and security schemes of the document.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes a way to authenticate with the API.
+type SecurityScheme struct {
+	Type        string `json:"type" yaml:"type"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// SecurityRequirement lists the security schemes that must be satisfied, keyed by name.
+type SecurityRequirement map[string][]string
+
+// Schema describes a data type.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Description          string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+}
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// New returns an empty OpenAPI document for the given API. All operations require a trusted client certificate by default.
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Response": responseSchema(),
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				MutualTLS: {Type: MutualTLS, Description: "Client certificate trusted by the cluster. Not required over the unix socket."},
+			},
+		},
+		Security: []SecurityRequirement{{MutualTLS: []string{}}},
+	}
+}
+
+// responseSchema returns the schema of the envelope wrapping every response.
+func responseSchema() *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"type":        {Type: "string", Description: "Response type: sync, async or error"},
+			"status":      {Type: "string"},
+			"status_code": {Type: "integer"},
+			"operation":   {Type: "string", Description: "URL of the background operation, for async responses"},
+			"error_code":  {Type: "integer"},
+			"error":       {Type: "string"},
+			"metadata":    {Description: "Response data"},
+		},
+	}
+}
+
+// ResponseSchema returns the schema of a response envelope whose metadata is a value of the same type as the given one.
+// If metadata is nil, the metadata is left unspecified.
+func (d *Document) ResponseSchema(metadata any) *Schema {
+	envelope := &Schema{Ref: "#/components/schemas/Response"}
+	if metadata == nil {
+		return envelope
+	}
+
+	return &Schema{AllOf: []*Schema{envelope, {Type: "object", Properties: map[string]*Schema{"metadata": d.Schema(metadata)}}}}
+}
+
+// AddOperation adds the operation for the given method at the given path. Path parameters in braces are declared on the path.
+func (d *Document) AddOperation(urlPath string, method string, op *Operation) {
+	item := d.Paths[urlPath]
+	if item == nil {
+		item = &PathItem{}
+		for _, segment := range strings.Split(urlPath, "/") {
+			if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+				item.Parameters = append(item.Parameters, Parameter{Name: strings.Trim(segment, "{}"), In: "path", Required: true, Schema: &Schema{Type: "string"}})
+			}
+		}
+
+		d.Paths[urlPath] = item
+	}
+
+	if op.OperationID == "" {
+		op.OperationID = operationID(method, urlPath)
+	}
+
+	switch method {
+	case http.MethodGet:
+		item.Get = op
+	case http.MethodPut:
+		item.Put = op
+	case http.MethodPost:
+		item.Post = op
+	case http.MethodDelete:
+		item.Delete = op
+	case http.MethodPatch:
+		item.Patch = op
+	}
+}
+
+// JSONContent returns the content of a JSON body with the given schema.
+func JSONContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// operationID returns a unique identifier for the operation, derived from its method and path.
+func operationID(method string, urlPath string) string {
+	words := strings.FieldsFunc(urlPath, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(append([]string{strings.ToLower(method)}, words...), "_")
+}
+
+// Schema returns the schema of the type of the given value, following the encoding/json rules.
+// Named struct types are added to the document components and referenced.
+func (d *Document) Schema(v any) *Schema {
+	if d.names == nil {
+		d.names = map[reflect.Type]string{}
+	}
+
+	return d.schema(reflect.TypeOf(v))
+}
+
+// schema returns the schema of the given type. Named struct types are added to the document components and referenced.
+func (d *Document) schema(t reflect.Type) *Schema {
+	switch t {
+	case rawMessageType:
+		return &Schema{}
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "Duration in nanoseconds"}
+	}
+
+	// Types with custom encoding are encoded as strings throughout the API.
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return d.schema(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: d.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+
+		name, ok := d.names[t]
+		if !ok {
+			name = d.componentName(t)
+			d.names[t] = name
+
+			// Register the name before building the schema so that recursive types can reference it.
+			d.Components.Schemas[name] = &Schema{}
+			*d.Components.Schemas[name] = *d.structSchema(t)
+		}
+
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		return &Schema{}
+	}
+}
+
+// componentName returns a unique name for the given named type within the document components.
+func (d *Document) componentName(t reflect.Type) string {
+	name := t.Name()
+	_, taken := d.Components.Schemas[name]
+	if !taken {
+		return name
+	}
+
+	pkg := path.Base(t.PkgPath())
+	name = strings.ToUpper(pkg[:1]) + pkg[1:] + t.Name()
+	for i := 2; ; i++ {
+		_, taken := d.Components.Schemas[name]
+		if !taken {
+			return name
+		}
+
+		name = fmt.Sprintf("%s%s%d", strings.ToUpper(pkg[:1])+pkg[1:], t.Name(), i)
+	}
+}
+
+// structSchema returns the schema of the fields of a struct, following the encoding/json rules for field names.
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// Embedded structs without a name have their fields promoted.
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			embedded := d.structSchema(fieldType)
+			for key, value := range embedded.Properties {
+				schema.Properties[key] = value
+			}
+
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = d.schema(field.Type)
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	sort.Strings(schema.Required)
+
+	return schema
+}
@@ -0,0 +1,81 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/rest/types"
+)
+
+type openAPISuite struct {
+	suite.Suite
+}
+
+func TestOpenAPISuite(t *testing.T) {
+	suite.Run(t, new(openAPISuite))
+}
+
+type embedded struct {
+	Name string `json:"name"`
+}
+
+type node struct {
+	embedded
+
+	Address  types.AddrPort       `json:"address"`
+	Children []*node              `json:"children,omitempty"`
+	Labels   map[string]string    `json:"labels"`
+	Data     json.RawMessage      `json:"data"`
+	Seen     time.Time            `json:"seen"`
+	Timeout  time.Duration        `json:"timeout"`
+	Cert     []byte               `json:"cert"`
+	Parent   *node                `json:"parent"`
+	Ignored  string               `json:"-"`
+	Untagged bool                 //nolint:tagliatelle
+	internal string               //nolint:unused
+	Servers  []types.ServerConfig `json:"servers"`
+}
+
+func (s *openAPISuite) Test_schema() {
+	doc := New(Info{Title: "test", Version: "1.0"})
+
+	schema := doc.Schema([]node{})
+	s.Equal("array", schema.Type)
+	s.Equal("#/components/schemas/node", schema.Items.Ref)
+
+	nodeSchema := doc.Components.Schemas["node"]
+	s.Require().NotNil(nodeSchema)
+	s.Equal([]string{"Untagged", "address", "cert", "data", "labels", "name", "seen", "servers", "timeout"}, nodeSchema.Required)
+	s.Equal(&Schema{Type: "string"}, nodeSchema.Properties["name"])
+	s.Equal(&Schema{Type: "string"}, nodeSchema.Properties["address"])
+	s.Equal(&Schema{Type: "string", Format: "date-time"}, nodeSchema.Properties["seen"])
+	s.Equal("integer", nodeSchema.Properties["timeout"].Type)
+	s.Equal(&Schema{Type: "string", Format: "byte"}, nodeSchema.Properties["cert"])
+	s.Equal(&Schema{}, nodeSchema.Properties["data"])
+	s.Equal(&Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}, nodeSchema.Properties["labels"])
+	s.Equal("#/components/schemas/node", nodeSchema.Properties["parent"].Ref)
+	s.Equal("#/components/schemas/node", nodeSchema.Properties["children"].Items.Ref)
+	s.NotContains(nodeSchema.Properties, "Ignored")
+	s.NotContains(nodeSchema.Properties, "internal")
+	s.Contains(doc.Components.Schemas, "ServerConfig")
+}
+
+func (s *openAPISuite) Test_addOperation() {
+	doc := New(Info{Title: "test", Version: "1.0"})
+	doc.AddOperation("/1.0/items/{name}", http.MethodGet, &Operation{})
+	doc.AddOperation("/1.0/items/{name}", http.MethodDelete, &Operation{OperationID: "removeItem"})
+
+	item := doc.Paths["/1.0/items/{name}"]
+	s.Require().NotNil(item)
+	s.Equal([]Parameter{{Name: "name", In: "path", Required: true, Schema: &Schema{Type: "string"}}}, item.Parameters)
+	s.Equal("get_1_0_items_name", item.Get.OperationID)
+	s.Equal("removeItem", item.Delete.OperationID)
+
+	// The document must be valid JSON, without the internal type registry.
+	_, err := json.Marshal(doc)
+	s.NoError(err)
+}
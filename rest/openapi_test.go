@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcluster/v2/rest/openapi"
+	"github.com/canonical/microcluster/v2/rest/types"
+	"github.com/canonical/microcluster/v2/state"
+)
+
+type restSuite struct {
+	suite.Suite
+}
+
+func TestRestSuite(t *testing.T) {
+	suite.Run(t, new(restSuite))
+}
+
+func (s *restSuite) Test_openAPI() {
+	handler := func(state state.State, r *http.Request) response.Response { return response.EmptySyncResponse }
+
+	resources := Resources{
+		PathPrefix: "1.0",
+		Endpoints: []Endpoint{
+			{
+				Path:              "items/{name}",
+				Aliases:           []EndpointAlias{{Name: "item", Path: "things/{name}"}},
+				AllowedBeforeInit: true,
+
+				Get:    EndpointAction{Handler: handler, Summary: "Get an item", Response: types.ClusterConfig{}, ProxyTarget: true, Entitlement: types.EntitlementConfigRead},
+				Put:    EndpointAction{Handler: handler, Request: types.ClusterConfig{}, AllowUntrusted: true},
+				Delete: EndpointAction{},
+			},
+			{
+				Path: "items",
+
+				Get: EndpointAction{Handler: handler},
+			},
+		},
+	}
+
+	doc := OpenAPI(openapi.Info{Title: "test", Version: "1.0"}, resources)
+	s.Len(doc.Paths, 3)
+
+	item := doc.Paths["/1.0/items/{name}"]
+	s.Require().NotNil(item)
+	s.Equal("get_1_0_things_name", doc.Paths["/1.0/things/{name}"].Get.OperationID)
+	s.Nil(item.Delete)
+
+	// Operations require a trusted client certificate unless they allow untrusted requests.
+	s.Require().NotNil(item.Get)
+	s.Equal("Get an item", item.Get.Summary)
+	s.Nil(item.Get.Security)
+	s.Equal(string(types.EntitlementConfigRead), item.Get.Entitlement)
+	s.True(item.Get.AllowedBeforeInit)
+	s.NotContains(item.Get.Responses, "503")
+	s.Equal([]openapi.Parameter{{Name: "target", In: "query", Description: "Name of the cluster member to forward the request to", Schema: &openapi.Schema{Type: "string"}}}, item.Get.Parameters)
+	s.Contains(doc.Components.Schemas, "ClusterConfig")
+
+	s.Require().NotNil(item.Put)
+	s.Equal(&[]openapi.SecurityRequirement{}, item.Put.Security)
+	s.Empty(item.Put.Entitlement)
+	s.Equal("#/components/schemas/ClusterConfig", item.Put.RequestBody.Content["application/json"].Schema.Ref)
+
+	items := doc.Paths["/1.0/items"]
+	s.Require().NotNil(items)
+	s.False(items.Get.AllowedBeforeInit)
+	s.Equal(string(types.EntitlementAPIRead), items.Get.Entitlement)
+	s.Contains(items.Get.Responses, "503")
+}
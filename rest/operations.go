@@ -0,0 +1,20 @@
+package rest
+
+import (
+	"github.com/canonical/lxd/lxd/response"
+
+	"github.com/canonical/microcluster/v2/internal/operations"
+)
+
+// Operation is a long-running action started by an endpoint handler.
+// Operations are created with the manager returned by state.State.Operations().
+type Operation = operations.Operation
+
+// OperationRunFunc is the function executed by an Operation.
+type OperationRunFunc = operations.RunFunc
+
+// OperationResponse returns an asynchronous response that points the client at the given operation.
+// Clients can follow the operation through the /core/1.0/operations API.
+func OperationResponse(op *Operation) response.Response {
+	return operations.OperationResponse(op)
+}
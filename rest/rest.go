@@ -21,6 +21,23 @@ type EndpointAction struct {
 	AccessHandler  func(state state.State, r *http.Request) (trusted bool, resp response.Response)
 	AllowUntrusted bool
 	ProxyTarget    bool // Allow forwarding of the request to a target if ?target=name is specified.
+
+	// Entitlement is required by clients that are not cluster members, unless AllowUntrusted is set.
+	// Defaults to types.DefaultEntitlement for the request method.
+	Entitlement types.Entitlement
+
+	Summary  string // Short description of the action, used in the OpenAPI document.
+	Request  any    // Value of the type of the request body, if any, used in the OpenAPI document.
+	Response any    // Value of the type of the response metadata, if any, used in the OpenAPI document.
+}
+
+// RequiredEntitlement returns the entitlement required by the action for requests with the given method.
+func (a EndpointAction) RequiredEntitlement(method string) types.Entitlement {
+	if a.Entitlement != "" {
+		return a.Entitlement
+	}
+
+	return types.DefaultEntitlement(method)
 }
 
 // Endpoint represents a URL in our API.
@@ -36,6 +53,9 @@ type Endpoint struct {
 
 	AllowedDuringShutdown bool // Whether we should return Unavailable Error (503) if daemon is shutting down.
 	AllowedBeforeInit     bool // Whether we should return Unavailabel Error (503) if the daemon has not been initialized (is not yet part of a cluster).
+
+	// Middlewares wrap the handling of every request to this endpoint, after those of the server. See Middleware for the ordering.
+	Middlewares []Middleware
 }
 
 // Resources represents all the resources served over the same path.
@@ -65,4 +85,8 @@ type Server struct {
 
 	// Resources is the list of resources offered by this server.
 	Resources []Resources
+
+	// Middlewares wrap the handling of every request to the resources of this server, before those of each endpoint.
+	// See Middleware for the ordering.
+	Middlewares []Middleware
 }
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"net/http"
+)
+
+// Entitlement is a permission to perform an action on a kind of object, in the form "<object>:<action>", for instance
+// "config:write". Either part of an entitlement granted by a role can be "*" to match any object or action, except that
+// a "*" object doesn't match the entitlements that expose secrets, which must be granted by object.
+type Entitlement string
+
+const (
+	// EntitlementAll grants every entitlement.
+	EntitlementAll Entitlement = "*"
+
+	// EntitlementAPIRead is required by GET requests to endpoint actions that do not declare an entitlement.
+	EntitlementAPIRead Entitlement = "api:read"
+
+	// EntitlementAPIWrite is required by other requests to endpoint actions that do not declare an entitlement.
+	EntitlementAPIWrite Entitlement = "api:write"
+
+	// EntitlementClusterRead allows listing cluster members and their state.
+	EntitlementClusterRead Entitlement = "cluster:read"
+
+	// EntitlementClusterWrite allows adding, updating and removing cluster members.
+	EntitlementClusterWrite Entitlement = "cluster:write"
+
+	// EntitlementConfigRead allows reading the cluster config.
+	EntitlementConfigRead Entitlement = "config:read"
+
+	// EntitlementConfigWrite allows changing the cluster config.
+	EntitlementConfigWrite Entitlement = "config:write"
+
+	// EntitlementTokensRead allows listing join tokens, including their secrets.
+	EntitlementTokensRead Entitlement = "tokens:read"
+
+	// EntitlementTokensWrite allows issuing and revoking join tokens.
+	EntitlementTokensWrite Entitlement = "tokens:write"
+
+	// EntitlementOperationsRead allows listing and waiting on operations and tasks.
+	EntitlementOperationsRead Entitlement = "operations:read"
+
+	// EntitlementOperationsWrite allows cancelling operations and starting tasks.
+	EntitlementOperationsWrite Entitlement = "operations:write"
+
+	// EntitlementLeasesRead allows listing leases.
+	EntitlementLeasesRead Entitlement = "leases:read"
+
+	// EntitlementLeasesWrite allows releasing leases.
+	EntitlementLeasesWrite Entitlement = "leases:write"
+
+	// EntitlementDatabaseRead allows running read-only SQL queries and taking database backups, which include the
+	// secrets stored in the database.
+	EntitlementDatabaseRead Entitlement = "database:read"
+
+	// EntitlementDatabaseWrite allows running any SQL query and restoring the database.
+	EntitlementDatabaseWrite Entitlement = "database:write"
+
+	// EntitlementDaemonRead allows reading the state and API description of the daemon.
+	EntitlementDaemonRead Entitlement = "daemon:read"
+
+	// EntitlementDaemonWrite allows controlling the daemon, for instance initializing or shutting it down.
+	EntitlementDaemonWrite Entitlement = "daemon:write"
+
+	// EntitlementCertificatesRead allows listing the trusted client certificates.
+	EntitlementCertificatesRead Entitlement = "certificates:read"
+
+	// EntitlementCertificatesWrite allows trusting client certificates, issuing tokens for clients to redeem, and
+	// revoking either.
+	EntitlementCertificatesWrite Entitlement = "certificates:write"
+
+	// EntitlementAuthRead allows reading the authorization policy.
+	EntitlementAuthRead Entitlement = "auth:read"
+
+	// EntitlementAuthWrite allows changing the authorization policy.
+	EntitlementAuthWrite Entitlement = "auth:write"
+)
+
+// DefaultEntitlement returns the entitlement required for the given request method by endpoint actions that do not declare one.
+func DefaultEntitlement(method string) Entitlement {
+	if method == http.MethodGet {
+		return EntitlementAPIRead
+	}
+
+	return EntitlementAPIWrite
+}
+
+// IdentityType is the kind of client that sent a request.
+type IdentityType string
+
+const (
+	// IdentityTypeClusterMember is a cluster member, identified by its certificate in the trust store.
+	// Cluster members are always granted every entitlement.
+	IdentityTypeClusterMember IdentityType = "cluster-member"
+
+	// IdentityTypeCertificate is a client identified by the fingerprint of its TLS certificate.
+	IdentityTypeCertificate IdentityType = "certificate"
+
+	// IdentityTypeUnix is a process connected to the unix socket, identified by its UID and GID.
+	// Processes running as root are always granted every entitlement.
+	IdentityTypeUnix IdentityType = "unix"
+)
+
+// Identity is the client that sent a request.
+type Identity struct {
+	// Type is the kind of client.
+	Type IdentityType `json:"type" yaml:"type"`
+
+	// Name identifies the client: the name of a cluster member, the fingerprint of a certificate, or "uid:<uid>" for a
+	// unix socket peer.
+	Name string `json:"name" yaml:"name"`
+
+	// UID is the user ID of a unix socket peer.
+	UID uint32 `json:"uid,omitempty" yaml:"uid,omitempty"`
+
+	// GID is the primary group ID of a unix socket peer.
+	GID uint32 `json:"gid,omitempty" yaml:"gid,omitempty"`
+}
+
+const (
+	// AuthRoleAdmin is a built-in role granting every entitlement.
+	AuthRoleAdmin = "admin"
+
+	// AuthRoleViewer is a built-in role granting every read entitlement that doesn't expose secrets.
+	AuthRoleViewer = "viewer"
+)
+
+// AuthBinding grants a role to the identities of a given type that match Identity.
+type AuthBinding struct {
+	// Role is the name of a built-in role or a role of the policy.
+	Role string `json:"role" yaml:"role"`
+
+	// IdentityType is the type of the identities granted the role.
+	IdentityType IdentityType `json:"identity_type" yaml:"identity_type"`
+
+	// Identity matches identities of the given type. It can be "*" for any identity, the fingerprint of a certificate,
+	// or "uid:<uid>" or "gid:<gid>" for unix socket peers.
+	Identity string `json:"identity" yaml:"identity"`
+}
+
+// AuthPolicy is the cluster-wide authorization policy, granting roles to the clients of the API.
+type AuthPolicy struct {
+	// Roles maps the names of custom roles to the entitlements they grant.
+	Roles map[string][]Entitlement `json:"roles" yaml:"roles"`
+
+	// Bindings grant roles to identities.
+	Bindings []AuthBinding `json:"bindings" yaml:"bindings"`
+}
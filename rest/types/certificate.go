@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"time"
 )
 
 // CertificateName represents the name of a certificate.
@@ -18,6 +19,18 @@ const (
 	ServerCertificateName CertificateName = "server"
 )
 
+// CertificateExpiry describes when a keypair managed by a cluster member expires.
+type CertificateExpiry struct {
+	// Name of the keypair, which is either the server or cluster certificate, or an additional listener.
+	Name CertificateName `json:"name" yaml:"name"`
+
+	// Fingerprint of the certificate.
+	Fingerprint string `json:"fingerprint" yaml:"fingerprint"`
+
+	// NotAfter is when the certificate expires.
+	NotAfter time.Time `json:"not_after" yaml:"not_after"`
+}
+
 // KeyPair holds a certificate together with its private key and optional CA.
 type KeyPair struct {
 	Cert string `json:"cert" yaml:"cert"`
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"time"
+)
+
+// CertificateRotationPhase is the phase of a rotation of the cluster certificate. Each phase must be acknowledged by
+// every cluster member before the rotation moves on to the next.
+type CertificateRotationPhase string
+
+const (
+	// CertificateRotationPrepare distributes the new cluster certificate, which cluster members trust alongside the
+	// current one.
+	CertificateRotationPrepare CertificateRotationPhase = "prepare"
+
+	// CertificateRotationSwitch has cluster members serve the new cluster certificate, while they keep trusting the
+	// previous one.
+	CertificateRotationSwitch CertificateRotationPhase = "switch"
+
+	// CertificateRotationRetire has cluster members stop trusting the previous cluster certificate, once the grace
+	// period has passed.
+	CertificateRotationRetire CertificateRotationPhase = "retire"
+
+	// CertificateRotationComplete is the phase of a rotation that every cluster member has retired.
+	CertificateRotationComplete CertificateRotationPhase = "complete"
+
+	// CertificateRotationRevert has cluster members serve the previous cluster certificate again once a rotation is
+	// aborted, while they keep trusting the new one.
+	CertificateRotationRevert CertificateRotationPhase = "revert"
+
+	// CertificateRotationDiscard has cluster members stop trusting the new cluster certificate of an aborted rotation.
+	CertificateRotationDiscard CertificateRotationPhase = "discard"
+
+	// CertificateRotationAborted is the phase of an aborted rotation that every cluster member has discarded.
+	CertificateRotationAborted CertificateRotationPhase = "aborted"
+)
+
+// Done returns whether a rotation in this phase has either completed or been aborted.
+func (p CertificateRotationPhase) Done() bool {
+	return p == CertificateRotationComplete || p == CertificateRotationAborted
+}
+
+// CertificateRotationPost starts a rotation of the cluster certificate.
+type CertificateRotationPost struct {
+	// Cert is the PEM encoded new cluster certificate.
+	Cert string `json:"cert" yaml:"cert"`
+
+	// Key is the PEM encoded private key of the new cluster certificate.
+	// It is stored in the database only until every cluster member serves the new certificate.
+	Key string `json:"key" yaml:"key"`
+
+	// GracePeriod is how long cluster members keep trusting the previous cluster certificate once they all serve the
+	// new one. Defaults to an hour if zero.
+	GracePeriod time.Duration `json:"grace_period" yaml:"grace_period"`
+}
+
+// CertificateRotation is the status of the latest rotation of the cluster certificate.
+type CertificateRotation struct {
+	// Fingerprint is the fingerprint of the new cluster certificate.
+	Fingerprint string `json:"fingerprint" yaml:"fingerprint"`
+
+	// PreviousFingerprint is the fingerprint of the cluster certificate being replaced.
+	PreviousFingerprint string `json:"previous_fingerprint" yaml:"previous_fingerprint"`
+
+	// Phase is the phase the rotation is in.
+	Phase CertificateRotationPhase `json:"phase" yaml:"phase"`
+
+	// GracePeriod is how long cluster members keep trusting the previous cluster certificate once they all serve the
+	// new one.
+	GracePeriod time.Duration `json:"grace_period" yaml:"grace_period"`
+
+	// StartedAt is when the rotation was started.
+	StartedAt time.Time `json:"started_at" yaml:"started_at"`
+
+	// SwitchedAt is when every cluster member served the new cluster certificate, or zero until then.
+	SwitchedAt time.Time `json:"switched_at" yaml:"switched_at"`
+
+	// CompletedAt is when every cluster member stopped trusting the previous cluster certificate, or zero until then.
+	CompletedAt time.Time `json:"completed_at" yaml:"completed_at"`
+
+	// Members lists the phase each cluster member has acknowledged.
+	Members []CertificateRotationMember `json:"members" yaml:"members"`
+}
+
+// CertificateRotationMember is the progress of a cluster member through a rotation of the cluster certificate.
+type CertificateRotationMember struct {
+	// Name of the cluster member.
+	Name string `json:"name" yaml:"name"`
+
+	// Phase is the latest phase acknowledged by the cluster member, or empty if none.
+	Phase CertificateRotationPhase `json:"phase" yaml:"phase"`
+}
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"time"
+)
+
+// ClientCertificate is a certificate trusted to authenticate a client that is not a cluster member.
+type ClientCertificate struct {
+	Name        string          `json:"name"        yaml:"name"`
+	Fingerprint string          `json:"fingerprint" yaml:"fingerprint"`
+	Certificate X509Certificate `json:"certificate" yaml:"certificate"`
+}
+
+// ClientCertificatesPost adds a trusted client certificate. Instead, it can request a token for a client to redeem, or
+// redeem such a token to have the certificate the request was sent with trusted.
+type ClientCertificatesPost struct {
+	// Name of the client certificate, or of the token.
+	Name string `json:"name" yaml:"name"`
+
+	// Certificate is the PEM encoded certificate to trust.
+	Certificate string `json:"certificate" yaml:"certificate"`
+
+	// Token requests a token that a client can redeem to have its certificate trusted under the given name.
+	Token bool `json:"token" yaml:"token"`
+
+	// ExpireAfter is how long the requested token remains valid. Tokens do not expire if it is zero.
+	ExpireAfter time.Duration `json:"expire_after" yaml:"expire_after"`
+
+	// TrustToken is a token to redeem, in which case the certificate the request was sent with is trusted.
+	TrustToken string `json:"trust_token" yaml:"trust_token"`
+}
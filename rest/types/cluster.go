@@ -1,6 +1,8 @@
 package types
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/canonical/microcluster/v2/internal/extensions"
@@ -10,14 +12,72 @@ import (
 type ClusterMember struct {
 	ClusterMemberLocal
 	Role                  string                `json:"role" yaml:"role"`
+	Labels                map[string]string     `json:"labels" yaml:"labels"`
+	FailureDomain         string                `json:"failure_domain" yaml:"failure_domain"`
 	SchemaInternalVersion uint64                `json:"schema_internal_version" yaml:"schema_internal_version"`
 	SchemaExternalVersion uint64                `json:"schema_external_version" yaml:"schema_external_version"`
 	LastHeartbeat         time.Time             `json:"last_heartbeat" yaml:"last_heartbeat"`
 	Status                MemberStatus          `json:"status" yaml:"status"`
 	Extensions            extensions.Extensions `json:"extensions" yaml:"extensions"`
 	Secret                string                `json:"secret" yaml:"secret"`
+
+	// Leader is whether the cluster member is the dqlite leader.
+	Leader bool `json:"leader" yaml:"leader"`
+
+	// LeaderElectedAt is when the responding cluster member learned that this cluster member became the dqlite leader.
+	// It is only set for the leader.
+	LeaderElectedAt *time.Time `json:"leader_elected_at,omitempty" yaml:"leader_elected_at,omitempty"`
+
+	// HeartbeatData is the application data contributed by the cluster member through heartbeats.
+	HeartbeatData json.RawMessage `json:"heartbeat_data,omitempty" yaml:"heartbeat_data,omitempty"`
+
+	// LastSeen is when the cluster member last answered a heartbeat. Zero if it has not answered one yet.
+	LastSeen time.Time `json:"last_seen" yaml:"last_seen"`
+
+	// HeartbeatRTT is the round-trip time of the last heartbeat answered by the cluster member.
+	HeartbeatRTT time.Duration `json:"heartbeat_rtt" yaml:"heartbeat_rtt"`
+
+	// MissedHeartbeats is the number of consecutive heartbeats the cluster member failed to answer.
+	MissedHeartbeats int `json:"missed_heartbeats" yaml:"missed_heartbeats"`
+
+	// Suspicion is the failure detector's suspicion level that the cluster member has failed.
+	// The status becomes SUSPECT and then UNREACHABLE as it crosses the configured thresholds.
+	Suspicion float64 `json:"suspicion" yaml:"suspicion"`
+
+	// Certificates lists the keypairs managed by the cluster member and when they expire, as last reported through
+	// heartbeats.
+	Certificates []CertificateExpiry `json:"certificates,omitempty" yaml:"certificates,omitempty"`
+}
+
+// ClusterMemberPut represents the configurable fields of a cluster member.
+// The failure domain is used to spread dqlite voters and stand-bys across cluster members that are unlikely to fail together.
+type ClusterMemberPut struct {
+	Labels        map[string]string `json:"labels" yaml:"labels"`
+	FailureDomain string            `json:"failure_domain" yaml:"failure_domain"`
+}
+
+// ClusterMemberPatch represents a change to the dqlite role of a cluster member.
+// Role is one of "voter", "stand-by" or "spare". Dqlite prefers other cluster members when promoting a demoted cluster
+// member, until it is restored from maintenance or its database restarts. Role changes that dqlite would revert to keep
+// its target number of voters and stand-bys are refused.
+type ClusterMemberPatch struct {
+	Role string `json:"role" yaml:"role"`
+}
+
+// ClusterMemberStatePost represents an action changing the state of a cluster member.
+// Action is one of "evacuate" or "restore".
+type ClusterMemberStatePost struct {
+	Action string `json:"action" yaml:"action"`
 }
 
+const (
+	// ClusterMemberEvacuate takes a cluster member out of service for maintenance, without removing it from the cluster.
+	ClusterMemberEvacuate = "evacuate"
+
+	// ClusterMemberRestore returns an evacuated cluster member to service.
+	ClusterMemberRestore = "restore"
+)
+
 // ClusterMemberLocal represents local information about a new cluster member.
 type ClusterMemberLocal struct {
 	Name        string          `json:"name" yaml:"name"`
@@ -46,4 +106,35 @@ const (
 
 	// MemberNeedsUpgrade should be the MemberStatus if the system needs to receive a schema upgrade to be compatible with other cluster members.
 	MemberNeedsUpgrade MemberStatus = "NEEDS UPGRADE"
+
+	// MemberSuspect should be the MemberStatus when the node's heartbeats are overdue, but not yet long enough for it to be considered unreachable.
+	MemberSuspect MemberStatus = "SUSPECT"
+
+	// MemberMaintenance should be the MemberStatus if the system has been evacuated and not yet restored.
+	MemberMaintenance MemberStatus = "MAINTENANCE"
+)
+
+const (
+	// InitConfigFailureDomain is the init config key setting the failure domain of a bootstrapping or joining cluster member.
+	InitConfigFailureDomain = "core.failure_domain"
+
+	// InitConfigLabelPrefix prefixes the init config keys setting the labels of a bootstrapping or joining cluster member.
+	InitConfigLabelPrefix = "core.label."
 )
+
+// MemberPutFromInitConfig returns the labels and failure domain set in the init config of a bootstrapping or joining cluster member.
+func MemberPutFromInitConfig(initConfig map[string]string) ClusterMemberPut {
+	put := ClusterMemberPut{
+		Labels:        map[string]string{},
+		FailureDomain: initConfig[InitConfigFailureDomain],
+	}
+
+	for key, value := range initConfig {
+		label, ok := strings.CutPrefix(key, InitConfigLabelPrefix)
+		if ok {
+			put.Labels[label] = value
+		}
+	}
+
+	return put
+}
@@ -1,8 +1,35 @@
 package types
 
+import (
+	"time"
+)
+
 // DaemonConfig is the in memory version of the local daemon.yaml file.
 type DaemonConfig struct {
 	Name    string                  `json:"name" yaml:"name"`
 	Address AddrPort                `json:"address" yaml:"address"`
 	Servers map[string]ServerConfig `json:"servers" yaml:"servers"`
+
+	// FailureDomain is a local copy of the cluster member's failure domain, which must be known before the database starts.
+	FailureDomain string `json:"failure_domain,omitempty" yaml:"failure_domain,omitempty"`
+
+	// RoleProbeInterval is a local copy of the cluster-wide dqlite role probe interval, which must be known before the database starts.
+	RoleProbeInterval time.Duration `json:"role_probe_interval,omitempty" yaml:"role_probe_interval,omitempty"`
+}
+
+// ClusterConfig holds cluster config keys and their values.
+type ClusterConfig struct {
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// ConfigChange is a change to the value of a cluster config key.
+type ConfigChange struct {
+	// Key is the name of the config key.
+	Key string `json:"key" yaml:"key"`
+
+	// Value is the new value of the key, or its default if the key was unset.
+	Value string `json:"value" yaml:"value"`
+
+	// Member is the cluster member the value applies to, for keys scoped to cluster members.
+	Member string `json:"member,omitempty" yaml:"member,omitempty"`
 }
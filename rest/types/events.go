@@ -0,0 +1,77 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType is the type of an event sent over the events stream.
+type EventType string
+
+const (
+	// EventMemberJoined is sent when a new cluster member has been added to the cluster.
+	EventMemberJoined EventType = "member-joined"
+
+	// EventMemberRemoved is sent when a cluster member has been removed from the cluster.
+	EventMemberRemoved EventType = "member-removed"
+
+	// EventMemberAutoRemoved is sent when the leader starts removing a cluster member that has missed heartbeats for too long.
+	EventMemberAutoRemoved EventType = "member-auto-removed"
+
+	// EventRoleChanged is sent when the dqlite role of a cluster member changes.
+	EventRoleChanged EventType = "role-changed"
+
+	// EventHeartbeatMissed is sent when the leader fails to send a heartbeat to a cluster member.
+	EventHeartbeatMissed EventType = "heartbeat-missed"
+
+	// EventLeaderChanged is sent when a different cluster member becomes the dqlite leader.
+	EventLeaderChanged EventType = "leader-changed"
+
+	// EventCertificateUpdated is sent when a certificate is replaced.
+	EventCertificateUpdated EventType = "certificate-updated"
+
+	// EventCertificateExpiring is sent when a keypair managed by a cluster member is close to expiring.
+	// Its metadata is a CertificateExpiry.
+	EventCertificateExpiring EventType = "certificate-expiring"
+
+	// EventUpgradeStateChanged is sent when the schema or API extension upgrade state of the database changes.
+	EventUpgradeStateChanged EventType = "upgrade-state-changed"
+
+	// EventDaemonConfigUpdated is sent when the local daemon configuration is updated.
+	EventDaemonConfigUpdated EventType = "daemon-config-updated"
+
+	// EventOperationUpdated is sent when an operation is created, makes progress, or completes.
+	EventOperationUpdated EventType = "operation-updated"
+)
+
+// Event represents a single event sent over the events stream.
+type Event struct {
+	Type      EventType       `json:"type" yaml:"type"`
+	Timestamp time.Time       `json:"timestamp" yaml:"timestamp"`
+	Location  string          `json:"location" yaml:"location"`
+	Metadata  json.RawMessage `json:"metadata" yaml:"metadata"`
+}
+
+// EventMember is the metadata of member related events.
+type EventMember struct {
+	Name    string `json:"name" yaml:"name"`
+	Address string `json:"address" yaml:"address"`
+	Role    string `json:"role,omitempty" yaml:"role,omitempty"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// EventLeader is the metadata of leader change events.
+type EventLeader struct {
+	OldLeader string `json:"old_leader" yaml:"old_leader"`
+	NewLeader string `json:"new_leader" yaml:"new_leader"`
+}
+
+// EventCertificate is the metadata of certificate update events.
+type EventCertificate struct {
+	Name CertificateName `json:"name" yaml:"name"`
+}
+
+// EventUpgradeState is the metadata of upgrade state events.
+type EventUpgradeState struct {
+	Status DatabaseStatus `json:"status" yaml:"status"`
+}
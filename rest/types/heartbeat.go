@@ -0,0 +1,29 @@
+package types
+
+import (
+	"time"
+)
+
+// HeartbeatConfig holds the intervals at which the leader sends heartbeats and dqlite probes cluster members to adjust their roles.
+type HeartbeatConfig struct {
+	// Interval is the time between heartbeat rounds. Changes take effect on each cluster member with the next heartbeat.
+	Interval time.Duration `json:"interval" yaml:"interval"`
+
+	// RoleProbeInterval is the time between dqlite role probes. Changes take effect when a cluster member's database next starts.
+	RoleProbeInterval time.Duration `json:"role_probe_interval" yaml:"role_probe_interval"`
+}
+
+// Heartbeat holds the cluster-wide heartbeat configuration, along with the configuration in effect on each cluster member.
+type Heartbeat struct {
+	HeartbeatConfig `yaml:",inline"`
+
+	// Members is the heartbeat configuration in effect on each reachable cluster member, keyed by name.
+	Members map[string]HeartbeatConfig `json:"members" yaml:"members"`
+
+	// Mismatched lists the cluster members whose heartbeat interval differs from the cluster-wide configuration.
+	Mismatched []string `json:"mismatched" yaml:"mismatched"`
+
+	// PendingRestart lists the cluster members whose dqlite role probe interval differs from the cluster-wide
+	// configuration. They apply it when their database next starts.
+	PendingRestart []string `json:"pending_restart" yaml:"pending_restart"`
+}
@@ -0,0 +1,14 @@
+package types
+
+import (
+	"time"
+)
+
+// Lease represents a cluster-wide lease held by a cluster member.
+type Lease struct {
+	Name      string    `json:"name" yaml:"name"`
+	Holder    string    `json:"holder" yaml:"holder"`
+	Token     int64     `json:"token" yaml:"token"`
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+	Expired   bool      `json:"expired" yaml:"expired"`
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"time"
+)
+
+// OperationStatus represents the state of an operation.
+type OperationStatus string
+
+const (
+	// OperationRunning indicates the operation is still in progress.
+	OperationRunning OperationStatus = "Running"
+
+	// OperationSuccess indicates the operation completed successfully.
+	OperationSuccess OperationStatus = "Success"
+
+	// OperationFailure indicates the operation completed with an error.
+	OperationFailure OperationStatus = "Failure"
+
+	// OperationCancelled indicates the operation was cancelled before it completed.
+	OperationCancelled OperationStatus = "Cancelled"
+)
+
+// IsFinal returns whether the operation status will no longer change.
+func (s OperationStatus) IsFinal() bool {
+	return s != OperationRunning
+}
+
+// OperationProgress represents the progress of a running operation.
+type OperationProgress struct {
+	Stage   string `json:"stage" yaml:"stage"`
+	Percent int    `json:"percent" yaml:"percent"`
+}
+
+// Operation represents a long-running action on a cluster member.
+type Operation struct {
+	ID          string             `json:"id" yaml:"id"`
+	Description string             `json:"description" yaml:"description"`
+	Location    string             `json:"location" yaml:"location"`
+	Status      OperationStatus    `json:"status" yaml:"status"`
+	CreatedAt   time.Time          `json:"created_at" yaml:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" yaml:"updated_at"`
+	Progress    *OperationProgress `json:"progress,omitempty" yaml:"progress,omitempty"`
+	Metadata    map[string]any     `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	MayCancel   bool               `json:"may_cancel" yaml:"may_cancel"`
+	Err         string             `json:"err,omitempty" yaml:"err,omitempty"`
+}
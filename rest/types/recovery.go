@@ -0,0 +1,75 @@
+package types
+
+import (
+	"time"
+)
+
+// RecoveryPushResult is the outcome of pushing the recovery tarball to a single cluster member.
+// Error is empty if the cluster member staged the tarball successfully.
+type RecoveryPushResult struct {
+	Name     string `json:"name" yaml:"name"`
+	Address  string `json:"address" yaml:"address"`
+	DqliteID uint64 `json:"dqlite_id" yaml:"dqlite_id"`
+	Error    string `json:"error" yaml:"error"`
+}
+
+// RecoveryPlan describes the changes that recovering from quorum loss with a proposed cluster configuration
+// would make on the local cluster member, without applying them.
+type RecoveryPlan struct {
+	// ValidationError is set if the proposed cluster configuration would be rejected.
+	ValidationError string `json:"validation_error" yaml:"validation_error"`
+
+	// DaemonRunning is set if the local daemon is running, in which case recovery would be rejected.
+	DaemonRunning bool `json:"daemon_running" yaml:"daemon_running"`
+
+	// LocalMember is the name of the local cluster member.
+	LocalMember string `json:"local_member" yaml:"local_member"`
+
+	// RaftLog describes the local copy of the raft log. Comparing it with the plan of the other
+	// cluster members shows which of them is the most up to date.
+	RaftLog RecoveryRaftLog `json:"raft_log" yaml:"raft_log"`
+
+	// Members lists the changes to each dqlite cluster member.
+	Members []RecoveryPlanMember `json:"members" yaml:"members"`
+
+	// TrustStoreUpdates lists the trust store entries whose address would be rewritten.
+	TrustStoreUpdates []RecoveryPlanAddressChange `json:"trust_store_updates" yaml:"trust_store_updates"`
+
+	// TrustStoreRemovals lists the trust store entries that would be removed as they are not dqlite cluster members.
+	TrustStoreRemovals []string `json:"trust_store_removals" yaml:"trust_store_removals"`
+
+	// DaemonAddress is set if the address in daemon.yaml would be rewritten.
+	DaemonAddress *RecoveryPlanAddressChange `json:"daemon_address" yaml:"daemon_address"`
+}
+
+// RecoveryRaftLog summarises the raft metadata, segments and snapshots in the local database directory.
+type RecoveryRaftLog struct {
+	Term         uint64    `json:"term" yaml:"term"`
+	VotedFor     uint64    `json:"voted_for" yaml:"voted_for"`
+	LastIndex    uint64    `json:"last_index" yaml:"last_index"`
+	OpenSegments int       `json:"open_segments" yaml:"open_segments"`
+	LastModified time.Time `json:"last_modified" yaml:"last_modified"`
+
+	// LastIndexUnknown is set if the open segments hold entries that can't be placed in the log, as all closed
+	// segments were compacted into a snapshot. LastIndex is then only a lower bound, so it can't be used to tell which
+	// cluster member is the most up to date.
+	LastIndexUnknown bool `json:"last_index_unknown" yaml:"last_index_unknown"`
+}
+
+// RecoveryPlanMember describes how recovery would change a single dqlite cluster member.
+type RecoveryPlanMember struct {
+	Name       string `json:"name" yaml:"name"`
+	DqliteID   uint64 `json:"dqlite_id" yaml:"dqlite_id"`
+	OldAddress string `json:"old_address" yaml:"old_address"`
+	NewAddress string `json:"new_address" yaml:"new_address"`
+	OldRole    string `json:"old_role" yaml:"old_role"`
+	NewRole    string `json:"new_role" yaml:"new_role"`
+	LosesVoter bool   `json:"loses_voter" yaml:"loses_voter"`
+}
+
+// RecoveryPlanAddressChange records an address that recovery would rewrite.
+type RecoveryPlanAddressChange struct {
+	Name       string `json:"name" yaml:"name"`
+	OldAddress string `json:"old_address" yaml:"old_address"`
+	NewAddress string `json:"new_address" yaml:"new_address"`
+}
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"time"
+)
+
+// TaskRunMode determines which cluster members run a scheduled task.
+type TaskRunMode string
+
+const (
+	// TaskRunLeader runs the task only on the dqlite leader.
+	TaskRunLeader TaskRunMode = "leader"
+
+	// TaskRunEveryMember runs the task on every cluster member.
+	TaskRunEveryMember TaskRunMode = "every-member"
+
+	// TaskRunRandomMember runs the task on one cluster member, chosen at random by the dqlite leader for each run.
+	TaskRunRandomMember TaskRunMode = "random-member"
+)
+
+// TaskStatus represents the state of a scheduled task on a cluster member.
+type TaskStatus struct {
+	Name       string      `json:"name" yaml:"name"`
+	Schedule   string      `json:"schedule" yaml:"schedule"`
+	RunMode    TaskRunMode `json:"run_mode" yaml:"run_mode"`
+	Running    bool        `json:"running" yaml:"running"`
+	NextRun    time.Time   `json:"next_run" yaml:"next_run"`
+	LastRun    time.Time   `json:"last_run" yaml:"last_run"`
+	LastMember string      `json:"last_member" yaml:"last_member"`
+	LastError  string      `json:"last_error" yaml:"last_error"`
+}
@@ -1,9 +1,43 @@
 package state
 
-import "github.com/canonical/microcluster/v2/internal/state"
+import (
+	"github.com/canonical/microcluster/v2/internal/config"
+	"github.com/canonical/microcluster/v2/internal/health"
+	"github.com/canonical/microcluster/v2/internal/state"
+	"github.com/canonical/microcluster/v2/internal/tasks"
+)
 
 // State exposes the internal daemon state for use with extended API handlers.
 type State = state.State
 
 // Hooks exposes the Hooks struct to be imported by the upstream project.
 type Hooks = state.Hooks
+
+// AutoRemovePolicy exposes the AutoRemovePolicy struct to be imported by the upstream project.
+type AutoRemovePolicy = state.AutoRemovePolicy
+
+// FailureDetectorConfig exposes the failure detector Config struct to be imported by the upstream project.
+type FailureDetectorConfig = health.Config
+
+// ConfigKey exposes the config Key struct to be imported by the upstream project.
+type ConfigKey = config.Key
+
+// Config key types and scopes.
+const (
+	ConfigTypeString   = config.KeyTypeString
+	ConfigTypeInt      = config.KeyTypeInt
+	ConfigTypeBool     = config.KeyTypeBool
+	ConfigTypeDuration = config.KeyTypeDuration
+
+	ConfigScopeCluster = config.KeyScopeCluster
+	ConfigScopeMember  = config.KeyScopeMember
+)
+
+// Task exposes the Task struct to be imported by the upstream project.
+type Task = tasks.Task
+
+// CertificateIssuer exposes the CertificateIssuer interface to be implemented by the upstream project.
+type CertificateIssuer = state.CertificateIssuer
+
+// CertificateRequest exposes the CertificateRequest struct to be imported by the upstream project.
+type CertificateRequest = state.CertificateRequest